@@ -2,14 +2,25 @@ package main
 
 import (
 	"boucherie-api/configs"
+	"boucherie-api/internal/auth"
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/escpos"
 	"boucherie-api/internal/handler"
 	mw "boucherie-api/internal/middleware"
+	"boucherie-api/internal/migrate"
+	"boucherie-api/internal/receipt"
 	"boucherie-api/internal/repository"
+	"boucherie-api/internal/scheduler"
 	"boucherie-api/internal/service"
+	"boucherie-api/migrations"
+	"context"
+	"crypto/rand"
 	"database/sql"
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
+	"text/tabwriter"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -29,7 +40,7 @@ func main() {
 	log.Info().Int("port", cfg.Port).Str("db", cfg.DBPath).Msg("configuration loaded")
 
 	// ── Database ────────────────────────────────────────
-	db, err := sql.Open("sqlite", cfg.DBPath+"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)")
+	db, err := sql.Open("sqlite", cfg.DBPath+"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate&_time_format=sqlite")
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to open database")
 	}
@@ -39,6 +50,14 @@ func main() {
 		log.Fatal().Err(err).Msg("failed to ping database")
 	}
 
+	// `migrate status|up|down [n]` manages the schema without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(db, os.Args[2:]); err != nil {
+			log.Fatal().Err(err).Msg("migrate")
+		}
+		return
+	}
+
 	// Run schema migrations
 	if err := runMigrations(db); err != nil {
 		log.Fatal().Err(err).Msg("failed to run migrations")
@@ -58,21 +77,118 @@ func main() {
 	saleRepo := repository.NewSaleRepo(db)
 	creditRepo := repository.NewCreditRepo(db)
 	orderRepo := repository.NewOrderRepo(db)
+	recurringRepo := repository.NewRecurringOrderRepo(db)
+	stockRepo := repository.NewStockRepo(db)
+	lotRepo := repository.NewLotRepo(db)
+	carcassRepo := repository.NewCarcassRepo(db)
+	supplierRepo := repository.NewSupplierRepo(db)
+	receptionRepo := repository.NewReceptionRepo(db)
+	supplierInvoiceRepo := repository.NewSupplierInvoiceRepo(db)
+	refundRepo := repository.NewRefundRepo(db)
+	userRepo := repository.NewUserRepo(db)
+	auditRepo := repository.NewAuditRepo(db)
+	statementRepo := repository.NewStatementRepo(db)
+	txManager := repository.NewTxManager(db)
 
 	// ── Services ────────────────────────────────────────
-	clientSvc := service.NewClientService(clientRepo)
-	productSvc := service.NewProductService(productRepo)
-	saleSvc := service.NewSaleService(saleRepo, productRepo, clientRepo, creditRepo)
-	creditSvc := service.NewCreditService(creditRepo, clientRepo)
-	orderSvc := service.NewOrderService(orderRepo, clientRepo, productRepo)
+	secret := []byte(cfg.AuthSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatal().Err(err).Msg("failed to generate auth secret")
+		}
+		log.Warn().Msg("AUTH_SECRET not set: using a random secret, tokens will not survive a restart")
+	}
+	userSvc := service.NewUserService(userRepo, auth.NewTokens(secret, cfg.AuthTokenTTL))
+	auditSvc := service.NewAuditService(auditRepo)
+	clientSvc := service.NewClientService(clientRepo, txManager, auditSvc)
+	productSvc := service.NewProductService(productRepo, stockRepo, txManager, auditSvc)
+	stockSvc := service.NewStockService(stockRepo, lotRepo, productRepo, txManager, auditSvc)
+	carcassSvc := service.NewCarcassService(carcassRepo, productRepo, stockSvc, txManager, auditSvc)
+	supplierSvc := service.NewSupplierService(supplierRepo, receptionRepo, productRepo, carcassSvc, stockSvc, txManager, auditSvc)
+	supplierTerms := domain.SupplierTerms{DefaultDays: cfg.SupplierTermDays}
+	supplierInvoiceSvc := service.NewSupplierInvoiceService(supplierInvoiceRepo, supplierRepo, receptionRepo, txManager, auditSvc, supplierTerms, cfg.PaymentOwnerThreshold)
+	terms := domain.PaymentTerms{DefaultDays: cfg.CreditTermDays}
+	limits := domain.CreditLimits{Default: cfg.DefaultCreditLimit}
+	saleSvc := service.NewSaleService(saleRepo, productRepo, clientRepo, creditRepo, refundRepo, txManager, auditSvc, terms, limits, userSvc, stockSvc)
+	creditSvc := service.NewCreditService(creditRepo, clientRepo, txManager, auditSvc, terms, cfg.PaymentOwnerThreshold)
+	statementSvc := service.NewStatementService(clientRepo, statementRepo)
+	reconcileSvc := service.NewReconciliationService(clientRepo, creditRepo, txManager, auditSvc)
+	deposits := domain.DepositPolicy{RefundBeforeHours: cfg.DepositRefundHours}
+	orderSvc := service.NewOrderService(orderRepo, clientRepo, productRepo, stockRepo, stockSvc, txManager, auditSvc, saleSvc, deposits, cfg.OrderCapacity)
+	recurringSvc := service.NewRecurringOrderService(recurringRepo, orderRepo, clientRepo, productRepo, orderSvc, txManager, auditSvc, cfg.RecurringOrderDaysAhead)
+
+	password, err := userSvc.EnsureOwner(context.Background(), cfg.OwnerUsername, cfg.OwnerPassword)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create owner account")
+	}
+	if password != "" && cfg.OwnerPassword == "" {
+		// Shown once on the terminal only, never in the structured logs.
+		fmt.Fprintf(os.Stderr, "\nOwner account %q created with password: %s\nChange it after the first login.\n\n", cfg.OwnerUsername, password)
+		log.Warn().Str("username", cfg.OwnerUsername).Msg("owner account created with a generated password, change it")
+	} else if password != "" {
+		log.Info().Str("username", cfg.OwnerUsername).Msg("owner account created")
+	}
+
+	// ── Background jobs ─────────────────────────────────
+	scheduler.Every(context.Background(), "overdue-credits", cfg.OverdueCheckInterval, func(ctx context.Context) error {
+		n, err := creditSvc.RefreshOverdue(ctx, time.Now())
+		if n > 0 {
+			log.Info().Int("credits", n).Msg("credit statuses refreshed")
+		}
+		if err != nil {
+			return err
+		}
+		n, err = supplierInvoiceSvc.RefreshOverdue(ctx, time.Now())
+		if n > 0 {
+			log.Info().Int("invoices", n).Msg("supplier invoice statuses refreshed")
+		}
+		return err
+	})
+	// The first run doubles as the startup balance check
+	scheduler.Every(context.Background(), "reconcile-balances", cfg.ReconcileInterval, func(ctx context.Context) error {
+		report, err := reconcileSvc.Reconcile(ctx, !cfg.ReconcileRepair)
+		if err != nil {
+			return err
+		}
+		for _, d := range report.Discrepancies {
+			log.Warn().Str("client", d.ClientID).Stringer("recorded", d.Recorded).Stringer("computed", d.Computed).
+				Bool("repaired", !report.DryRun).Msg("client balance drift")
+		}
+		return nil
+	})
+
+	scheduler.Every(context.Background(), "recurring-orders", cfg.RecurringOrderInterval, func(ctx context.Context) error {
+		report, err := recurringSvc.Materialize(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		if report.Created > 0 {
+			log.Info().Int("orders", report.Created).Msg("recurring orders created")
+		}
+		for _, f := range report.Failures {
+			log.Warn().Str("recurringOrder", f.RecurringOrderID).Str("date", f.Date).Str("error", f.Error).
+				Msg("recurring order not created")
+		}
+		return nil
+	})
 
 	// ── Handlers ────────────────────────────────────────
-	clientH := handler.NewClientHandler(clientSvc)
-	productH := handler.NewProductHandler(productSvc)
-	saleH := handler.NewSaleHandler(saleSvc)
+	shop := receipt.Shop{Name: cfg.ShopName, Address: cfg.ShopAddress, Phone: cfg.ShopPhone}
+	clientH := handler.NewClientHandler(clientSvc, creditSvc, statementSvc, shop)
+	productH := handler.NewProductHandler(productSvc, stockSvc)
+	carcassH := handler.NewCarcassHandler(carcassSvc)
+	lotH := handler.NewLotHandler(stockSvc)
+	supplierH := handler.NewSupplierHandler(supplierSvc, supplierInvoiceSvc)
+	printer := escpos.NewPrinter(cfg.PrinterTarget)
+	saleH := handler.NewSaleHandler(saleSvc, shop, printer)
 	creditH := handler.NewCreditHandler(creditSvc)
-	orderH := handler.NewOrderHandler(orderSvc)
+	orderH := handler.NewOrderHandler(orderSvc, printer)
+	recurringH := handler.NewRecurringOrderHandler(recurringSvc)
 	dashboardH := handler.NewDashboardHandler(db)
+	userH := handler.NewUserHandler(userSvc)
+	auditH := handler.NewAuditHandler(auditSvc)
+	adminH := handler.NewAdminHandler(reconcileSvc)
 
 	// ── Router ──────────────────────────────────────────
 	r := chi.NewRouter()
@@ -82,6 +198,7 @@ func main() {
 	r.Use(mw.Logger)
 	r.Use(chimw.Recoverer)
 	r.Use(chimw.RequestID)
+	r.Use(mw.RequestContext)
 	r.Use(chimw.RealIP)
 
 	// Health check
@@ -91,12 +208,26 @@ func main() {
 
 	// API v1
 	r.Route("/api/v1", func(r chi.Router) {
-		r.Handle("/dashboard", dashboardH)
-		r.Mount("/clients", clientH.Routes())
-		r.Mount("/products", productH.Routes())
-		r.Mount("/sales", saleH.Routes())
-		r.Mount("/credits", creditH.Routes())
-		r.Mount("/orders", orderH.Routes())
+		r.Mount("/auth", userH.AuthRoutes())
+
+		r.Group(func(r chi.Router) {
+			r.Use(mw.Authenticate(userSvc))
+			r.With(mw.RequireRole(domain.RoleOwner, domain.RoleCashier)).Handle("/dashboard", dashboardH)
+			r.Mount("/users", userH.Routes())
+			r.Mount("/clients", clientH.Routes())
+			r.Mount("/products", productH.Routes())
+			r.Mount("/inventory", productH.InventoryRoutes())
+			r.Mount("/carcasses", carcassH.Routes())
+			r.Mount("/lots", lotH.Routes())
+			r.Mount("/suppliers", supplierH.Routes())
+			r.Mount("/supplier-invoices", supplierH.InvoiceRoutes())
+			r.Mount("/sales", saleH.Routes())
+			r.Mount("/credits", creditH.Routes())
+			r.Mount("/orders", orderH.Routes())
+			r.Mount("/recurring-orders", recurringH.Routes())
+			r.Mount("/audit", auditH.Routes())
+			r.Mount("/admin", adminH.Routes())
+		})
 	})
 
 	// ── Start ───────────────────────────────────────────
@@ -108,22 +239,86 @@ func main() {
 	}
 }
 
-// runMigrations executes the schema.sql file to set up tables.
+// runMigrations applies every pending embedded migration.
 func runMigrations(db *sql.DB) error {
-	schema, err := os.ReadFile("migrations/schema.sql")
+	m, err := migrate.New(db, migrations.FS)
 	if err != nil {
-		return fmt.Errorf("reading schema: %w", err)
+		return fmt.Errorf("loading migrations: %w", err)
 	}
-	_, err = db.Exec(string(schema))
+	applied, err := m.Up(context.Background())
+	for _, mig := range applied {
+		log.Info().Int("version", mig.Version).Str("name", mig.Name).Msg("migration applied")
+	}
+	return err
+}
+
+// runMigrateCommand implements `migrate status|up|down [n]`.
+func runMigrateCommand(db *sql.DB, args []string) error {
+	m, err := migrate.New(db, migrations.FS)
 	if err != nil {
-		return fmt.Errorf("executing schema: %w", err)
+		return fmt.Errorf("loading migrations: %w", err)
+	}
+	ctx := context.Background()
+
+	cmd := "status"
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+	switch cmd {
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		tracked, err := m.Tracked(ctx)
+		if err != nil {
+			return err
+		}
+		if !tracked {
+			fmt.Println("schema_migrations table missing: `migrate up` will create it")
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, st := range statuses {
+			state, at := "pending", ""
+			if st.Applied {
+				state, at = "applied", st.AppliedAt.Format(time.RFC3339)
+			}
+			if st.Legacy {
+				state = "legacy"
+			}
+			if st.Modified {
+				state = "modified"
+			}
+			fmt.Fprintf(tw, "%04d\t%s\t%s\t%s\n", st.Version, st.Name, state, at)
+		}
+		return tw.Flush()
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, mig := range applied {
+			fmt.Printf("applied  %04d_%s\n", mig.Version, mig.Name)
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid step count %q", args[1])
+			}
+		}
+		reverted, err := m.Down(ctx, steps)
+		for _, mig := range reverted {
+			fmt.Printf("reverted %04d_%s\n", mig.Version, mig.Name)
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown migrate command %q (expected status, up or down)", cmd)
 	}
-	return nil
 }
 
-// runSeed loads seed data from migrations/seed.sql (idempotent).
+// runSeed loads the embedded seed data (idempotent).
 func runSeed(db *sql.DB) error {
-	seed, err := os.ReadFile("migrations/seed.sql")
+	seed, err := migrations.FS.ReadFile("seed.sql")
 	if err != nil {
 		return fmt.Errorf("reading seed: %w", err)
 	}
@@ -1,14 +1,73 @@
 package configs
 
 import (
+	"boucherie-api/internal/domain"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds all application configuration loaded from environment variables.
 type Config struct {
 	Port   int
 	DBPath string
+
+	// Shop details printed on receipts
+	ShopName    string
+	ShopAddress string
+	ShopPhone   string
+
+	// PrinterTarget designates the ESC/POS printer: "tcp://host:port" or a
+	// device file path. Empty disables printing.
+	PrinterTarget string
+
+	// AuthSecret signs access tokens. When empty a random secret is used and
+	// tokens do not survive a restart.
+	AuthSecret   string
+	AuthTokenTTL time.Duration
+
+	// First owner account, created when the users table is empty. An empty
+	// password is generated and printed once on stderr, outside the logs.
+	OwnerUsername string
+	OwnerPassword string
+
+	// PaymentOwnerThreshold is the payment amount, from a client or to a
+	// supplier, above which only the owner may record it. Zero disables the
+	// check.
+	PaymentOwnerThreshold domain.Money
+
+	// CreditTermDays is the default number of days a client has to settle a
+	// credit. Clients may have their own term.
+	CreditTermDays int
+	// DefaultCreditLimit caps what a client may owe unless they have their
+	// own limit. Zero means no shop-wide limit.
+	DefaultCreditLimit domain.Money
+	// OverdueCheckInterval is how often open credits are checked for being
+	// past due.
+	OverdueCheckInterval time.Duration
+
+	// ReconcileInterval is how often client balances are checked against
+	// their credits, besides the check made at startup. ReconcileRepair lets
+	// the scheduled check fix the balances it finds drifted.
+	ReconcileInterval time.Duration
+	ReconcileRepair   bool
+
+	// DepositRefundHours is how long before pickup an order must be cancelled
+	// for its deposit to be refunded; later cancellations keep the deposit.
+	DepositRefundHours int
+
+	// OrderCapacity limits the orders taken per pickup day.
+	OrderCapacity domain.OrderCapacity
+
+	// RecurringOrderDaysAhead is how many days before pickup orders are
+	// created from recurring orders, checked every RecurringOrderInterval.
+	RecurringOrderDaysAhead int
+	RecurringOrderInterval  time.Duration
+
+	// SupplierTermDays is the default number of days the shop has to pay a
+	// supplier invoice. Suppliers may have their own term.
+	SupplierTermDays int
 }
 
 // Load reads configuration from environment variables with sensible defaults.
@@ -25,8 +84,168 @@ func Load() *Config {
 		dbPath = v
 	}
 
+	shopName := "Boucherie"
+	if v := os.Getenv("SHOP_NAME"); v != "" {
+		shopName = v
+	}
+
+	tokenTTL := 12 * time.Hour
+	if v := os.Getenv("AUTH_TOKEN_TTL"); v != "" {
+		if h, err := strconv.Atoi(v); err == nil && h > 0 {
+			tokenTTL = time.Duration(h) * time.Hour
+		}
+	}
+
+	ownerUsername := "admin"
+	if v := os.Getenv("OWNER_USERNAME"); v != "" {
+		ownerUsername = v
+	}
+
+	var paymentThreshold domain.Money
+	if v := os.Getenv("PAYMENT_OWNER_THRESHOLD"); v != "" {
+		if m, err := domain.ParseMoney(v); err == nil && m > 0 {
+			paymentThreshold = m
+		}
+	}
+
+	creditTermDays := 30
+	if v := os.Getenv("CREDIT_TERM_DAYS"); v != "" {
+		if d, err := strconv.Atoi(v); err == nil && d >= 0 {
+			creditTermDays = d
+		}
+	}
+
+	supplierTermDays := 30
+	if v := os.Getenv("SUPPLIER_TERM_DAYS"); v != "" {
+		if d, err := strconv.Atoi(v); err == nil && d >= 0 {
+			supplierTermDays = d
+		}
+	}
+
+	var creditLimit domain.Money
+	if v := os.Getenv("DEFAULT_CREDIT_LIMIT"); v != "" {
+		if m, err := domain.ParseMoney(v); err == nil && m > 0 {
+			creditLimit = m
+		}
+	}
+
+	overdueInterval := time.Hour
+	if v := os.Getenv("OVERDUE_CHECK_MINUTES"); v != "" {
+		if m, err := strconv.Atoi(v); err == nil && m > 0 {
+			overdueInterval = time.Duration(m) * time.Minute
+		}
+	}
+
+	reconcileInterval := 24 * time.Hour
+	if v := os.Getenv("RECONCILE_INTERVAL_MINUTES"); v != "" {
+		if m, err := strconv.Atoi(v); err == nil && m > 0 {
+			reconcileInterval = time.Duration(m) * time.Minute
+		}
+	}
+	reconcileRepair, _ := strconv.ParseBool(os.Getenv("RECONCILE_REPAIR"))
+
+	depositRefundHours := 48
+	if v := os.Getenv("DEPOSIT_REFUND_HOURS"); v != "" {
+		if h, err := strconv.Atoi(v); err == nil && h >= 0 {
+			depositRefundHours = h
+		}
+	}
+
+	capacity := domain.OrderCapacity{
+		MaxKgPerCategory: parseCategoryKg(os.Getenv("ORDER_MAX_KG_PER_CATEGORY")),
+	}
+	if v := os.Getenv("ORDER_MAX_PER_DAY"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			capacity.MaxOrdersPerDay = n
+		}
+	}
+	if v := os.Getenv("ORDER_CUTOFF_HOURS"); v != "" {
+		if h, err := strconv.Atoi(v); err == nil && h > 0 {
+			capacity.CutoffHours = h
+		}
+	}
+	capacity.ClosedWeekdays, capacity.ClosedDates = parseClosedDays(os.Getenv("ORDER_CLOSED_DAYS"))
+
+	recurringDaysAhead := 7
+	if v := os.Getenv("RECURRING_ORDER_DAYS_AHEAD"); v != "" {
+		if d, err := strconv.Atoi(v); err == nil && d > 0 {
+			recurringDaysAhead = d
+		}
+	}
+
+	recurringInterval := time.Hour
+	if v := os.Getenv("RECURRING_ORDER_INTERVAL_MINUTES"); v != "" {
+		if m, err := strconv.Atoi(v); err == nil && m > 0 {
+			recurringInterval = time.Duration(m) * time.Minute
+		}
+	}
+
 	return &Config{
-		Port:   port,
-		DBPath: dbPath,
+		Port:        port,
+		DBPath:      dbPath,
+		ShopName:    shopName,
+		ShopAddress: os.Getenv("SHOP_ADDRESS"),
+		ShopPhone:   os.Getenv("SHOP_PHONE"),
+
+		PrinterTarget: os.Getenv("PRINTER_TARGET"),
+
+		AuthSecret:            os.Getenv("AUTH_SECRET"),
+		AuthTokenTTL:          tokenTTL,
+		OwnerUsername:         ownerUsername,
+		OwnerPassword:         os.Getenv("OWNER_PASSWORD"),
+		PaymentOwnerThreshold: paymentThreshold,
+
+		CreditTermDays:       creditTermDays,
+		DefaultCreditLimit:   creditLimit,
+		OverdueCheckInterval: overdueInterval,
+
+		ReconcileInterval: reconcileInterval,
+		ReconcileRepair:   reconcileRepair,
+
+		DepositRefundHours: depositRefundHours,
+		OrderCapacity:      capacity,
+
+		RecurringOrderDaysAhead: recurringDaysAhead,
+		RecurringOrderInterval:  recurringInterval,
+
+		SupplierTermDays: supplierTermDays,
+	}
+}
+
+// parseCategoryKg reads "agneau=300,boeuf=150" into a weight cap per category.
+func parseCategoryKg(v string) map[domain.MeatCategory]float64 {
+	caps := make(map[domain.MeatCategory]float64)
+	for _, part := range strings.Split(v, ",") {
+		name, kg, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		if f, err := strconv.ParseFloat(strings.TrimSpace(kg), 64); err == nil && f > 0 {
+			caps[domain.MeatCategory(strings.ToLower(strings.TrimSpace(name)))] = f
+		}
+	}
+	return caps
+}
+
+// weekdays maps English and French day names to weekdays.
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+	"dimanche": time.Sunday, "lundi": time.Monday, "mardi": time.Tuesday, "mercredi": time.Wednesday,
+	"jeudi": time.Thursday, "vendredi": time.Friday, "samedi": time.Saturday,
+}
+
+// parseClosedDays reads "friday,2026-10-20" into closed weekdays and dates.
+func parseClosedDays(v string) ([]time.Weekday, []string) {
+	var days []time.Weekday
+	var dates []string
+	for _, part := range strings.Split(v, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if wd, ok := weekdays[part]; ok {
+			days = append(days, wd)
+		} else if _, err := time.Parse("2006-01-02", part); err == nil {
+			dates = append(dates, part)
+		}
 	}
+	return days, dates
 }
@@ -0,0 +1,83 @@
+// Package auth issues and verifies signed access tokens and hashes passwords.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrInvalidToken is returned for malformed, forged or expired tokens.
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims is the payload carried by an access token.
+type Claims struct {
+	UserID    string `json:"sub"`
+	Role      string `json:"role"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Tokens signs access tokens with HMAC-SHA256.
+type Tokens struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewTokens creates a token issuer. Tokens expire after ttl.
+func NewTokens(secret []byte, ttl time.Duration) *Tokens {
+	return &Tokens{secret: secret, ttl: ttl}
+}
+
+// Issue returns a signed token for the user and its expiry time.
+func (t *Tokens) Issue(userID, role string) (string, time.Time, error) {
+	exp := time.Now().Add(t.ttl)
+	payload, err := json.Marshal(Claims{UserID: userID, Role: role, ExpiresAt: exp.Unix()})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	body := base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + t.sign(body), exp, nil
+}
+
+// Verify checks the signature and expiry of a token and returns its claims.
+func (t *Tokens) Verify(token string) (*Claims, error) {
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(t.sign(body))) {
+		return nil, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var c Claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if time.Now().Unix() >= c.ExpiresAt {
+		return nil, ErrInvalidToken
+	}
+	return &c, nil
+}
+
+func (t *Tokens) sign(body string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(body))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// HashPassword returns the bcrypt hash of password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
+// CheckPassword reports whether password matches hash.
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// AuditEntity names the kind of record an audit entry is about.
+type AuditEntity string
+
+const (
+	AuditClient          AuditEntity = "client"
+	AuditProduct         AuditEntity = "product"
+	AuditSale            AuditEntity = "sale"
+	AuditRefund          AuditEntity = "refund"
+	AuditCredit          AuditEntity = "credit"
+	AuditPayment         AuditEntity = "payment"
+	AuditOrder           AuditEntity = "order"
+	AuditDeposit         AuditEntity = "deposit"
+	AuditRecurringOrder  AuditEntity = "recurring_order"
+	AuditStockMovement   AuditEntity = "stock_movement"
+	AuditCarcass         AuditEntity = "carcass"
+	AuditSupplier        AuditEntity = "supplier"
+	AuditReception       AuditEntity = "reception"
+	AuditSupplierInvoice AuditEntity = "supplier_invoice"
+	AuditSupplierPayment AuditEntity = "supplier_payment"
+	AuditLot             AuditEntity = "lot"
+)
+
+// AuditAction is the kind of change recorded.
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
+
+// AuditEntry is one append-only record of a change made to an entity.
+// Before is empty on creation and After on deletion.
+type AuditEntry struct {
+	ID        string          `json:"id"`
+	Entity    AuditEntity     `json:"entity"`
+	EntityID  string          `json:"entityId"`
+	Action    AuditAction     `json:"action"`
+	UserID    string          `json:"userId"`
+	UserName  string          `json:"userName"`
+	RequestID string          `json:"requestId"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	Date      time.Time       `json:"date"`
+}
+
+// AuditFilter narrows an audit query. Empty fields are ignored; From and To
+// are inclusive YYYY-MM-DD dates.
+type AuditFilter struct {
+	Entity   AuditEntity
+	EntityID string
+	From     string
+	To       string
+}
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the HTTP request ID.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the HTTP request ID, or "".
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
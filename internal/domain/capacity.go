@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+// OrderCapacity limits the orders the shop takes for a pickup day. Zero
+// values disable the matching rule.
+type OrderCapacity struct {
+	// MaxOrdersPerDay caps the orders picked up on one day.
+	MaxOrdersPerDay int
+	// MaxKgPerCategory caps the weight ordered per meat category on one day.
+	MaxKgPerCategory map[MeatCategory]float64
+	// CutoffHours is how long before the start of the pickup day orders
+	// must be taken.
+	CutoffHours int
+	// ClosedWeekdays and ClosedDates (YYYY-MM-DD) are days without pickups.
+	ClosedWeekdays []time.Weekday
+	ClosedDates    []string
+}
+
+// ClosedOn reports whether the shop takes no pickups on day.
+func (c OrderCapacity) ClosedOn(day time.Time) bool {
+	for _, wd := range c.ClosedWeekdays {
+		if day.Weekday() == wd {
+			return true
+		}
+	}
+	date := day.Format("2006-01-02")
+	for _, d := range c.ClosedDates {
+		if d == date {
+			return true
+		}
+	}
+	return false
+}
+
+// Cutoff returns the time after which orders for day are no longer taken.
+// day is a pickup date at local midnight, so the cut-off follows the shop's
+// clock rather than UTC.
+func (c OrderCapacity) Cutoff(day time.Time) time.Time {
+	return day.Add(-time.Duration(c.CutoffHours) * time.Hour)
+}
+
+// Reasons a pickup day cannot take an order.
+const (
+	SlotClosed       = "closed"        // the shop is closed that day
+	SlotCutoff       = "cutoff"        // too late to order for that day
+	SlotFull         = "full"          // the day has its maximum of orders
+	SlotCategoryFull = "category_full" // a category has its maximum weight
+)
+
+// CategoryLoad is the weight ordered in a category for a pickup day.
+// MaxKg is zero when the category is not capped.
+type CategoryLoad struct {
+	Category    MeatCategory `json:"category"`
+	Kg          float64      `json:"kg"`
+	MaxKg       float64      `json:"maxKg,omitempty"`
+	RemainingKg float64      `json:"remainingKg,omitempty"`
+}
+
+// PickupSlot tells whether orders can still be taken for Date (YYYY-MM-DD)
+// and how much of its capacity is booked. Cancelled orders are left out.
+type PickupSlot struct {
+	Date            string         `json:"date"`
+	Available       bool           `json:"available"`
+	Reason          string         `json:"reason,omitempty"`
+	Orders          int            `json:"orders"`
+	MaxOrders       int            `json:"maxOrders,omitempty"`
+	RemainingOrders int            `json:"remainingOrders,omitempty"`
+	Categories      []CategoryLoad `json:"categories"`
+}
@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// CarcassStatus tells whether a carcass has been cut up yet.
+type CarcassStatus string
+
+const (
+	CarcassRecue    CarcassStatus = "recue"    // received, not cut up yet
+	CarcassDecoupee CarcassStatus = "decoupee" // cut into products
+)
+
+// CarcassCut is a catalog product produced by cutting up a carcass. The
+// carcass cost is shared between its cuts in proportion to their selling
+// value (kg × price), so prime cuts carry more of it than stewing meat.
+type CarcassCut struct {
+	ID          string  `json:"id"`
+	CarcassID   string  `json:"carcassId"`
+	ProductID   string  `json:"productId"`
+	ProductName string  `json:"productName"`
+	LotID       string  `json:"lotId,omitempty"`
+	Quantity    float64 `json:"quantity"`   // in kg
+	PricePerKg  Money   `json:"pricePerKg"` // selling price when cut
+	Cost        Money   `json:"cost"`       // share of the carcass cost
+	CostPerKg   Money   `json:"costPerKg"`
+	YieldPct    float64 `json:"yieldPct"` // share of the carcass weight
+}
+
+// Carcass is a whole animal or primal cut bought to be cut up (découpe):
+// a lamb, a beef quarter. Once broken down, the weight is split between the
+// cuts, the waste and bones, and what was lost on the block.
+type Carcass struct {
+	ID          string        `json:"id"`
+	Category    MeatCategory  `json:"category"`
+	Description string        `json:"description,omitempty"`
+	Supplier    string        `json:"supplier,omitempty"`
+	SupplierID  string        `json:"supplierId,omitempty"` // set when received from a supplier
+	Weight      float64       `json:"weight"`               // in kg, as received
+	Cost        Money         `json:"cost"`
+	CostPerKg   Money         `json:"costPerKg"` // purchase cost of the whole weight
+	Status      CarcassStatus `json:"status"`
+	Cuts        []CarcassCut  `json:"cuts"`
+	WasteKg     float64       `json:"wasteKg"` // bones, fat and trimmings
+	// Computed from the cuts: CutKg is the sellable weight, LossKg what is
+	// neither a cut nor declared waste.
+	CutKg        float64    `json:"cutKg"`
+	LossKg       float64    `json:"lossKg"`
+	YieldPct     float64    `json:"yieldPct"`
+	WastePct     float64    `json:"wastePct"`
+	UserID       string     `json:"userId,omitempty"`
+	UserName     string     `json:"userName,omitempty"`
+	ReceivedAt   time.Time  `json:"receivedAt"`
+	BrokenDownAt *time.Time `json:"brokenDownAt,omitempty"`
+}
+
+// Compute derives the weights, yields and costs per kg from the cuts.
+func (c *Carcass) Compute() {
+	c.CostPerKg = c.Cost.Prorate(1000, Grams(c.Weight))
+	c.CutKg = 0
+	for i := range c.Cuts {
+		cut := &c.Cuts[i]
+		c.CutKg += cut.Quantity
+		cut.CostPerKg = cut.Cost.Prorate(1000, Grams(cut.Quantity))
+		cut.YieldPct = percent(cut.Quantity, c.Weight)
+	}
+	c.CutKg = RoundKg(c.CutKg)
+	if c.Status == CarcassDecoupee {
+		c.LossKg = RoundKg(c.Weight - c.CutKg - c.WasteKg)
+	}
+	c.YieldPct = percent(c.CutKg, c.Weight)
+	c.WastePct = percent(c.WasteKg, c.Weight)
+}
+
+// percent returns part / whole as a percentage rounded to 0.1.
+func percent(part, whole float64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(part/whole*1000) / 10
+}
+
+// CarcassFilter narrows carcass listings. From and To are inclusive
+// YYYY-MM-DD reception dates.
+type CarcassFilter struct {
+	Category   MeatCategory
+	SupplierID string
+	From       string
+	To         string
+}
+
+// CreateCarcassRequest registers a carcass reception.
+type CreateCarcassRequest struct {
+	Category    MeatCategory `json:"category" validate:"required"`
+	Description string       `json:"description,omitempty"`
+	Supplier    string       `json:"supplier,omitempty"`
+	SupplierID  string       `json:"-"` // set by supplier receptions only
+	Weight      float64      `json:"weight" validate:"required,gt=0"`
+	Cost        Money        `json:"cost" validate:"gte=0"`
+	ReceivedAt  string       `json:"receivedAt,omitempty"` // YYYY-MM-DD, today by default
+	Lot         *LotRequest  `json:"lot,omitempty"`
+}
+
+// CarcassCutRequest is a product produced by a breakdown.
+type CarcassCutRequest struct {
+	ProductID string  `json:"productId" validate:"required"`
+	Quantity  float64 `json:"quantity" validate:"required,gt=0"`
+}
+
+// BreakdownRequest records how a carcass was cut up.
+type BreakdownRequest struct {
+	Cuts    []CarcassCutRequest `json:"cuts" validate:"required,min=1,dive"`
+	WasteKg float64             `json:"wasteKg" validate:"gte=0"`
+}
+
+// CutYield sums up what a product yields from the carcasses of a period.
+type CutYield struct {
+	ProductID   string  `json:"productId"`
+	ProductName string  `json:"productName"`
+	Quantity    float64 `json:"quantity"` // in kg
+	Cost        Money   `json:"cost"`
+	CostPerKg   Money   `json:"costPerKg"`
+	YieldPct    float64 `json:"yieldPct"`
+}
+
+// YieldReport sums up the carcasses of a category broken down over a period.
+type YieldReport struct {
+	Category  MeatCategory `json:"category"`
+	From      string       `json:"from,omitempty"`
+	To        string       `json:"to,omitempty"`
+	Carcasses int          `json:"carcasses"`
+	Weight    float64      `json:"weight"`
+	Cost      Money        `json:"cost"`
+	CutKg     float64      `json:"cutKg"`
+	WasteKg   float64      `json:"wasteKg"`
+	YieldPct  float64      `json:"yieldPct"`
+	Cuts      []CutYield   `json:"cuts"`
+}
+
+// Compute rounds the totals and derives the yields and costs per kg.
+func (r *YieldReport) Compute() {
+	r.Weight = RoundKg(r.Weight)
+	r.CutKg = RoundKg(r.CutKg)
+	r.WasteKg = RoundKg(r.WasteKg)
+	r.YieldPct = percent(r.CutKg, r.Weight)
+	for i := range r.Cuts {
+		cut := &r.Cuts[i]
+		cut.Quantity = RoundKg(cut.Quantity)
+		cut.CostPerKg = cut.Cost.Prorate(1000, Grams(cut.Quantity))
+		cut.YieldPct = percent(cut.Quantity, r.Weight)
+	}
+}
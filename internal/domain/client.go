@@ -4,13 +4,15 @@ import "time"
 
 // Client represents a butcher shop customer.
 type Client struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name" validate:"required,min=2"`
-	Phone       string    `json:"phone" validate:"required"`
-	Email       string    `json:"email,omitempty"`
-	Avatar      string    `json:"avatar,omitempty"`
-	TotalCredit float64   `json:"totalCredit"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ID              string    `json:"id"`
+	Name            string    `json:"name" validate:"required,min=2"`
+	Phone           string    `json:"phone" validate:"required"`
+	Email           string    `json:"email,omitempty"`
+	Avatar          string    `json:"avatar,omitempty"`
+	TotalCredit     Money     `json:"totalCredit"`
+	PaymentTermDays *int      `json:"paymentTermDays,omitempty"` // overrides the shop default when set
+	CreditLimit     *Money    `json:"creditLimit,omitempty"`     // overrides the shop default when set
+	CreatedAt       time.Time `json:"createdAt"`
 }
 
 // CreateClientRequest represents the payload to create a new client.
@@ -18,6 +20,9 @@ type CreateClientRequest struct {
 	Name  string `json:"name" validate:"required,min=2"`
 	Phone string `json:"phone" validate:"required"`
 	Email string `json:"email,omitempty"`
+
+	PaymentTermDays *int   `json:"paymentTermDays,omitempty" validate:"omitempty,min=0"`
+	CreditLimit     *Money `json:"creditLimit,omitempty" validate:"omitempty,gte=0"`
 }
 
 // UpdateClientRequest represents the payload to update an existing client.
@@ -25,4 +30,36 @@ type UpdateClientRequest struct {
 	Name  *string `json:"name,omitempty" validate:"omitempty,min=2"`
 	Phone *string `json:"phone,omitempty"`
 	Email *string `json:"email,omitempty"`
+
+	// PaymentTermDays sets the client's payment term; a negative value
+	// clears the override so the shop default applies again.
+	PaymentTermDays *int `json:"paymentTermDays,omitempty"`
+	// CreditLimit sets the most the client may owe; a negative value clears
+	// the override so the shop default applies again.
+	CreditLimit *Money `json:"creditLimit,omitempty"`
+}
+
+// AnonymousClientID identifies the walk-in client used for anonymous sales.
+const AnonymousClientID = "anonymous"
+
+// CreditLimits is the policy capping how much a client may owe.
+type CreditLimits struct {
+	// Default applies to clients without their own limit. Zero means no
+	// shop-wide limit.
+	Default Money
+}
+
+// Limit returns the credit limit applying to client and whether there is one.
+// Anonymous clients are never granted credit.
+func (l CreditLimits) Limit(client *Client) (Money, bool) {
+	switch {
+	case client.ID == AnonymousClientID:
+		return 0, true
+	case client.CreditLimit != nil:
+		return *client.CreditLimit, true
+	case l.Default > 0:
+		return l.Default, true
+	default:
+		return 0, false
+	}
 }
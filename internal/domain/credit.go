@@ -21,12 +21,16 @@ const (
 )
 
 // Payment represents a single payment against a credit.
+// Payments recorded in one go share a ReceiptID.
 type Payment struct {
-	ID       string        `json:"id"`
-	CreditID string        `json:"creditId"`
-	Amount   float64       `json:"amount"`
-	Date     time.Time     `json:"date"`
-	Method   PaymentMethod `json:"method"`
+	ID        string        `json:"id"`
+	CreditID  string        `json:"creditId"`
+	ReceiptID string        `json:"receiptId"`
+	Amount    Money         `json:"amount"`
+	Date      time.Time     `json:"date"`
+	Method    PaymentMethod `json:"method"`
+	UserID    string        `json:"userId,omitempty"`
+	UserName  string        `json:"userName,omitempty"`
 }
 
 // Credit represents money owed by a client for a sale.
@@ -35,16 +39,84 @@ type Credit struct {
 	ClientID        string       `json:"clientId"`
 	ClientName      string       `json:"clientName"`
 	SaleID          string       `json:"saleId"`
-	Amount          float64      `json:"amount"`
-	RemainingAmount float64      `json:"remainingAmount"`
+	Amount          Money        `json:"amount"`
+	RemainingAmount Money        `json:"remainingAmount"`
 	Status          CreditStatus `json:"status"`
 	CreatedAt       time.Time    `json:"createdAt"`
 	DueDate         *time.Time   `json:"dueDate,omitempty"`
 	Payments        []Payment    `json:"payments"`
 }
 
+// PaymentTerms is the policy deciding when a credit falls due.
+type PaymentTerms struct {
+	// DefaultDays applies to clients without their own payment term.
+	// Zero means credits are due the day they are granted.
+	DefaultDays int
+}
+
+// Days returns the payment term in days that applies to client.
+func (t PaymentTerms) Days(client *Client) int {
+	if client != nil && client.PaymentTermDays != nil {
+		return *client.PaymentTermDays
+	}
+	return t.DefaultDays
+}
+
+// DueDate returns when a credit granted to client at from falls due.
+func (t PaymentTerms) DueDate(client *Client, from time.Time) time.Time {
+	return from.AddDate(0, 0, t.Days(client))
+}
+
+// IsOverdue reports whether the credit is still owed after its due date.
+func (c *Credit) IsOverdue(now time.Time) bool {
+	return c.RemainingAmount > 0 && c.DueDate != nil && now.After(*c.DueDate)
+}
+
+// StatusAt returns the status the credit should have at now: paid once
+// nothing remains, late while owed past its due date, ongoing otherwise.
+func (c *Credit) StatusAt(now time.Time) CreditStatus {
+	switch {
+	case c.RemainingAmount <= 0:
+		return CreditStatusPaye
+	case c.IsOverdue(now):
+		return CreditStatusEnRetard
+	default:
+		return CreditStatusEnCours
+	}
+}
+
 // CreatePaymentRequest represents the payload to register a payment on a credit.
 type CreatePaymentRequest struct {
-	Amount float64       `json:"amount" validate:"required,gt=0"`
-	Method PaymentMethod `json:"method" validate:"required"`
+	Amount Money         `json:"amount" validate:"required,gt=0"`
+	Method PaymentMethod `json:"method" validate:"required,oneof=cash carte virement"`
+}
+
+// AllocationStrategy decides in which order a payment on account settles a
+// client's open credits.
+type AllocationStrategy string
+
+const (
+	AllocateOldestFirst  AllocationStrategy = "oldest"
+	AllocateOverdueFirst AllocationStrategy = "overdue"
+)
+
+// CreateClientPaymentRequest represents a payment on account, spread over the
+// client's open credits.
+type CreateClientPaymentRequest struct {
+	Amount   Money              `json:"amount" validate:"required,gt=0"`
+	Method   PaymentMethod      `json:"method" validate:"required,oneof=cash carte virement"`
+	Strategy AllocationStrategy `json:"strategy,omitempty" validate:"omitempty,oneof=oldest overdue"`
+}
+
+// ClientPaymentReceipt summarises a payment on account: one Payment per
+// credit it settled, all sharing ReceiptID.
+type ClientPaymentReceipt struct {
+	ReceiptID   string        `json:"receiptId"`
+	ClientID    string        `json:"clientId"`
+	ClientName  string        `json:"clientName"`
+	Amount      Money         `json:"amount"`
+	Method      PaymentMethod `json:"method"`
+	Date        time.Time     `json:"date"`
+	Payments    []Payment     `json:"payments"`
+	TotalCredit Money         `json:"totalCredit"`
 }
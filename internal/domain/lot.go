@@ -0,0 +1,108 @@
+package domain
+
+import "time"
+
+// Lot is a batch of meat traced back to its animal, as sanitary rules
+// require. Products received from a supplier get a lot per reception line;
+// a carcass gets one on reception and passes it on to each of its cuts.
+type Lot struct {
+	ID string `json:"id"`
+	// Number is the lot number printed on the supplier's label.
+	Number      string `json:"number,omitempty"`
+	ProductID   string `json:"productId,omitempty"`
+	ProductName string `json:"productName,omitempty"`
+	CarcassID   string `json:"carcassId,omitempty"`
+	// ParentID is the carcass lot a cut lot comes from.
+	ParentID      string `json:"parentId,omitempty"`
+	ReceptionID   string `json:"receptionId,omitempty"`
+	SupplierID    string `json:"supplierId,omitempty"`
+	SupplierName  string `json:"supplierName,omitempty"`
+	Origin        string `json:"origin,omitempty"`        // country of birth, rearing and slaughter
+	SlaughterDate string `json:"slaughterDate,omitempty"` // YYYY-MM-DD
+	// ApprovalNumber is the slaughterhouse's sanitary approval number
+	// (numéro d'agrément), e.g. "FR 59.350.001 CE".
+	ApprovalNumber string    `json:"approvalNumber,omitempty"`
+	AnimalID       string    `json:"animalId,omitempty"` // ear tag
+	Quantity       float64   `json:"quantity"`           // in kg, as received
+	Remaining      float64   `json:"remaining"`          // in kg, not sold yet
+	ReceivedAt     time.Time `json:"receivedAt"`
+}
+
+// Trace copies onto l the traceability details of the lot it derives from.
+func (l *Lot) Trace(from *Lot) {
+	l.ParentID = from.ID
+	l.Number = from.Number
+	l.ReceptionID = from.ReceptionID
+	l.SupplierID = from.SupplierID
+	l.SupplierName = from.SupplierName
+	l.Origin = from.Origin
+	l.SlaughterDate = from.SlaughterDate
+	l.ApprovalNumber = from.ApprovalNumber
+	l.AnimalID = from.AnimalID
+}
+
+// LotRequest carries the traceability details of goods received.
+type LotRequest struct {
+	Number         string `json:"number,omitempty"`
+	Origin         string `json:"origin,omitempty"`
+	SlaughterDate  string `json:"slaughterDate,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	ApprovalNumber string `json:"approvalNumber,omitempty"`
+	AnimalID       string `json:"animalId,omitempty"`
+}
+
+// LotFilter narrows lot listings. OpenOnly keeps the lots with meat left.
+type LotFilter struct {
+	ProductID  string
+	SupplierID string
+	Number     string
+	OpenOnly   bool
+}
+
+// SaleItemLot is the part of a sale line taken from a lot.
+type SaleItemLot struct {
+	LotID     string  `json:"lotId"`
+	LotNumber string  `json:"lotNumber,omitempty"`
+	Quantity  float64 `json:"quantity"` // in kg
+}
+
+// SaleLot is a lot a sale line was taken from, for tracing a sale.
+type SaleLot struct {
+	SaleItemID  string  `json:"saleItemId"`
+	ProductID   string  `json:"productId"`
+	ProductName string  `json:"productName"`
+	Quantity    float64 `json:"quantity"` // in kg, taken from the lot
+	Lot         Lot     `json:"lot"`
+}
+
+// RecallSale is a sale line that received meat from a recalled lot.
+type RecallSale struct {
+	SaleID      string    `json:"saleId"`
+	SaleItemID  string    `json:"saleItemId"`
+	Date        time.Time `json:"date"`
+	ClientID    string    `json:"clientId"`
+	ClientName  string    `json:"clientName"`
+	ClientPhone string    `json:"clientPhone,omitempty"`
+	ProductID   string    `json:"productId"`
+	ProductName string    `json:"productName"`
+	LotID       string    `json:"lotId"`
+	Quantity    float64   `json:"quantity"` // in kg, taken from the lot
+}
+
+// RecallClient sums up what a client received from a recalled lot.
+type RecallClient struct {
+	ClientID    string  `json:"clientId"`
+	ClientName  string  `json:"clientName"`
+	ClientPhone string  `json:"clientPhone,omitempty"`
+	Quantity    float64 `json:"quantity"` // in kg
+	Sales       int     `json:"sales"`
+}
+
+// LotRecall lists everyone who received meat from a lot or from the lots
+// derived from it (the cuts of a carcass).
+type LotRecall struct {
+	Lot     Lot            `json:"lot"`
+	Derived []Lot          `json:"derived"`
+	Sold    float64        `json:"sold"` // in kg
+	Sales   []RecallSale   `json:"sales"`
+	Clients []RecallClient `json:"clients"`
+}
@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in centimes. It is stored as an integer so that
+// balances never drift, and travels as a decimal number in JSON
+// (12.5 on the wire is Money(1250)).
+//
+// Rounding rules:
+//   - decimal input with more than two digits is rounded half away from zero;
+//   - weights are counted in whole grams, and price per kg × weight is
+//     rounded half away from zero to the centime (see MulKg).
+type Money int64
+
+// ErrInvalidMoney is returned when a JSON amount cannot be parsed.
+var ErrInvalidMoney = errors.New("invalid money amount")
+
+// MoneyFromFloat converts a decimal amount to Money, rounding half away from zero.
+func MoneyFromFloat(v float64) Money {
+	return Money(math.Round(v * 100))
+}
+
+// Float64 returns the amount as a decimal number, for display only.
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// String formats the amount with two decimals, e.g. "-12.05".
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/100, v%100)
+}
+
+// MulKg returns the price of kg kilograms at m per kilogram. The weight is
+// first rounded to the gram, then the product is rounded to the centime.
+func (m Money) MulKg(kg float64) Money {
+	return m.Prorate(Grams(kg), 1000)
+}
+
+// Prorate returns m × part / whole rounded half away from zero, computed on
+// integers. It is used to split a line total when part of it is refunded.
+func (m Money) Prorate(part, whole int64) Money {
+	if whole == 0 {
+		return 0
+	}
+	return Money(divRound(int64(m)*part, whole))
+}
+
+// Grams converts a weight in kg to whole grams.
+func Grams(kg float64) int64 {
+	return int64(math.Round(kg * 1000))
+}
+
+// RoundKg rounds a weight in kg to the gram, dropping the float noise that
+// sums of weights pick up.
+func RoundKg(kg float64) float64 {
+	return float64(Grams(kg)) / 1000
+}
+
+// Min returns the smaller of two amounts.
+func (m Money) Min(o Money) Money {
+	if o < m {
+		return o
+	}
+	return m
+}
+
+// MarshalJSON encodes the amount as a decimal JSON number.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string such as "12.50".
+func (m *Money) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "null" {
+		return nil
+	}
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// ParseMoney parses a decimal string without going through float64.
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, ErrInvalidMoney
+		}
+		return MoneyFromFloat(f), nil
+	}
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg, s = true, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	intPart, frac, _ := strings.Cut(s, ".")
+	if intPart == "" && frac == "" || !digits(intPart) || !digits(frac) {
+		return 0, ErrInvalidMoney
+	}
+	units := int64(0)
+	if intPart != "" {
+		var err error
+		if units, err = strconv.ParseInt(intPart, 10, 64); err != nil {
+			return 0, ErrInvalidMoney
+		}
+	}
+
+	frac += "000"
+	cents := int64(frac[0]-'0')*10 + int64(frac[1]-'0')
+	if frac[2] >= '5' {
+		cents++
+	}
+	v := units*100 + cents
+	if neg {
+		v = -v
+	}
+	return Money(v), nil
+}
+
+// Value stores the amount as an integer number of centimes.
+func (m Money) Value() (driver.Value, error) {
+	return int64(m), nil
+}
+
+// Scan reads an integer amount of centimes. Legacy REAL values are rounded.
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+	case int64:
+		*m = Money(v)
+	case float64:
+		// A REAL column holds euros or a computed value, never centimes.
+		return fmt.Errorf("cannot scan REAL %v into Money: amounts are stored as integer centimes", v)
+	case []byte:
+		return m.scanText(string(v))
+	case string:
+		return m.scanText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+	return nil
+}
+
+func (m *Money) scanText(s string) error {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("cannot scan %q into Money: %w", s, err)
+	}
+	*m = Money(v)
+	return nil
+}
+
+// digits reports whether s contains only ASCII digits.
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// divRound divides a by b rounding half away from zero.
+func divRound(a, b int64) int64 {
+	if b < 0 {
+		a, b = -a, -b
+	}
+	if a >= 0 {
+		return (a + b/2) / b
+	}
+	return -((-a + b/2) / b)
+}
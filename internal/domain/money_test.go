@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{in: "12", want: 1200},
+		{in: "12.5", want: 1250},
+		{in: "12.50", want: 1250},
+		{in: " 0.07 ", want: 7},
+		{in: ".5", want: 50},
+		{in: "5.", want: 500},
+		{in: "+3.20", want: 320},
+		{in: "-3.20", want: -320},
+		{in: "1.005", want: 101},
+		{in: "1.004", want: 100},
+		{in: "-1.005", want: -101},
+		{in: "0.995", want: 100},
+		{in: "1.2e1", want: 1200},
+		{in: "", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: "-+5", wantErr: true},
+		{in: "+-5", wantErr: true},
+		{in: "--5", wantErr: true},
+		{in: "1.2.3", wantErr: true},
+		{in: "1,50", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "1.-5", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMoney(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMoney(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestMulKg(t *testing.T) {
+	tests := []struct {
+		price Money
+		kg    float64
+		want  Money
+	}{
+		{price: 2490, kg: 1, want: 2490},
+		{price: 2490, kg: 0.5, want: 1245},
+		// 1.2345 kg is weighed as 1235 g.
+		{price: 1000, kg: 1.2345, want: 1235},
+		{price: 1000, kg: 1.2344, want: 1234},
+		// 333 g at 15.00 = 4.995, rounded half away from zero.
+		{price: 1500, kg: 0.333, want: 500},
+		{price: -1500, kg: 0.333, want: -500},
+		{price: 1999, kg: 0, want: 0},
+	}
+	for _, tt := range tests {
+		if got := tt.price.MulKg(tt.kg); got != tt.want {
+			t.Errorf("%v.MulKg(%v) = %v, want %v", tt.price, tt.kg, got, tt.want)
+		}
+	}
+}
+
+func TestProrate(t *testing.T) {
+	tests := []struct {
+		m           Money
+		part, whole int64
+		want        Money
+	}{
+		{m: 1000, part: 1, whole: 2, want: 500},
+		{m: 1000, part: 1, whole: 3, want: 333},
+		{m: 1000, part: 2, whole: 3, want: 667},
+		{m: 5, part: 1, whole: 2, want: 3},
+		{m: -5, part: 1, whole: 2, want: -3},
+		{m: 1000, part: 0, whole: 3, want: 0},
+		{m: 1000, part: 1, whole: 0, want: 0},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Prorate(tt.part, tt.whole); got != tt.want {
+			t.Errorf("%v.Prorate(%d, %d) = %v, want %v", tt.m, tt.part, tt.whole, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	for _, m := range []Money{0, 5, 1250, -320, 123456789} {
+		b, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Money
+		if err := json.Unmarshal(b, &got); err != nil || got != m {
+			t.Errorf("round-trip of %v via %s = %v, %v", m, b, got, err)
+		}
+	}
+
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{in: `12.5`, want: 1250},
+		{in: `"12.50"`, want: 1250},
+		{in: `0.125`, want: 13},
+		{in: `"."`, wantErr: true},
+		{in: `"-+5"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		var got Money
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Unmarshal(%s) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Unmarshal(%s) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	if b, _ := json.Marshal(Money(-5)); string(b) != "-0.05" {
+		t.Errorf("Marshal(-5) = %s, want -0.05", b)
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	var m Money
+	if err := m.Scan(int64(1250)); err != nil || m != 1250 {
+		t.Errorf("Scan(int64) = %v, %v", m, err)
+	}
+	if err := m.Scan([]byte("42")); err != nil || m != 42 {
+		t.Errorf("Scan([]byte) = %v, %v", m, err)
+	}
+	if err := m.Scan(12.5); err == nil {
+		t.Error("Scan(float64) should fail: REAL values are not centimes")
+	}
+	if err := m.Scan("12.5"); err == nil {
+		t.Error("Scan of a decimal string should fail")
+	}
+}
@@ -13,15 +13,57 @@ const (
 	OrderStatusAnnulee   OrderStatus = "annulee"
 )
 
+// orderTransitions lists, for each status, the statuses an order may move to.
+// An order is prepared in order and may be cancelled until it is delivered.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusEnAttente: {OrderStatusConfirmee, OrderStatusAnnulee},
+	OrderStatusConfirmee: {OrderStatusPrete, OrderStatusAnnulee},
+	OrderStatusPrete:     {OrderStatusLivree, OrderStatusAnnulee},
+	OrderStatusLivree:    {},
+	OrderStatusAnnulee:   {},
+}
+
+// NextStatuses returns the statuses an order in status s may move to.
+func (s OrderStatus) NextStatuses() []OrderStatus {
+	return orderTransitions[s]
+}
+
+// ItemsEditable reports whether lines may still be added, changed or removed
+// on an order in status s, i.e. before it is prepared.
+func (s OrderStatus) ItemsEditable() bool {
+	return s == OrderStatusEnAttente || s == OrderStatusConfirmee
+}
+
+// CanTransitionTo reports whether an order in status s may move to next.
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, st := range orderTransitions[s] {
+		if st == next {
+			return true
+		}
+	}
+	return false
+}
+
 // OrderItem represents a single product line in an order.
 type OrderItem struct {
 	ID          string  `json:"id"`
 	OrderID     string  `json:"orderId"`
 	ProductID   string  `json:"productId"`
 	ProductName string  `json:"productName"`
-	Quantity    float64 `json:"quantity"` // in kg
+	Quantity    float64 `json:"quantity"`   // in kg
+	PricePerKg  Money   `json:"pricePerKg"` // quoted when the order was taken
+	Subtotal    Money   `json:"subtotal"`   // estimate, the weighed quantity may differ
 }
 
+// DepositOutcome records what became of an order's deposit.
+type DepositOutcome string
+
+const (
+	DepositDeduit    DepositOutcome = "deduit"    // deducted from the sale at pickup
+	DepositRembourse DepositOutcome = "rembourse" // paid back on cancellation
+	DepositConserve  DepositOutcome = "conserve"  // kept by the shop on cancellation
+)
+
 // Order represents a customer pre-order or reservation.
 type Order struct {
 	ID          string      `json:"id"`
@@ -32,7 +74,46 @@ type Order struct {
 	PickupDate  time.Time   `json:"pickupDate"`
 	Notes       string      `json:"notes,omitempty"`
 	Status      OrderStatus `json:"status"`
-	CreatedAt   time.Time   `json:"createdAt"`
+	// EstimatedTotal is the sum of the line subtotals at the quoted prices.
+	EstimatedTotal Money `json:"estimatedTotal"`
+	// Deposit is what the client paid in advance, less what was paid back.
+	Deposit        Money          `json:"deposit"`
+	DepositOutcome DepositOutcome `json:"depositOutcome,omitempty"`
+	SaleID         string         `json:"saleId,omitempty"` // sale made at pickup
+	// Warnings are returned when the order is created, they are not stored.
+	Warnings []StockWarning `json:"warnings,omitempty"`
+	// RecurringOrderID is the template this order was created from, if any.
+	RecurringOrderID string     `json:"recurringOrderId,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	ConfirmedAt      *time.Time `json:"confirmedAt,omitempty"`
+	ReadyAt          *time.Time `json:"readyAt,omitempty"`
+	DeliveredAt      *time.Time `json:"deliveredAt,omitempty"`
+	CancelledAt      *time.Time `json:"cancelledAt,omitempty"`
+}
+
+// Estimate prices every line at its quoted price and sums the estimated total.
+func (o *Order) Estimate() {
+	o.EstimatedTotal = 0
+	for i := range o.Items {
+		o.Items[i].Subtotal = o.Items[i].PricePerKg.MulKg(o.Items[i].Quantity)
+		o.EstimatedTotal += o.Items[i].Subtotal
+	}
+}
+
+// MarkStatus moves the order to status and stamps when it happened.
+// Callers check the transition is allowed first.
+func (o *Order) MarkStatus(status OrderStatus, at time.Time) {
+	o.Status = status
+	switch status {
+	case OrderStatusConfirmee:
+		o.ConfirmedAt = &at
+	case OrderStatusPrete:
+		o.ReadyAt = &at
+	case OrderStatusLivree:
+		o.DeliveredAt = &at
+	case OrderStatusAnnulee:
+		o.CancelledAt = &at
+	}
 }
 
 // CreateOrderItemRequest is used when creating an order.
@@ -49,9 +130,76 @@ type CreateOrderRequest struct {
 	Notes      string                   `json:"notes,omitempty"`
 }
 
+// UpdateOrderItemRequest changes the quantity of an order line.
+type UpdateOrderItemRequest struct {
+	Quantity float64 `json:"quantity" validate:"required,gt=0"`
+}
+
 // UpdateOrderRequest represents the payload to update an order.
+// The status only changes through the dedicated action endpoints.
 type UpdateOrderRequest struct {
-	Status     *OrderStatus `json:"status,omitempty"`
-	PickupDate *string      `json:"pickupDate,omitempty"`
-	Notes      *string      `json:"notes,omitempty"`
+	PickupDate *string `json:"pickupDate,omitempty"`
+	Notes      *string `json:"notes,omitempty"`
+}
+
+// CheckoutItemRequest is the weighed quantity handed over for an order line.
+type CheckoutItemRequest struct {
+	OrderItemID string  `json:"orderItemId" validate:"required"`
+	Quantity    float64 `json:"quantity" validate:"required,gt=0"` // in kg
+	LotID       string  `json:"lotId,omitempty"`                   // lot to take it from, oldest by default
+}
+
+// CheckoutOrderRequest turns a ready order into a sale at pickup. Order lines
+// left out are not sold.
+type CheckoutOrderRequest struct {
+	Items         []CheckoutItemRequest `json:"items" validate:"required,min=1,dive"`
+	PaidAmount    Money                 `json:"paidAmount" validate:"gte=0"`
+	Method        PaymentMethod         `json:"method,omitempty" validate:"omitempty,oneof=cash carte virement"`
+	OverrideToken string                `json:"overrideToken,omitempty"`
+}
+
+// CheckoutResult is the delivered order and the sale recorded for it.
+type CheckoutResult struct {
+	Order Order `json:"order"`
+	Sale  Sale  `json:"sale"`
+}
+
+// DepositKind tells a deposit received from one paid back.
+type DepositKind string
+
+const (
+	DepositKindAcompte       DepositKind = "acompte"
+	DepositKindRemboursement DepositKind = "remboursement"
+)
+
+// OrderDeposit is money received against an order before pickup, or paid
+// back when a cancelled order's deposit is refunded.
+type OrderDeposit struct {
+	ID       string        `json:"id"`
+	OrderID  string        `json:"orderId"`
+	Kind     DepositKind   `json:"kind"`
+	Amount   Money         `json:"amount"`
+	Method   PaymentMethod `json:"method"`
+	UserID   string        `json:"userId,omitempty"`
+	UserName string        `json:"userName,omitempty"`
+	Date     time.Time     `json:"date"`
+}
+
+// CreateDepositRequest represents the payload to record a deposit on an order.
+type CreateDepositRequest struct {
+	Amount Money         `json:"amount" validate:"gt=0"`
+	Method PaymentMethod `json:"method,omitempty" validate:"omitempty,oneof=cash carte virement"`
+}
+
+// DepositPolicy decides whether a cancelled order's deposit is paid back.
+type DepositPolicy struct {
+	// RefundBeforeHours is how long before pickup the order must be
+	// cancelled for the deposit to be refunded.
+	RefundBeforeHours int
+}
+
+// Refunds reports whether the deposit of an order picked up on pickup and
+// cancelled at at is paid back rather than kept.
+func (p DepositPolicy) Refunds(pickup, at time.Time) bool {
+	return !at.After(pickup.Add(-time.Duration(p.RefundBeforeHours) * time.Hour))
 }
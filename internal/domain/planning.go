@@ -0,0 +1,43 @@
+package domain
+
+// PlanningClient is one order's share of a product on the planning.
+type PlanningClient struct {
+	OrderID     string      `json:"orderId"`
+	ClientID    string      `json:"clientId"`
+	ClientName  string      `json:"clientName"`
+	ClientPhone string      `json:"clientPhone,omitempty"`
+	Status      OrderStatus `json:"status"`
+	Quantity    float64     `json:"quantity"` // in kg
+}
+
+// PlanningProduct is the total weight of a product to prepare for a day,
+// broken down by order.
+type PlanningProduct struct {
+	ProductID     string           `json:"productId"`
+	ProductName   string           `json:"productName"`
+	TotalQuantity float64          `json:"totalQuantity"` // in kg
+	Clients       []PlanningClient `json:"clients"`
+}
+
+// Planning is the preparation list for the orders picked up on Date
+// (YYYY-MM-DD). Cancelled orders are left out.
+type Planning struct {
+	Date       string            `json:"date"`
+	OrderCount int               `json:"orderCount"`
+	Products   []PlanningProduct `json:"products"`
+}
+
+// CalendarDay counts the orders picked up on Date (YYYY-MM-DD). Total leaves
+// out cancelled orders, ByStatus counts every status.
+type CalendarDay struct {
+	Date     string              `json:"date"`
+	Total    int                 `json:"total"`
+	ByStatus map[OrderStatus]int `json:"byStatus"`
+}
+
+// Calendar is the week of pickups from Monday From to Sunday To.
+type Calendar struct {
+	From string        `json:"from"`
+	To   string        `json:"to"`
+	Days []CalendarDay `json:"days"`
+}
@@ -21,16 +21,18 @@ type Product struct {
 	ID         string       `json:"id"`
 	Name       string       `json:"name" validate:"required,min=2"`
 	Category   MeatCategory `json:"category" validate:"required"`
-	PricePerKg float64      `json:"pricePerKg" validate:"required,gt=0"`
+	PricePerKg Money        `json:"pricePerKg" validate:"required,gt=0"`
 	Image      string       `json:"image,omitempty"`
 	InStock    bool         `json:"inStock"`
+	// Stock is filled when reading products, it is not set by clients.
+	Stock *StockSummary `json:"stock,omitempty"`
 }
 
 // CreateProductRequest represents the payload to create a product.
 type CreateProductRequest struct {
 	Name       string       `json:"name" validate:"required,min=2"`
 	Category   MeatCategory `json:"category" validate:"required"`
-	PricePerKg float64      `json:"pricePerKg" validate:"required,gt=0"`
+	PricePerKg Money        `json:"pricePerKg" validate:"required,gt=0"`
 	Image      string       `json:"image,omitempty"`
 }
 
@@ -38,7 +40,7 @@ type CreateProductRequest struct {
 type UpdateProductRequest struct {
 	Name       *string       `json:"name,omitempty" validate:"omitempty,min=2"`
 	Category   *MeatCategory `json:"category,omitempty"`
-	PricePerKg *float64      `json:"pricePerKg,omitempty" validate:"omitempty,gt=0"`
+	PricePerKg *Money        `json:"pricePerKg,omitempty" validate:"omitempty,gt=0"`
 	Image      *string       `json:"image,omitempty"`
 	InStock    *bool         `json:"inStock,omitempty"`
 }
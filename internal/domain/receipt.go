@@ -0,0 +1,8 @@
+package domain
+
+// Receipt gathers what is printed on a sale ticket.
+type Receipt struct {
+	Sale Sale `json:"sale"`
+	// ClientBalance is the client's outstanding credit once the sale was recorded.
+	ClientBalance Money `json:"clientBalance"`
+}
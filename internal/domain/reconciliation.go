@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// BalanceDiscrepancy is a client whose recorded balance differs from the sum
+// of what remains due on their credits.
+type BalanceDiscrepancy struct {
+	ClientID   string `json:"clientId"`
+	ClientName string `json:"clientName"`
+	Recorded   Money  `json:"recorded"`
+	Computed   Money  `json:"computed"`
+	Difference Money  `json:"difference"` // Recorded - Computed
+}
+
+// ReconciliationReport is the outcome of checking every client balance.
+type ReconciliationReport struct {
+	CheckedAt      time.Time            `json:"checkedAt"`
+	ClientsChecked int                  `json:"clientsChecked"`
+	DryRun         bool                 `json:"dryRun"`
+	Repaired       int                  `json:"repaired"`
+	Discrepancies  []BalanceDiscrepancy `json:"discrepancies"`
+}
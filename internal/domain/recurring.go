@@ -0,0 +1,95 @@
+package domain
+
+import "time"
+
+// RecurringOrderItem is a product line repeated on every occurrence.
+type RecurringOrderItem struct {
+	ID               string  `json:"id"`
+	RecurringOrderID string  `json:"recurringOrderId"`
+	ProductID        string  `json:"productId"`
+	ProductName      string  `json:"productName"`
+	Quantity         float64 `json:"quantity"` // in kg
+}
+
+// RecurringOrder is a template for the orders a client places every week,
+// e.g. a restaurant picking up the same cuts each Tuesday and Friday. Orders
+// are created from it ahead of each occurrence; changing the template leaves
+// the orders already created untouched.
+type RecurringOrder struct {
+	ID         string               `json:"id"`
+	ClientID   string               `json:"clientId"`
+	ClientName string               `json:"clientName"`
+	Items      []RecurringOrderItem `json:"items"`
+	Weekdays   []time.Weekday       `json:"weekdays"` // 0 = Sunday
+	StartDate  time.Time            `json:"startDate"`
+	EndDate    *time.Time           `json:"endDate,omitempty"`
+	Notes      string               `json:"notes,omitempty"`
+	Active     bool                 `json:"active"`
+	// Skips lists the occurrences (YYYY-MM-DD) that are not ordered.
+	Skips     []string  `json:"skips"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// OccursOn reports whether the template has an occurrence on day, skipped
+// or not.
+func (r *RecurringOrder) OccursOn(day time.Time) bool {
+	if day.Before(r.StartDate) || (r.EndDate != nil && day.After(*r.EndDate)) {
+		return false
+	}
+	for _, wd := range r.Weekdays {
+		if day.Weekday() == wd {
+			return true
+		}
+	}
+	return false
+}
+
+// Skipped reports whether the occurrence on date (YYYY-MM-DD) is skipped.
+func (r *RecurringOrder) Skipped(date string) bool {
+	for _, d := range r.Skips {
+		if d == date {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateRecurringOrderRequest represents the payload to create a template.
+type CreateRecurringOrderRequest struct {
+	ClientID  string                   `json:"clientId" validate:"required"`
+	Items     []CreateOrderItemRequest `json:"items" validate:"required,min=1,dive"`
+	Weekdays  []int                    `json:"weekdays" validate:"required,min=1,dive,min=0,max=6"`
+	StartDate string                   `json:"startDate" validate:"required"`
+	EndDate   string                   `json:"endDate,omitempty"`
+	Notes     string                   `json:"notes,omitempty"`
+}
+
+// UpdateRecurringOrderRequest represents the payload to change a template.
+// An empty EndDate removes the end date.
+type UpdateRecurringOrderRequest struct {
+	Items    []CreateOrderItemRequest `json:"items,omitempty" validate:"omitempty,min=1,dive"`
+	Weekdays []int                    `json:"weekdays,omitempty" validate:"omitempty,min=1,dive,min=0,max=6"`
+	EndDate  *string                  `json:"endDate,omitempty"`
+	Notes    *string                  `json:"notes,omitempty"`
+	Active   *bool                    `json:"active,omitempty"`
+}
+
+// OccurrenceRequest changes a single occurrence of a template: the order for
+// that day is created right away with these lines instead of the template's.
+type OccurrenceRequest struct {
+	Items []CreateOrderItemRequest `json:"items" validate:"required,min=1,dive"`
+	Notes *string                  `json:"notes,omitempty"`
+}
+
+// OccurrenceFailure is an occurrence the scheduler could not order.
+type OccurrenceFailure struct {
+	RecurringOrderID string `json:"recurringOrderId"`
+	Date             string `json:"date"`
+	Error            string `json:"error"`
+}
+
+// MaterializeReport sums up a scheduler run over the recurring orders.
+type MaterializeReport struct {
+	Created  int                 `json:"created"`
+	Failures []OccurrenceFailure `json:"failures"`
+}
@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// RefundKind distinguishes a full sale cancellation from a partial refund.
+type RefundKind string
+
+const (
+	RefundKindAnnulation    RefundKind = "annulation"
+	RefundKindRemboursement RefundKind = "remboursement"
+)
+
+// RefundItem represents a returned quantity of a single sale line.
+type RefundItem struct {
+	ID          string  `json:"id"`
+	RefundID    string  `json:"refundId"`
+	SaleItemID  string  `json:"saleItemId"`
+	ProductID   string  `json:"productId"`
+	ProductName string  `json:"productName"`
+	Quantity    float64 `json:"quantity"` // in kg
+	Amount      Money   `json:"amount"`
+}
+
+// Refund is the document recording a cancellation or refund of a sale.
+// Total is split between CreditAmount (removed from the linked credit)
+// and CashAmount (handed back to the client).
+type Refund struct {
+	ID           string        `json:"id"`
+	SaleID       string        `json:"saleId"`
+	ClientID     string        `json:"clientId"`
+	Kind         RefundKind    `json:"kind"`
+	Items        []RefundItem  `json:"items"`
+	Total        Money         `json:"total"`
+	CreditAmount Money         `json:"creditAmount"`
+	CashAmount   Money         `json:"cashAmount"`
+	Method       PaymentMethod `json:"method"`
+	Reason       string        `json:"reason,omitempty"`
+	Date         time.Time     `json:"date"`
+}
+
+// CreateRefundItemRequest selects a sale line and the quantity to refund.
+type CreateRefundItemRequest struct {
+	SaleItemID string  `json:"saleItemId" validate:"required"`
+	Quantity   float64 `json:"quantity" validate:"required,gt=0"`
+}
+
+// CreateRefundRequest represents the payload to partially refund a sale.
+type CreateRefundRequest struct {
+	Items  []CreateRefundItemRequest `json:"items" validate:"required,min=1,dive"`
+	Method PaymentMethod             `json:"method,omitempty" validate:"omitempty,oneof=cash carte virement"`
+	Reason string                    `json:"reason,omitempty"`
+}
+
+// CancelSaleRequest represents the payload to cancel a whole sale.
+type CancelSaleRequest struct {
+	Method PaymentMethod `json:"method,omitempty" validate:"omitempty,oneof=cash carte virement"`
+	Reason string        `json:"reason,omitempty"`
+}
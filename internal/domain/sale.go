@@ -2,6 +2,16 @@ package domain
 
 import "time"
 
+// SaleStatus reflects whether a sale has been refunded or cancelled.
+type SaleStatus string
+
+const (
+	SaleStatusValidee                 SaleStatus = "validee"
+	SaleStatusRembourseePartiellement SaleStatus = "remboursee_partiellement"
+	SaleStatusRemboursee              SaleStatus = "remboursee"
+	SaleStatusAnnulee                 SaleStatus = "annulee"
+)
+
 // SaleItem represents a single line item in a sale.
 type SaleItem struct {
 	ID          string  `json:"id"`
@@ -9,30 +19,55 @@ type SaleItem struct {
 	ProductID   string  `json:"productId"`
 	ProductName string  `json:"productName"`
 	Quantity    float64 `json:"quantity"` // in kg
-	Subtotal    float64 `json:"subtotal"`
+	PricePerKg  Money   `json:"pricePerKg"`
+	Subtotal    Money   `json:"subtotal"`
+	// Lots are the lots the line was taken from, when known.
+	Lots []SaleItemLot `json:"lots,omitempty"`
 }
 
 // Sale represents a completed sale transaction.
 type Sale struct {
-	ID           string     `json:"id"`
-	ClientID     string     `json:"clientId"`
-	ClientName   string     `json:"clientName"`
-	Items        []SaleItem `json:"items"`
-	Total        float64    `json:"total"`
-	PaidAmount   float64    `json:"paidAmount"`
-	CreditAmount float64    `json:"creditAmount"`
-	Date         time.Time  `json:"date"`
+	ID             string        `json:"id"`
+	ClientID       string        `json:"clientId"`
+	ClientName     string        `json:"clientName"`
+	Items          []SaleItem    `json:"items"`
+	Total          Money         `json:"total"`
+	PaidAmount     Money         `json:"paidAmount"`
+	DepositAmount  Money         `json:"depositAmount,omitempty"` // settled by the order's deposit
+	CreditAmount   Money         `json:"creditAmount"`
+	PaymentMethod  PaymentMethod `json:"paymentMethod"`
+	RefundedAmount Money         `json:"refundedAmount"`
+	Status         SaleStatus    `json:"status"`
+	UserID         string        `json:"userId,omitempty"`
+	UserName       string        `json:"userName,omitempty"`
+	// CreditOverrideBy is the owner who allowed this sale over the client's
+	// credit limit.
+	CreditOverrideBy string `json:"creditOverrideBy,omitempty"`
+	// OrderID is the order picked up with this sale, if any.
+	OrderID string `json:"orderId,omitempty"`
+	// ClientBalance is the client's outstanding credit right after the sale.
+	// It is nil for sales recorded before it was kept.
+	ClientBalance *Money `json:"clientBalance,omitempty"`
+	// Warnings are returned when the sale is created, they are not stored.
+	Warnings []StockWarning `json:"warnings,omitempty"`
+	Date     time.Time      `json:"date"`
 }
 
 // CreateSaleItemRequest is used to add items when creating a sale.
 type CreateSaleItemRequest struct {
 	ProductID string  `json:"productId" validate:"required"`
 	Quantity  float64 `json:"quantity" validate:"required,gt=0"`
+	// LotID takes the line from this lot instead of the oldest ones.
+	LotID string `json:"lotId,omitempty"`
 }
 
 // CreateSaleRequest represents the payload to register a new sale.
 type CreateSaleRequest struct {
 	ClientID   string                  `json:"clientId" validate:"required"`
 	Items      []CreateSaleItemRequest `json:"items" validate:"required,min=1,dive"`
-	PaidAmount float64                 `json:"paidAmount" validate:"gte=0"`
+	PaidAmount Money                   `json:"paidAmount" validate:"gte=0"`
+	Method     PaymentMethod           `json:"method,omitempty" validate:"omitempty,oneof=cash carte virement"`
+	// OverrideToken is an owner's access token allowing the sale to go over
+	// the client's credit limit.
+	OverrideToken string `json:"overrideToken,omitempty"`
 }
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// StatementEntryKind is the kind of movement on a client's account.
+type StatementEntryKind string
+
+const (
+	StatementSale    StatementEntryKind = "vente"
+	StatementPayment StatementEntryKind = "paiement"
+	StatementRefund  StatementEntryKind = "remboursement"
+)
+
+// StatementEntry is one movement on a client's account. Debit increases what
+// the client owes, Credit decreases it; Balance is the running balance after
+// the movement.
+type StatementEntry struct {
+	Date        time.Time          `json:"date"`
+	Kind        StatementEntryKind `json:"kind"`
+	Reference   string             `json:"reference"`
+	SaleID      string             `json:"saleId,omitempty"`
+	Method      PaymentMethod      `json:"method,omitempty"`
+	Description string             `json:"description"`
+	Debit       Money              `json:"debit"`
+	Credit      Money              `json:"credit"`
+	Balance     Money              `json:"balance"`
+}
+
+// Statement is a client's account ledger over a period (relevé de compte).
+// From and To are inclusive YYYY-MM-DD dates.
+type Statement struct {
+	ClientID       string           `json:"clientId"`
+	ClientName     string           `json:"clientName"`
+	ClientPhone    string           `json:"clientPhone"`
+	From           string           `json:"from"`
+	To             string           `json:"to"`
+	OpeningBalance Money            `json:"openingBalance"`
+	TotalDebit     Money            `json:"totalDebit"`
+	TotalCredit    Money            `json:"totalCredit"`
+	ClosingBalance Money            `json:"closingBalance"`
+	Entries        []StatementEntry `json:"entries"`
+	GeneratedAt    time.Time        `json:"generatedAt"`
+}
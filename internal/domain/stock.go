@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+// StockMovementKind tells why stock came in or went out.
+type StockMovementKind string
+
+const (
+	StockReception     StockMovementKind = "reception"     // delivered by a supplier
+	StockVente         StockMovementKind = "vente"         // sold
+	StockRemboursement StockMovementKind = "remboursement" // returned by a refund
+	StockPerte         StockMovementKind = "perte"         // waste, trimmings, spoilage
+	StockAjustement    StockMovementKind = "ajustement"    // correction after a count
+	StockTransfert     StockMovementKind = "transfert"     // moved to or from another shop
+)
+
+// StockMovement is one line of the stock ledger. Quantity is in kg, positive
+// when stock comes in and negative when it goes out.
+type StockMovement struct {
+	ID        string            `json:"id"`
+	ProductID string            `json:"productId"`
+	Kind      StockMovementKind `json:"kind"`
+	Quantity  float64           `json:"quantity"`
+	Reference string            `json:"reference,omitempty"` // sale, refund or order ID
+	Note      string            `json:"note,omitempty"`
+	UserID    string            `json:"userId,omitempty"`
+	UserName  string            `json:"userName,omitempty"`
+	Date      time.Time         `json:"date"`
+}
+
+// StockSummary is a product's stock in kg. Confirmed and ready orders hold
+// Reserved kg of the OnHand stock until they are picked up or cancelled, so
+// the counter may only sell what is Available. A product is Tracked once
+// stock has been received or counted for it; before that its stock is not
+// known and InStock is only set by hand.
+type StockSummary struct {
+	OnHand    float64 `json:"onHand"`
+	Reserved  float64 `json:"reserved"`
+	Available float64 `json:"available"`
+	Tracked   bool    `json:"tracked"`
+}
+
+// StockLevel is the current stock of a product.
+type StockLevel struct {
+	ProductID   string       `json:"productId"`
+	ProductName string       `json:"productName"`
+	Category    MeatCategory `json:"category"`
+	StockSummary
+	InStock   bool            `json:"inStock"`
+	Movements []StockMovement `json:"movements,omitempty"`
+}
+
+// StockWarning flags an order line asking for more than will be available
+// for its pickup date, or a sale line taking more than is available now.
+type StockWarning struct {
+	ProductID   string  `json:"productId"`
+	ProductName string  `json:"productName"`
+	Requested   float64 `json:"requested"` // in kg
+	Available   float64 `json:"available"` // in kg
+}
+
+// CreateStockMovementRequest records stock moved by hand. Reception and perte
+// quantities are given positive; ajustement and transfert are signed.
+type CreateStockMovementRequest struct {
+	Kind     StockMovementKind `json:"kind" validate:"required,oneof=reception perte ajustement transfert"`
+	Quantity float64           `json:"quantity" validate:"required"`
+	Note     string            `json:"note,omitempty"`
+	// LotID takes stock going out from this lot instead of the oldest ones.
+	LotID string `json:"lotId,omitempty"`
+}
@@ -0,0 +1,180 @@
+package domain
+
+import "time"
+
+// Supplier is a wholesaler or slaughterhouse the shop buys from. Balance is
+// what the shop owes on its invoices, the mirror of a client's TotalCredit.
+type Supplier struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Phone           string    `json:"phone,omitempty"`
+	Email           string    `json:"email,omitempty"`
+	Address         string    `json:"address,omitempty"`
+	PaymentTermDays *int      `json:"paymentTermDays,omitempty"` // overrides the shop default when set
+	Balance         Money     `json:"balance"`
+	Uninvoiced      Money     `json:"uninvoiced"` // received but not invoiced yet
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// CreateSupplierRequest represents the payload to create a new supplier.
+type CreateSupplierRequest struct {
+	Name    string `json:"name" validate:"required,min=2"`
+	Phone   string `json:"phone,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Address string `json:"address,omitempty"`
+
+	PaymentTermDays *int `json:"paymentTermDays,omitempty" validate:"omitempty,min=0"`
+}
+
+// UpdateSupplierRequest represents the payload to update an existing supplier.
+type UpdateSupplierRequest struct {
+	Name    *string `json:"name,omitempty" validate:"omitempty,min=2"`
+	Phone   *string `json:"phone,omitempty"`
+	Email   *string `json:"email,omitempty"`
+	Address *string `json:"address,omitempty"`
+
+	// PaymentTermDays sets the supplier's payment term; a negative value
+	// clears the override so the shop default applies again.
+	PaymentTermDays *int `json:"paymentTermDays,omitempty"`
+}
+
+// ReceptionItem is a line of goods received: a catalog product going into
+// stock, or a carcass waiting to be cut up.
+type ReceptionItem struct {
+	ID         string  `json:"id"`
+	ProductID  string  `json:"productId,omitempty"`
+	CarcassID  string  `json:"carcassId,omitempty"`
+	LotID      string  `json:"lotId,omitempty"`
+	Name       string  `json:"name"`
+	Quantity   float64 `json:"quantity"`   // in kg
+	PricePerKg Money   `json:"pricePerKg"` // purchase price
+	Subtotal   Money   `json:"subtotal"`
+}
+
+// Reception is a delivery of goods from a supplier (bon de réception). It is
+// owed once invoiced.
+type Reception struct {
+	ID           string          `json:"id"`
+	SupplierID   string          `json:"supplierId"`
+	SupplierName string          `json:"supplierName"`
+	InvoiceID    *string         `json:"invoiceId,omitempty"`
+	Items        []ReceptionItem `json:"items"`
+	Total        Money           `json:"total"`
+	Note         string          `json:"note,omitempty"`
+	UserID       string          `json:"userId,omitempty"`
+	UserName     string          `json:"userName,omitempty"`
+	Date         time.Time       `json:"date"`
+}
+
+// ReceptionItemRequest is a line of a reception: either ProductID for a
+// catalog product, or Category for a whole carcass.
+type ReceptionItemRequest struct {
+	ProductID   string       `json:"productId,omitempty" validate:"required_without=Category,excluded_with=Category"`
+	Category    MeatCategory `json:"category,omitempty"`
+	Description string       `json:"description,omitempty"` // for carcasses
+	Quantity    float64      `json:"quantity" validate:"required,gt=0"`
+	PricePerKg  Money        `json:"pricePerKg" validate:"required,gt=0"`
+	Lot         *LotRequest  `json:"lot,omitempty"`
+}
+
+// CreateReceptionRequest represents the payload to record goods received.
+type CreateReceptionRequest struct {
+	Items []ReceptionItemRequest `json:"items" validate:"required,min=1,dive"`
+	Note  string                 `json:"note,omitempty"`
+	Date  string                 `json:"date,omitempty"` // YYYY-MM-DD, today by default
+}
+
+// SupplierPayment is a single payment made against a supplier invoice.
+// Payments made in one go share a ReceiptID.
+type SupplierPayment struct {
+	ID        string        `json:"id"`
+	InvoiceID string        `json:"invoiceId"`
+	ReceiptID string        `json:"receiptId"`
+	Amount    Money         `json:"amount"`
+	Date      time.Time     `json:"date"`
+	Method    PaymentMethod `json:"method"`
+	UserID    string        `json:"userId,omitempty"`
+	UserName  string        `json:"userName,omitempty"`
+}
+
+// SupplierInvoice is money owed to a supplier, the mirror of a client Credit.
+// It usually covers one or more receptions.
+type SupplierInvoice struct {
+	ID              string            `json:"id"`
+	SupplierID      string            `json:"supplierId"`
+	SupplierName    string            `json:"supplierName"`
+	Number          string            `json:"number"` // the supplier's invoice number
+	Amount          Money             `json:"amount"`
+	RemainingAmount Money             `json:"remainingAmount"`
+	Status          CreditStatus      `json:"status"`
+	Date            time.Time         `json:"date"`
+	DueDate         *time.Time        `json:"dueDate,omitempty"`
+	ReceptionIDs    []string          `json:"receptionIds"`
+	Payments        []SupplierPayment `json:"payments"`
+	CreatedAt       time.Time         `json:"createdAt"`
+}
+
+// IsOverdue reports whether the invoice is still owed after its due date.
+func (i *SupplierInvoice) IsOverdue(now time.Time) bool {
+	return i.RemainingAmount > 0 && i.DueDate != nil && now.After(*i.DueDate)
+}
+
+// StatusAt returns the status the invoice should have at now: paid once
+// nothing remains, late while owed past its due date, ongoing otherwise.
+func (i *SupplierInvoice) StatusAt(now time.Time) CreditStatus {
+	switch {
+	case i.RemainingAmount <= 0:
+		return CreditStatusPaye
+	case i.IsOverdue(now):
+		return CreditStatusEnRetard
+	default:
+		return CreditStatusEnCours
+	}
+}
+
+// SupplierTerms is the policy deciding when a supplier invoice falls due.
+type SupplierTerms struct {
+	// DefaultDays applies to suppliers without their own payment term.
+	DefaultDays int
+}
+
+// DueDate returns when an invoice from supplier dated from falls due.
+func (t SupplierTerms) DueDate(supplier *Supplier, from time.Time) time.Time {
+	days := t.DefaultDays
+	if supplier != nil && supplier.PaymentTermDays != nil {
+		days = *supplier.PaymentTermDays
+	}
+	return from.AddDate(0, 0, days)
+}
+
+// CreateSupplierInvoiceRequest records an invoice received from a supplier.
+// Amount defaults to the total of the receptions it covers; it is required
+// when there are none.
+type CreateSupplierInvoiceRequest struct {
+	Number       string   `json:"number" validate:"required"`
+	ReceptionIDs []string `json:"receptionIds,omitempty"`
+	Amount       *Money   `json:"amount,omitempty" validate:"omitempty,gt=0"`
+	Date         string   `json:"date,omitempty"`    // YYYY-MM-DD, today by default
+	DueDate      string   `json:"dueDate,omitempty"` // YYYY-MM-DD, from the payment term by default
+}
+
+// CreateSupplierPaymentRequest represents a payment to a supplier, spread
+// over their open invoices.
+type CreateSupplierPaymentRequest struct {
+	Amount   Money              `json:"amount" validate:"required,gt=0"`
+	Method   PaymentMethod      `json:"method" validate:"required,oneof=cash carte virement"`
+	Strategy AllocationStrategy `json:"strategy,omitempty" validate:"omitempty,oneof=oldest overdue"`
+}
+
+// SupplierPaymentReceipt summarises a payment to a supplier: one
+// SupplierPayment per invoice it settled, all sharing ReceiptID.
+type SupplierPaymentReceipt struct {
+	ReceiptID    string            `json:"receiptId"`
+	SupplierID   string            `json:"supplierId"`
+	SupplierName string            `json:"supplierName"`
+	Amount       Money             `json:"amount"`
+	Method       PaymentMethod     `json:"method"`
+	Date         time.Time         `json:"date"`
+	Payments     []SupplierPayment `json:"payments"`
+	Balance      Money             `json:"balance"`
+}
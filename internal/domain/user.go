@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Role represents what a staff member is allowed to do.
+type Role string
+
+const (
+	RoleOwner    Role = "owner"
+	RoleCashier  Role = "cashier"
+	RolePreparer Role = "preparer"
+)
+
+// User represents a shop staff account.
+type User struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	Name         string    `json:"name"`
+	Role         Role      `json:"role"`
+	PasswordHash string    `json:"-"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// LoginRequest represents the payload to open a session.
+type LoginRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// LoginResponse carries the access token returned on login.
+type LoginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	User      User      `json:"user"`
+}
+
+// CreateUserRequest represents the payload to create a staff account.
+type CreateUserRequest struct {
+	Username string `json:"username" validate:"required,min=3"`
+	Name     string `json:"name" validate:"required,min=2"`
+	Password string `json:"password" validate:"required,min=8"`
+	Role     Role   `json:"role" validate:"required,oneof=owner cashier preparer"`
+}
+
+// UpdateUserRequest represents the payload to update a staff account.
+type UpdateUserRequest struct {
+	Name     *string `json:"name,omitempty" validate:"omitempty,min=2"`
+	Password *string `json:"password,omitempty" validate:"omitempty,min=8"`
+	Role     *Role   `json:"role,omitempty" validate:"omitempty,oneof=owner cashier preparer"`
+	Active   *bool   `json:"active,omitempty"`
+}
+
+type userKey struct{}
+
+// ContextWithUser returns a copy of ctx carrying the authenticated user.
+func ContextWithUser(ctx context.Context, u *User) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+// UserFromContext returns the authenticated user, or nil.
+func UserFromContext(ctx context.Context) *User {
+	u, _ := ctx.Value(userKey{}).(*User)
+	return u
+}
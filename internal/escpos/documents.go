@@ -0,0 +1,75 @@
+package escpos
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/receipt"
+	"fmt"
+)
+
+// Ticket encodes a sale ticket with a bold total and a paper cut.
+func Ticket(shop receipt.Shop, rc *domain.Receipt) []byte {
+	s := rc.Sale
+	e := NewEncoder()
+
+	e.Align(AlignCenter).Bold(true).DoubleSize(true).Line(shop.Name).DoubleSize(false).Bold(false)
+	for _, h := range []string{shop.Address, shop.Phone} {
+		if h != "" {
+			e.Line(h)
+		}
+	}
+	e.Align(AlignLeft).Separator()
+	e.Line("Ticket : " + receipt.ShortID(s.ID))
+	e.Line("Date   : " + s.Date.Format("02/01/2006 15:04"))
+	e.Line("Client : " + s.ClientName)
+	if s.Status == domain.SaleStatusAnnulee {
+		e.Align(AlignCenter).Bold(true).Line("*** VENTE ANNULÉE ***").Bold(false).Align(AlignLeft)
+	}
+	e.Separator()
+
+	for _, item := range s.Items {
+		e.Line(item.ProductName)
+		detail := fmt.Sprintf("  %.3f kg x %s", item.Quantity, receipt.Amount(item.PricePerKg))
+		e.Line(receipt.Columns(detail, receipt.Amount(item.Subtotal), Columns))
+	}
+	e.Separator()
+
+	for i, t := range receipt.Totals(rc) {
+		line := receipt.Columns(t.Label, receipt.Amount(t.Amount), Columns)
+		if i == 0 {
+			e.Bold(true).Line(line).Bold(false)
+			continue
+		}
+		e.Line(line)
+	}
+	e.Separator()
+	e.Align(AlignCenter).Line("Merci de votre visite")
+	return e.Feed(4).Cut().Bytes()
+}
+
+// PreparationSlip encodes the slip the preparation team works from,
+// with quantities printed large so they can be read at the block.
+func PreparationSlip(order *domain.Order) []byte {
+	e := NewEncoder()
+
+	e.Align(AlignCenter).Bold(true).DoubleSize(true).Line("PRÉPARATION").DoubleSize(false).Bold(false)
+	e.Line("Commande " + receipt.ShortID(order.ID))
+	e.Align(AlignLeft).Separator()
+	e.Line("Client  : " + order.ClientName)
+	if order.ClientPhone != "" {
+		e.Line("Tél.    : " + order.ClientPhone)
+	}
+	e.Bold(true).Line("Retrait : " + order.PickupDate.Format("02/01/2006")).Bold(false)
+	e.Separator()
+
+	for _, item := range order.Items {
+		e.Line(item.ProductName)
+		e.Bold(true).DoubleSize(true).Line(fmt.Sprintf("  %.3f kg", item.Quantity)).DoubleSize(false).Bold(false)
+	}
+
+	if order.Notes != "" {
+		e.Separator()
+		e.Bold(true).Line("Notes :").Bold(false)
+		e.Line(order.Notes)
+	}
+	return e.Separator().Feed(4).Cut().Bytes()
+}
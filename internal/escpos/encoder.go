@@ -0,0 +1,123 @@
+// Package escpos encodes tickets and preparation slips for ESC/POS thermal
+// printers and sends them to a network or device-file printer.
+package escpos
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Columns is the number of characters per line in font A on 80mm paper.
+const Columns = 48
+
+// codePageWPC1252 selects Windows-1252, which covers French accents and €.
+const codePageWPC1252 = 16
+
+// Alignment values for ESC a.
+const (
+	AlignLeft   = 0
+	AlignCenter = 1
+	AlignRight  = 2
+)
+
+// Encoder accumulates ESC/POS commands and text.
+type Encoder struct {
+	buf bytes.Buffer
+}
+
+// NewEncoder returns an encoder that starts by resetting the printer and
+// selecting the Windows-1252 code page.
+func NewEncoder() *Encoder {
+	e := &Encoder{}
+	e.buf.Write([]byte{0x1B, '@'})
+	e.buf.Write([]byte{0x1B, 't', codePageWPC1252})
+	return e
+}
+
+// Bold switches emphasized printing on or off.
+func (e *Encoder) Bold(on bool) *Encoder {
+	e.buf.Write([]byte{0x1B, 'E', flag(on)})
+	return e
+}
+
+// Align sets the justification of the following lines.
+func (e *Encoder) Align(a byte) *Encoder {
+	e.buf.Write([]byte{0x1B, 'a', a})
+	return e
+}
+
+// DoubleSize switches double width and height on or off.
+func (e *Encoder) DoubleSize(on bool) *Encoder {
+	size := byte(0x00)
+	if on {
+		size = 0x11
+	}
+	e.buf.Write([]byte{0x1D, '!', size})
+	return e
+}
+
+// Text writes s encoded in Windows-1252.
+func (e *Encoder) Text(s string) *Encoder {
+	e.buf.Write(encode(s))
+	return e
+}
+
+// Line writes s followed by a line feed.
+func (e *Encoder) Line(s string) *Encoder {
+	return e.Text(s).Feed(1)
+}
+
+// Separator writes a dashed line across the paper.
+func (e *Encoder) Separator() *Encoder {
+	return e.Line(strings.Repeat("-", Columns))
+}
+
+// Feed prints and feeds n lines.
+func (e *Encoder) Feed(n byte) *Encoder {
+	if n == 1 {
+		e.buf.WriteByte('\n')
+		return e
+	}
+	e.buf.Write([]byte{0x1B, 'd', n})
+	return e
+}
+
+// Cut feeds the paper past the cutter and performs a partial cut.
+func (e *Encoder) Cut() *Encoder {
+	e.buf.Write([]byte{0x1D, 'V', 66, 0})
+	return e
+}
+
+// Bytes returns the encoded command stream.
+func (e *Encoder) Bytes() []byte {
+	return e.buf.Bytes()
+}
+
+func flag(on bool) byte {
+	if on {
+		return 1
+	}
+	return 0
+}
+
+// encode converts s to Windows-1252, replacing unsupported runes with '?'.
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		case r == '€':
+			out = append(out, 0x80)
+		case r == '’':
+			out = append(out, 0x92)
+		case r == 'œ':
+			out = append(out, 0x9C)
+		case r == 'Œ':
+			out = append(out, 0x8C)
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
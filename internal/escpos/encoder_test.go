@@ -0,0 +1,124 @@
+package escpos
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/receipt"
+	"bytes"
+	"testing"
+	"time"
+)
+
+var (
+	escInit     = []byte{0x1B, '@'}
+	escCodePage = []byte{0x1B, 't', 16}
+	boldOn      = []byte{0x1B, 'E', 1}
+	boldOff     = []byte{0x1B, 'E', 0}
+	doubleOn    = []byte{0x1D, '!', 0x11}
+	doubleOff   = []byte{0x1D, '!', 0x00}
+	cut         = []byte{0x1D, 'V', 66, 0}
+)
+
+func TestEncoderStartsWithResetAndCodePage(t *testing.T) {
+	got := NewEncoder().Bytes()
+	want := append(append([]byte{}, escInit...), escCodePage...)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got % x, want % x", got, want)
+	}
+}
+
+func TestEncoderWritesWindows1252(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []byte
+	}{
+		{"abc", []byte("abc")},
+		{"é", []byte{0xE9}},
+		{"Épaule à l’os", []byte{0xC9, 'p', 'a', 'u', 'l', 'e', ' ', 0xE0, ' ', 'l', 0x92, 'o', 's'}},
+		{"çœ€", []byte{0xE7, 0x9C, 0x80}},
+		{"✓", []byte{'?'}},
+	}
+	for _, tt := range tests {
+		got := encode(tt.in)
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("encode(%q) = % x, want % x", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEncoderCommands(t *testing.T) {
+	got := NewEncoder().Bold(true).DoubleSize(true).Line("Gigot").DoubleSize(false).Bold(false).Feed(3).Cut().Bytes()
+
+	var want []byte
+	for _, part := range [][]byte{escInit, escCodePage, boldOn, doubleOn, []byte("Gigot\n"), doubleOff, boldOff, {0x1B, 'd', 3}, cut} {
+		want = append(want, part...)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got % x, want % x", got, want)
+	}
+}
+
+func TestTicket(t *testing.T) {
+	rc := &domain.Receipt{
+		Sale: domain.Sale{
+			ID:         "0123456789abcdef",
+			ClientName: "Hélène",
+			Items: []domain.SaleItem{
+				{ProductName: "Côte de bœuf", Quantity: 1.25, PricePerKg: 3200, Subtotal: 4000},
+			},
+			Total:      4000,
+			PaidAmount: 4000,
+			Date:       time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC),
+		},
+	}
+	got := Ticket(receipt.Shop{Name: "Boucherie"}, rc)
+
+	if !bytes.HasPrefix(got, append(append([]byte{}, escInit...), escCodePage...)) {
+		t.Errorf("ticket does not start with ESC @ ESC t 16: % x", got[:8])
+	}
+	if !bytes.HasSuffix(got, cut) {
+		t.Errorf("ticket does not end with GS V cut: % x", got[len(got)-4:])
+	}
+
+	header := concat(boldOn, doubleOn, []byte("Boucherie\n"), doubleOff, boldOff)
+	if !bytes.Contains(got, header) {
+		t.Error("shop name is not printed bold and double size")
+	}
+	total := concat(boldOn, []byte(receipt.Columns("TOTAL", "40.00", Columns)+"\n"), boldOff)
+	if !bytes.Contains(got, total) {
+		t.Error("total is not printed bold")
+	}
+	for _, text := range [][]byte{encode("Hélène"), encode("Côte de bœuf")} {
+		if !bytes.Contains(got, text) {
+			t.Errorf("ticket lacks % x", text)
+		}
+	}
+}
+
+func TestPreparationSlip(t *testing.T) {
+	order := &domain.Order{
+		ID:         "fedcba9876543210",
+		ClientName: "Dupont",
+		PickupDate: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		Items:      []domain.OrderItem{{ProductName: "Gigot", Quantity: 2.5}},
+	}
+	got := PreparationSlip(order)
+
+	qty := concat(boldOn, doubleOn, []byte("  2.500 kg\n"), doubleOff, boldOff)
+	if !bytes.Contains(got, qty) {
+		t.Error("quantity is not printed bold and double size")
+	}
+	if !bytes.Contains(got, encode("PRÉPARATION")) {
+		t.Error("title is not encoded in Windows-1252")
+	}
+	if !bytes.HasSuffix(got, cut) {
+		t.Error("slip does not end with GS V cut")
+	}
+}
+
+func concat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
@@ -0,0 +1,67 @@
+package escpos
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// Printer sends raw ESC/POS bytes to a thermal printer.
+type Printer interface {
+	Print(ctx context.Context, data []byte) error
+}
+
+// TCPPrinter prints to a network printer, usually listening on port 9100.
+type TCPPrinter struct {
+	Addr    string
+	Timeout time.Duration
+}
+
+// Print opens a connection, writes data and closes it.
+func (p *TCPPrinter) Print(ctx context.Context, data []byte) error {
+	d := net.Dialer{Timeout: p.Timeout}
+	conn, err := d.DialContext(ctx, "tcp", p.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if p.Timeout > 0 {
+		conn.SetWriteDeadline(time.Now().Add(p.Timeout))
+	}
+	_, err = conn.Write(data)
+	return err
+}
+
+// FilePrinter prints to a device file such as /dev/usb/lp0.
+type FilePrinter struct {
+	Path string
+}
+
+// Print writes data to the device file.
+func (p *FilePrinter) Print(ctx context.Context, data []byte) error {
+	f, err := os.OpenFile(p.Path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// NewPrinter returns the printer designated by target: "tcp://host:port"
+// for a network printer, or a device file path. An empty target yields nil.
+func NewPrinter(target string) Printer {
+	switch {
+	case target == "":
+		return nil
+	case strings.HasPrefix(target, "tcp://"):
+		return &TCPPrinter{Addr: strings.TrimPrefix(target, "tcp://"), Timeout: 5 * time.Second}
+	default:
+		return &FilePrinter{Path: target}
+	}
+}
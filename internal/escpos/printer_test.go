@@ -0,0 +1,71 @@
+package escpos
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTCPPrinterDeliversBytes(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		received <- data
+	}()
+
+	data := NewEncoder().Bold(true).Line("Épaule d’agneau").Bold(false).Cut().Bytes()
+	p := NewPrinter("tcp://" + ln.Addr().String())
+	if err := p.Print(context.Background(), data); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-received:
+		if !bytes.Equal(got, data) {
+			t.Fatalf("printer received % x, want % x", got, data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("nothing received")
+	}
+}
+
+func TestTCPPrinterUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	p := &TCPPrinter{Addr: addr, Timeout: time.Second}
+	if err := p.Print(context.Background(), []byte("x")); err == nil {
+		t.Fatal("expected an error printing to a closed port")
+	}
+}
+
+func TestNewPrinter(t *testing.T) {
+	if p := NewPrinter(""); p != nil {
+		t.Errorf("empty target: got %T, want nil", p)
+	}
+	if p, ok := NewPrinter("tcp://10.0.0.5:9100").(*TCPPrinter); !ok || p.Addr != "10.0.0.5:9100" {
+		t.Errorf("tcp target: got %#v", p)
+	}
+	if p, ok := NewPrinter("/dev/usb/lp0").(*FilePrinter); !ok || p.Path != "/dev/usb/lp0" {
+		t.Errorf("device target: got %#v", p)
+	}
+}
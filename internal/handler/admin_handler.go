@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"boucherie-api/internal/domain"
+	mw "boucherie-api/internal/middleware"
+	"boucherie-api/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AdminHandler exposes maintenance operations to the owner.
+type AdminHandler struct {
+	reconciliation *service.ReconciliationService
+}
+
+// NewAdminHandler creates a new admin handler.
+func NewAdminHandler(reconciliation *service.ReconciliationService) *AdminHandler {
+	return &AdminHandler{reconciliation: reconciliation}
+}
+
+// Routes registers admin routes. They are reserved to the owner.
+func (h *AdminHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Use(mw.RequireRole(domain.RoleOwner))
+	r.Post("/reconcile", h.reconcile)
+	return r
+}
+
+// reconcile checks client balances against their credits. It only reports
+// unless called with ?dryRun=false, in which case drifted balances are fixed.
+func (h *AdminHandler) reconcile(w http.ResponseWriter, r *http.Request) {
+	dryRun := true
+	if v := r.URL.Query().Get("dryRun"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			Error(w, http.StatusBadRequest, "invalid dryRun value: "+v)
+			return
+		}
+		dryRun = b
+	}
+
+	report, err := h.reconciliation.Reconcile(r.Context(), dryRun)
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"boucherie-api/internal/domain"
+	mw "boucherie-api/internal/middleware"
+	"boucherie-api/internal/service"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AuditHandler exposes the audit trail.
+type AuditHandler struct {
+	svc *service.AuditService
+}
+
+// NewAuditHandler creates a new audit handler.
+func NewAuditHandler(svc *service.AuditService) *AuditHandler {
+	return &AuditHandler{svc: svc}
+}
+
+// Routes registers audit routes. Only the owner may read the trail.
+func (h *AuditHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Use(mw.RequireRole(domain.RoleOwner))
+	r.Get("/", h.list)
+	return r
+}
+
+func (h *AuditHandler) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := domain.AuditFilter{
+		Entity:   domain.AuditEntity(q.Get("entity")),
+		EntityID: q.Get("id"),
+		From:     q.Get("from"),
+		To:       q.Get("to"),
+	}
+
+	entries, err := h.svc.List(r.Context(), filter)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if entries == nil {
+		entries = []domain.AuditEntry{}
+	}
+	JSON(w, http.StatusOK, entries)
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/migrate"
+	"boucherie-api/internal/repository"
+	"boucherie-api/internal/service"
+	"boucherie-api/migrations"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// openTestDB returns a migrated database in a temporary file.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "boucherie.db") + "?_pragma=foreign_keys(1)&_txlock=immediate&_time_format=sqlite"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestAuditList(t *testing.T) {
+	db := openTestDB(t)
+	repo := repository.NewAuditRepo(db)
+	at := func(day, clock string) time.Time {
+		d, err := time.ParseInLocation("2006-01-02 15:04", day+" "+clock, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	// Entries just before and after local midnight
+	for _, e := range []domain.AuditEntry{
+		{ID: "e1", Entity: domain.AuditClient, EntityID: "c1", Action: domain.AuditCreate, Date: at("2026-03-09", "23:50")},
+		{ID: "e2", Entity: domain.AuditClient, EntityID: "c1", Action: domain.AuditUpdate, Date: at("2026-03-10", "00:10")},
+		{ID: "e3", Entity: domain.AuditSale, EntityID: "s1", Action: domain.AuditCreate, Date: at("2026-03-10", "23:50")},
+		{ID: "e4", Entity: domain.AuditClient, EntityID: "c2", Action: domain.AuditCreate, Date: at("2026-03-11", "00:10")},
+	} {
+		if err := repo.Create(context.Background(), &e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := NewAuditHandler(service.NewAuditService(repo)).Routes()
+	owner := &domain.User{ID: "u1", Role: domain.RoleOwner}
+
+	tests := []struct {
+		query      string
+		wantStatus int
+		want       []string
+	}{
+		{query: "", wantStatus: http.StatusOK, want: []string{"e4", "e3", "e2", "e1"}},
+		{query: "entity=client", wantStatus: http.StatusOK, want: []string{"e4", "e2", "e1"}},
+		{query: "entity=client&id=c1", wantStatus: http.StatusOK, want: []string{"e2", "e1"}},
+		{query: "id=s1", wantStatus: http.StatusOK, want: []string{"e3"}},
+		{query: "from=2026-03-10&to=2026-03-10", wantStatus: http.StatusOK, want: []string{"e3", "e2"}},
+		{query: "from=2026-03-11", wantStatus: http.StatusOK, want: []string{"e4"}},
+		{query: "to=2026-03-09", wantStatus: http.StatusOK, want: []string{"e1"}},
+		{query: "entity=client&from=2026-03-10", wantStatus: http.StatusOK, want: []string{"e4", "e2"}},
+		{query: "entity=lot", wantStatus: http.StatusOK, want: []string{}},
+		{query: "from=10/03/2026", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+			req = req.WithContext(domain.ContextWithUser(req.Context(), owner))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.want == nil {
+				return
+			}
+			var body struct {
+				Data []domain.AuditEntry `json:"data"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, e := range body.Data {
+				got = append(got, e.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("entries %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("cashier refused", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(domain.ContextWithUser(req.Context(), &domain.User{ID: "u2", Role: domain.RoleCashier}))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+		}
+	})
+}
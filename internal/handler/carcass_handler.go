@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"boucherie-api/internal/domain"
+	mw "boucherie-api/internal/middleware"
+	"boucherie-api/internal/service"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// CarcassHandler handles HTTP requests for carcass receptions and breakdowns.
+type CarcassHandler struct {
+	svc      *service.CarcassService
+	validate *validator.Validate
+}
+
+// NewCarcassHandler creates a new carcass handler.
+func NewCarcassHandler(svc *service.CarcassService) *CarcassHandler {
+	return &CarcassHandler{svc: svc, validate: validator.New()}
+}
+
+// Routes registers carcass routes. They are reserved to the owner and cashiers.
+func (h *CarcassHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Use(mw.RequireRole(domain.RoleOwner, domain.RoleCashier))
+	r.Get("/", h.list)
+	r.Post("/", h.create)
+	r.Get("/yields", h.yields)
+	r.Get("/{id}", h.get)
+	r.Delete("/{id}", h.delete)
+	r.Post("/{id}/breakdown", h.breakdown)
+	return r
+}
+
+// filter reads the category, supplierId, from and to query parameters.
+func (h *CarcassHandler) filter(r *http.Request) domain.CarcassFilter {
+	q := r.URL.Query()
+	return domain.CarcassFilter{
+		Category:   domain.MeatCategory(q.Get("category")),
+		SupplierID: q.Get("supplierId"),
+		From:       q.Get("from"),
+		To:         q.Get("to"),
+	}
+}
+
+func (h *CarcassHandler) list(w http.ResponseWriter, r *http.Request) {
+	list, err := h.svc.List(r.Context(), h.filter(r))
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, list)
+}
+
+func (h *CarcassHandler) get(w http.ResponseWriter, r *http.Request) {
+	c, err := h.svc.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, c)
+}
+
+func (h *CarcassHandler) create(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateCarcassRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	c, err := h.svc.Create(r.Context(), req)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusCreated, c)
+}
+
+// breakdown records the cuts and waste of a received carcass.
+func (h *CarcassHandler) breakdown(w http.ResponseWriter, r *http.Request) {
+	var req domain.BreakdownRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	c, err := h.svc.Breakdown(r.Context(), chi.URLParam(r, "id"), req)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, c)
+}
+
+func (h *CarcassHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, map[string]string{"deleted": id})
+}
+
+// yields sums up the yield and cost per kg of each cut for a category.
+func (h *CarcassHandler) yields(w http.ResponseWriter, r *http.Request) {
+	report, err := h.svc.Yields(r.Context(), h.filter(r))
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, report)
+}
@@ -2,7 +2,11 @@ package handler
 
 import (
 	"boucherie-api/internal/domain"
+	mw "boucherie-api/internal/middleware"
+	"boucherie-api/internal/receipt"
 	"boucherie-api/internal/service"
+	"boucherie-api/internal/statement"
+	"bytes"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -11,22 +15,33 @@ import (
 
 // ClientHandler handles HTTP requests for client operations.
 type ClientHandler struct {
-	svc      *service.ClientService
-	validate *validator.Validate
+	svc        *service.ClientService
+	credits    *service.CreditService
+	statements *service.StatementService
+	shop       receipt.Shop
+	validate   *validator.Validate
 }
 
 // NewClientHandler creates a new client handler.
-func NewClientHandler(svc *service.ClientService) *ClientHandler {
-	return &ClientHandler{svc: svc, validate: validator.New()}
+func NewClientHandler(
+	svc *service.ClientService,
+	credits *service.CreditService,
+	statements *service.StatementService,
+	shop receipt.Shop,
+) *ClientHandler {
+	return &ClientHandler{svc: svc, credits: credits, statements: statements, shop: shop, validate: validator.New()}
 }
 
 // Routes registers client routes on the given router.
 func (h *ClientHandler) Routes() http.Handler {
 	r := chi.NewRouter()
+	r.Use(mw.RequireRole(domain.RoleOwner, domain.RoleCashier))
 	r.Get("/", h.list)
 	r.Post("/", h.create)
 	r.Get("/{id}", h.get)
 	r.Put("/{id}", h.update)
+	r.Post("/{id}/payments", h.pay)
+	r.Get("/{id}/statement", h.statement)
 	return r
 }
 
@@ -64,7 +79,7 @@ func (h *ClientHandler) create(w http.ResponseWriter, r *http.Request) {
 	}
 	client, err := h.svc.Create(r.Context(), req)
 	if err != nil {
-		Error(w, http.StatusInternalServerError, err.Error())
+		ServiceError(w, err, http.StatusInternalServerError)
 		return
 	}
 	JSON(w, http.StatusCreated, client)
@@ -77,10 +92,67 @@ func (h *ClientHandler) update(w http.ResponseWriter, r *http.Request) {
 		Error(w, http.StatusBadRequest, "invalid JSON body")
 		return
 	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	client, err := h.svc.Update(r.Context(), id, req)
 	if err != nil {
-		Error(w, http.StatusNotFound, err.Error())
+		ServiceError(w, err, http.StatusNotFound)
 		return
 	}
 	JSON(w, http.StatusOK, client)
 }
+
+func (h *ClientHandler) pay(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req domain.CreateClientPaymentRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	receipt, err := h.credits.PayOnAccount(r.Context(), id, req)
+	if err != nil {
+		ServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+	JSON(w, http.StatusCreated, receipt)
+}
+
+// statement returns the client's account ledger as JSON, CSV or PDF.
+func (h *ClientHandler) statement(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	q := r.URL.Query()
+	st, err := h.statements.Statement(r.Context(), id, q.Get("from"), q.Get("to"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filename := "releve-" + receipt.ShortID(st.ClientID) + "-" + st.From + "-" + st.To
+	switch format := q.Get("format"); format {
+	case "", "json":
+		JSON(w, http.StatusOK, st)
+	case "csv":
+		var buf bytes.Buffer
+		if err := statement.CSV(&buf, st); err != nil {
+			Error(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.csv"`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes())
+	case "pdf":
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `inline; filename="`+filename+`.pdf"`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(statement.PDF(h.shop, st))
+	default:
+		Error(w, http.StatusBadRequest, "unknown statement format: "+format)
+	}
+}
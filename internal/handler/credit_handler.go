@@ -2,6 +2,7 @@ package handler
 
 import (
 	"boucherie-api/internal/domain"
+	mw "boucherie-api/internal/middleware"
 	"boucherie-api/internal/service"
 	"net/http"
 
@@ -23,6 +24,7 @@ func NewCreditHandler(svc *service.CreditService) *CreditHandler {
 // Routes registers credit routes.
 func (h *CreditHandler) Routes() http.Handler {
 	r := chi.NewRouter()
+	r.Use(mw.RequireRole(domain.RoleOwner, domain.RoleCashier))
 	r.Get("/", h.list)
 	r.Post("/{id}/payments", h.addPayment)
 	return r
@@ -58,7 +60,7 @@ func (h *CreditHandler) addPayment(w http.ResponseWriter, r *http.Request) {
 	}
 	credit, err := h.svc.AddPayment(r.Context(), id, req)
 	if err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
+		ServiceError(w, err, http.StatusBadRequest)
 		return
 	}
 	JSON(w, http.StatusOK, credit)
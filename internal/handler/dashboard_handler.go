@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"boucherie-api/internal/domain"
 	"context"
 	"database/sql"
 	"net/http"
+	"time"
 )
 
 // DashboardHandler handles the dashboard stats endpoint.
@@ -17,20 +19,21 @@ func NewDashboardHandler(db *sql.DB) *DashboardHandler {
 }
 
 type dashboardStats struct {
-	TodayRevenue  float64      `json:"todayRevenue"`
-	TodayCash     float64      `json:"todayCash"`
-	TodayCredit   float64      `json:"todayCredit"`
+	TodayRevenue  domain.Money `json:"todayRevenue"`
+	TodayCash     domain.Money `json:"todayCash"`
+	TodayCredit   domain.Money `json:"todayCredit"`
+	TodayRefunds  domain.Money `json:"todayRefunds"`
 	TodaySales    int          `json:"todaySales"`
 	TotalClients  int          `json:"totalClients"`
-	PendingCredit float64      `json:"pendingCredit"`
+	PendingCredit domain.Money `json:"pendingCredit"`
 	OverdueCount  int          `json:"overdueCount"`
 	TopDebtors    []debtorInfo `json:"topDebtors"`
 }
 
 type debtorInfo struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	TotalCredit float64 `json:"totalCredit"`
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	TotalCredit domain.Money `json:"totalCredit"`
 }
 
 // ServeHTTP handles GET /api/v1/dashboard.
@@ -38,11 +41,29 @@ func (h *DashboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	stats := dashboardStats{}
 
+	// Today is the shop's day, from local midnight to the next
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	tomorrow := today.AddDate(0, 0, 1)
+
 	// Today's revenue
 	h.db.QueryRowContext(ctx,
-		`SELECT COALESCE(SUM(total),0), COALESCE(SUM(paid_amount),0), COALESCE(SUM(credit_amount),0), COUNT(*) FROM sales WHERE date(date) = date('now')`,
+		`SELECT COALESCE(SUM(total),0), COALESCE(SUM(paid_amount),0), COALESCE(SUM(credit_amount),0), COUNT(*) FROM sales
+		 WHERE datetime(date) >= datetime(?) AND datetime(date) < datetime(?)`,
+		today, tomorrow,
 	).Scan(&stats.TodayRevenue, &stats.TodayCash, &stats.TodayCredit, &stats.TodaySales)
 
+	// Today's refunds are netted out of revenue, cash and credit
+	var refundCash, refundCredit domain.Money
+	h.db.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(total),0), COALESCE(SUM(cash_amount),0), COALESCE(SUM(credit_amount),0) FROM refunds
+		 WHERE datetime(date) >= datetime(?) AND datetime(date) < datetime(?)`,
+		today, tomorrow,
+	).Scan(&stats.TodayRefunds, &refundCash, &refundCredit)
+	stats.TodayRevenue -= stats.TodayRefunds
+	stats.TodayCash -= refundCash
+	stats.TodayCredit -= refundCredit
+
 	// Total clients
 	h.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM clients`).Scan(&stats.TotalClients)
 
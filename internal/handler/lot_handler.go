@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"boucherie-api/internal/domain"
+	mw "boucherie-api/internal/middleware"
+	"boucherie-api/internal/service"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// LotHandler handles HTTP requests for lot traceability.
+type LotHandler struct {
+	svc *service.StockService
+}
+
+// NewLotHandler creates a new lot handler.
+func NewLotHandler(svc *service.StockService) *LotHandler {
+	return &LotHandler{svc: svc}
+}
+
+// Routes registers lot routes. They are reserved to the owner and cashiers.
+func (h *LotHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Use(mw.RequireRole(domain.RoleOwner, domain.RoleCashier))
+	r.Get("/", h.list)
+	r.Get("/{id}", h.get)
+	r.Get("/{id}/recall", h.recall)
+	return r
+}
+
+// list handles GET /lots?productId=&supplierId=&number=&open=true.
+func (h *LotHandler) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	lots, err := h.svc.Lots(r.Context(), domain.LotFilter{
+		ProductID:  q.Get("productId"),
+		SupplierID: q.Get("supplierId"),
+		Number:     q.Get("number"),
+		OpenOnly:   q.Get("open") == "true",
+	})
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, lots)
+}
+
+func (h *LotHandler) get(w http.ResponseWriter, r *http.Request) {
+	lot, err := h.svc.Lot(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, lot)
+}
+
+// recall lists the sales and clients that received meat from the lot.
+func (h *LotHandler) recall(w http.ResponseWriter, r *http.Request) {
+	recall, err := h.svc.Recall(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, recall)
+}
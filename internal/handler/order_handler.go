@@ -2,8 +2,11 @@ package handler
 
 import (
 	"boucherie-api/internal/domain"
+	"boucherie-api/internal/escpos"
+	mw "boucherie-api/internal/middleware"
 	"boucherie-api/internal/service"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
@@ -12,22 +15,41 @@ import (
 // OrderHandler handles HTTP requests for order operations.
 type OrderHandler struct {
 	svc      *service.OrderService
+	printer  escpos.Printer
 	validate *validator.Validate
 }
 
-// NewOrderHandler creates a new order handler.
-func NewOrderHandler(svc *service.OrderService) *OrderHandler {
-	return &OrderHandler{svc: svc, validate: validator.New()}
+// NewOrderHandler creates a new order handler. printer may be nil when no
+// thermal printer is configured.
+func NewOrderHandler(svc *service.OrderService, printer escpos.Printer) *OrderHandler {
+	return &OrderHandler{svc: svc, printer: printer, validate: validator.New()}
 }
 
 // Routes registers order routes.
 func (h *OrderHandler) Routes() http.Handler {
 	r := chi.NewRouter()
 	r.Get("/", h.list)
-	r.Post("/", h.create)
+	r.Get("/planning", h.planning)
+	r.Get("/calendar", h.calendar)
+	r.Get("/slots", h.slots)
 	r.Get("/{id}", h.get)
 	r.Put("/{id}", h.update)
-	r.Delete("/{id}", h.delete)
+	r.Post("/{id}/print", h.print)
+	r.Post("/{id}/ready", h.transition(domain.OrderStatusPrete))
+	r.Group(func(r chi.Router) {
+		r.Use(mw.RequireRole(domain.RoleOwner, domain.RoleCashier))
+		r.Post("/", h.create)
+		r.Delete("/{id}", h.delete)
+		r.Post("/{id}/confirm", h.transition(domain.OrderStatusConfirmee))
+		r.Post("/{id}/deliver", h.transition(domain.OrderStatusLivree))
+		r.Post("/{id}/checkout", h.checkout)
+		r.Post("/{id}/cancel", h.transition(domain.OrderStatusAnnulee))
+		r.Post("/{id}/items", h.addItem)
+		r.Put("/{id}/items/{itemId}", h.updateItem)
+		r.Delete("/{id}/items/{itemId}", h.removeItem)
+		r.Get("/{id}/deposits", h.listDeposits)
+		r.Post("/{id}/deposits", h.addDeposit)
+	})
 	return r
 }
 
@@ -48,6 +70,45 @@ func (h *OrderHandler) list(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, orders)
 }
 
+// planning returns the preparation list for ?date= (today by default).
+func (h *OrderHandler) planning(w http.ResponseWriter, r *http.Request) {
+	planning, err := h.svc.Planning(r.Context(), r.URL.Query().Get("date"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, planning)
+}
+
+// calendar returns per-day order counts for the week containing ?date=.
+func (h *OrderHandler) calendar(w http.ResponseWriter, r *http.Request) {
+	cal, err := h.svc.Calendar(r.Context(), r.URL.Query().Get("date"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, cal)
+}
+
+// slots returns pickup availability for ?days= days (14 by default) from ?from=.
+func (h *OrderHandler) slots(w http.ResponseWriter, r *http.Request) {
+	days := 14
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 62 {
+			Error(w, http.StatusBadRequest, "days must be between 1 and 62")
+			return
+		}
+		days = n
+	}
+	slots, err := h.svc.Slots(r.Context(), r.URL.Query().Get("from"), days)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, slots)
+}
+
 func (h *OrderHandler) get(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	order, err := h.svc.Get(r.Context(), id)
@@ -83,14 +144,128 @@ func (h *OrderHandler) update(w http.ResponseWriter, r *http.Request) {
 		Error(w, http.StatusBadRequest, "invalid JSON body")
 		return
 	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	order, err := h.svc.Update(r.Context(), id, req)
 	if err != nil {
-		Error(w, http.StatusNotFound, err.Error())
+		ServiceError(w, err, http.StatusNotFound)
+		return
+	}
+	JSON(w, http.StatusOK, order)
+}
+
+// transition returns a handler moving an order to status.
+func (h *OrderHandler) transition(status domain.OrderStatus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		order, err := h.svc.Transition(r.Context(), id, status)
+		if err != nil {
+			ServiceError(w, err, http.StatusNotFound)
+			return
+		}
+		JSON(w, http.StatusOK, order)
+	}
+}
+
+// checkout sells a ready order at the weighed quantities and delivers it.
+func (h *OrderHandler) checkout(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req domain.CheckoutOrderRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	result, err := h.svc.Checkout(r.Context(), id, req)
+	if err != nil {
+		ServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+	JSON(w, http.StatusCreated, result)
+}
+
+func (h *OrderHandler) addItem(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req domain.CreateOrderItemRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	order, err := h.svc.AddItem(r.Context(), id, req)
+	if err != nil {
+		ServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+	JSON(w, http.StatusOK, order)
+}
+
+func (h *OrderHandler) updateItem(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req domain.UpdateOrderItemRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	order, err := h.svc.UpdateItem(r.Context(), id, chi.URLParam(r, "itemId"), req)
+	if err != nil {
+		ServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+	JSON(w, http.StatusOK, order)
+}
+
+func (h *OrderHandler) removeItem(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	order, err := h.svc.RemoveItem(r.Context(), id, chi.URLParam(r, "itemId"))
+	if err != nil {
+		ServiceError(w, err, http.StatusBadRequest)
 		return
 	}
 	JSON(w, http.StatusOK, order)
 }
 
+func (h *OrderHandler) listDeposits(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	deposits, err := h.svc.Deposits(r.Context(), id)
+	if err != nil {
+		ServiceError(w, err, http.StatusNotFound)
+		return
+	}
+	JSON(w, http.StatusOK, deposits)
+}
+
+func (h *OrderHandler) addDeposit(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req domain.CreateDepositRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	deposit, err := h.svc.AddDeposit(r.Context(), id, req)
+	if err != nil {
+		ServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+	JSON(w, http.StatusCreated, deposit)
+}
+
 func (h *OrderHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if err := h.svc.Delete(r.Context(), id); err != nil {
@@ -99,3 +274,22 @@ func (h *OrderHandler) delete(w http.ResponseWriter, r *http.Request) {
 	}
 	JSON(w, http.StatusOK, map[string]string{"deleted": id})
 }
+
+// print sends the preparation slip of an order to the thermal printer.
+func (h *OrderHandler) print(w http.ResponseWriter, r *http.Request) {
+	if h.printer == nil {
+		Error(w, http.StatusServiceUnavailable, "no printer configured")
+		return
+	}
+	id := chi.URLParam(r, "id")
+	order, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err := h.printer.Print(r.Context(), escpos.PreparationSlip(order)); err != nil {
+		Error(w, http.StatusBadGateway, "printer error: "+err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, map[string]string{"printed": id})
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"boucherie-api/internal/domain"
+	mw "boucherie-api/internal/middleware"
 	"boucherie-api/internal/service"
 	"net/http"
 
@@ -12,22 +13,38 @@ import (
 // ProductHandler handles HTTP requests for product operations.
 type ProductHandler struct {
 	svc      *service.ProductService
+	stock    *service.StockService
 	validate *validator.Validate
 }
 
 // NewProductHandler creates a new product handler.
-func NewProductHandler(svc *service.ProductService) *ProductHandler {
-	return &ProductHandler{svc: svc, validate: validator.New()}
+func NewProductHandler(svc *service.ProductService, stock *service.StockService) *ProductHandler {
+	return &ProductHandler{svc: svc, stock: stock, validate: validator.New()}
 }
 
 // Routes registers product routes.
 func (h *ProductHandler) Routes() http.Handler {
 	r := chi.NewRouter()
 	r.Get("/", h.list)
-	r.Post("/", h.create)
 	r.Get("/{id}", h.get)
-	r.Put("/{id}", h.update)
-	r.Delete("/{id}", h.delete)
+	r.Get("/{id}/stock", h.getStock)
+	r.Group(func(r chi.Router) {
+		r.Use(mw.RequireRole(domain.RoleOwner, domain.RoleCashier))
+		r.Put("/{id}", h.update)
+		r.Post("/{id}/stock", h.recordStock)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(mw.RequireRole(domain.RoleOwner))
+		r.Post("/", h.create)
+		r.Delete("/{id}", h.delete)
+	})
+	return r
+}
+
+// InventoryRoutes registers the stock overview routes.
+func (h *ProductHandler) InventoryRoutes() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/", h.inventory)
 	return r
 }
 
@@ -70,7 +87,7 @@ func (h *ProductHandler) create(w http.ResponseWriter, r *http.Request) {
 	}
 	product, err := h.svc.Create(r.Context(), req)
 	if err != nil {
-		Error(w, http.StatusInternalServerError, err.Error())
+		ServiceError(w, err, http.StatusInternalServerError)
 		return
 	}
 	JSON(w, http.StatusCreated, product)
@@ -85,7 +102,7 @@ func (h *ProductHandler) update(w http.ResponseWriter, r *http.Request) {
 	}
 	product, err := h.svc.Update(r.Context(), id, req)
 	if err != nil {
-		Error(w, http.StatusNotFound, err.Error())
+		ServiceError(w, err, http.StatusNotFound)
 		return
 	}
 	JSON(w, http.StatusOK, product)
@@ -99,3 +116,48 @@ func (h *ProductHandler) delete(w http.ResponseWriter, r *http.Request) {
 	}
 	JSON(w, http.StatusOK, map[string]string{"deleted": id})
 }
+
+func (h *ProductHandler) inventory(w http.ResponseWriter, r *http.Request) {
+	levels, err := h.stock.Inventory(r.Context())
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, levels)
+}
+
+// getStock returns a product's stock and its movements, optionally between
+// ?from= and ?to=.
+func (h *ProductHandler) getStock(w http.ResponseWriter, r *http.Request) {
+	var from, to *string
+	if v := r.URL.Query().Get("from"); v != "" {
+		from = &v
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		to = &v
+	}
+	level, err := h.stock.Product(r.Context(), chi.URLParam(r, "id"), from, to)
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, level)
+}
+
+func (h *ProductHandler) recordStock(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateStockMovementRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	m, err := h.stock.Record(r.Context(), chi.URLParam(r, "id"), req)
+	if err != nil {
+		ServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+	JSON(w, http.StatusCreated, m)
+}
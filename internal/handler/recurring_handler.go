@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"boucherie-api/internal/domain"
+	mw "boucherie-api/internal/middleware"
+	"boucherie-api/internal/service"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// RecurringOrderHandler handles HTTP requests for recurring order templates.
+type RecurringOrderHandler struct {
+	svc      *service.RecurringOrderService
+	validate *validator.Validate
+}
+
+// NewRecurringOrderHandler creates a new recurring order handler.
+func NewRecurringOrderHandler(svc *service.RecurringOrderService) *RecurringOrderHandler {
+	return &RecurringOrderHandler{svc: svc, validate: validator.New()}
+}
+
+// Routes registers recurring order routes. They are reserved to the owner and cashiers.
+func (h *RecurringOrderHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Use(mw.RequireRole(domain.RoleOwner, domain.RoleCashier))
+	r.Get("/", h.list)
+	r.Post("/", h.create)
+	r.Get("/{id}", h.get)
+	r.Put("/{id}", h.update)
+	r.Delete("/{id}", h.delete)
+	r.Put("/{id}/occurrences/{date}", h.modifyOccurrence)
+	r.Post("/{id}/occurrences/{date}/skip", h.skip)
+	return r
+}
+
+func (h *RecurringOrderHandler) list(w http.ResponseWriter, r *http.Request) {
+	list, err := h.svc.List(r.Context())
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if list == nil {
+		list = []domain.RecurringOrder{}
+	}
+	JSON(w, http.StatusOK, list)
+}
+
+func (h *RecurringOrderHandler) get(w http.ResponseWriter, r *http.Request) {
+	ro, err := h.svc.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, ro)
+}
+
+func (h *RecurringOrderHandler) create(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateRecurringOrderRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ro, err := h.svc.Create(r.Context(), req)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusCreated, ro)
+}
+
+func (h *RecurringOrderHandler) update(w http.ResponseWriter, r *http.Request) {
+	var req domain.UpdateRecurringOrderRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ro, err := h.svc.Update(r.Context(), chi.URLParam(r, "id"), req)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, ro)
+}
+
+func (h *RecurringOrderHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, map[string]string{"deleted": id})
+}
+
+// skip leaves out one occurrence, cancelling its order if already created.
+func (h *RecurringOrderHandler) skip(w http.ResponseWriter, r *http.Request) {
+	ro, err := h.svc.Skip(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "date"))
+	if err != nil {
+		ServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+	JSON(w, http.StatusOK, ro)
+}
+
+// modifyOccurrence orders one occurrence now with its own lines.
+func (h *RecurringOrderHandler) modifyOccurrence(w http.ResponseWriter, r *http.Request) {
+	var req domain.OccurrenceRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	order, err := h.svc.ModifyOccurrence(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "date"), req)
+	if err != nil {
+		ServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+	JSON(w, http.StatusCreated, order)
+}
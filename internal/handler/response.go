@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"boucherie-api/internal/service"
 	"encoding/json"
+	"errors"
 	"net/http"
 )
 
@@ -10,6 +12,8 @@ type apiResponse struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	Code    string      `json:"code,omitempty"`
+	Details interface{} `json:"details,omitempty"`
 }
 
 // JSON sends a JSON response with the given status code.
@@ -31,3 +35,41 @@ func Decode(r *http.Request, v interface{}) error {
 	defer r.Body.Close()
 	return json.NewDecoder(r.Body).Decode(v)
 }
+
+// ServiceError sends an error returned by a service, using the status that
+// matches its kind, or status for plain business errors.
+func ServiceError(w http.ResponseWriter, err error, status int) {
+	var limitErr *service.CreditLimitError
+	if errors.As(err, &limitErr) {
+		detailedError(w, http.StatusUnprocessableEntity, err, "credit_limit_exceeded", limitErr)
+		return
+	}
+	var capacityErr *service.CapacityError
+	if errors.As(err, &capacityErr) {
+		detailedError(w, http.StatusConflict, err, "capacity_exceeded", capacityErr)
+		return
+	}
+	var transitionErr *service.TransitionError
+	if errors.As(err, &transitionErr) {
+		detailedError(w, http.StatusConflict, err, "invalid_transition", transitionErr)
+		return
+	}
+
+	switch {
+	case errors.Is(err, service.ErrUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, service.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, service.ErrConflict):
+		status = http.StatusConflict
+	}
+	Error(w, status, err.Error())
+}
+
+// detailedError sends an error with a machine-readable code and details the
+// client can display.
+func detailedError(w http.ResponseWriter, status int, err error, code string, details interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiResponse{Success: false, Error: err.Error(), Code: code, Details: details})
+}
@@ -2,8 +2,12 @@ package handler
 
 import (
 	"boucherie-api/internal/domain"
+	"boucherie-api/internal/escpos"
+	mw "boucherie-api/internal/middleware"
+	"boucherie-api/internal/receipt"
 	"boucherie-api/internal/service"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
@@ -12,19 +16,30 @@ import (
 // SaleHandler handles HTTP requests for sale operations.
 type SaleHandler struct {
 	svc      *service.SaleService
+	shop     receipt.Shop
+	printer  escpos.Printer
 	validate *validator.Validate
 }
 
-// NewSaleHandler creates a new sale handler.
-func NewSaleHandler(svc *service.SaleService) *SaleHandler {
-	return &SaleHandler{svc: svc, validate: validator.New()}
+// NewSaleHandler creates a new sale handler. shop is printed on receipts;
+// printer may be nil when no thermal printer is configured.
+func NewSaleHandler(svc *service.SaleService, shop receipt.Shop, printer escpos.Printer) *SaleHandler {
+	return &SaleHandler{svc: svc, shop: shop, printer: printer, validate: validator.New()}
 }
 
 // Routes registers sale routes.
 func (h *SaleHandler) Routes() http.Handler {
 	r := chi.NewRouter()
+	r.Use(mw.RequireRole(domain.RoleOwner, domain.RoleCashier))
 	r.Get("/", h.list)
 	r.Post("/", h.create)
+	r.Get("/{id}", h.get)
+	r.Get("/{id}/lots", h.lots)
+	r.Get("/{id}/receipt", h.receipt)
+	r.Post("/{id}/print", h.print)
+	r.Post("/{id}/cancel", h.cancel)
+	r.Get("/{id}/refunds", h.listRefunds)
+	r.Post("/{id}/refunds", h.refund)
 	return r
 }
 
@@ -39,7 +54,7 @@ func (h *SaleHandler) list(w http.ResponseWriter, r *http.Request) {
 	}
 	sales, err := h.svc.List(r.Context(), clientID, date)
 	if err != nil {
-		Error(w, http.StatusInternalServerError, err.Error())
+		Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	if sales == nil {
@@ -48,6 +63,83 @@ func (h *SaleHandler) list(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, sales)
 }
 
+func (h *SaleHandler) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	sale, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, sale)
+}
+
+// lots lists the lots the sale's lines were taken from.
+func (h *SaleHandler) lots(w http.ResponseWriter, r *http.Request) {
+	lots, err := h.svc.Lots(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, lots)
+}
+
+// receipt handles GET /sales/{id}/receipt?format=text|html|pdf|escpos&width=32|48.
+func (h *SaleHandler) receipt(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	rc, err := h.svc.Receipt(r.Context(), id)
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "text":
+		width, _ := strconv.Atoi(r.URL.Query().Get("width"))
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(receipt.Text(h.shop, rc, width)))
+	case "html":
+		body, err := receipt.HTML(h.shop, rc)
+		if err != nil {
+			Error(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	case "pdf":
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `inline; filename="ticket-`+id+`.pdf"`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(receipt.PDF(h.shop, rc))
+	case "escpos":
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write(escpos.Ticket(h.shop, rc))
+	default:
+		Error(w, http.StatusBadRequest, "unknown receipt format: "+format)
+	}
+}
+
+// print sends the sale ticket to the thermal printer.
+func (h *SaleHandler) print(w http.ResponseWriter, r *http.Request) {
+	if h.printer == nil {
+		Error(w, http.StatusServiceUnavailable, "no printer configured")
+		return
+	}
+	id := chi.URLParam(r, "id")
+	rc, err := h.svc.Receipt(r.Context(), id)
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err := h.printer.Print(r.Context(), escpos.Ticket(h.shop, rc)); err != nil {
+		Error(w, http.StatusBadGateway, "printer error: "+err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, map[string]string{"printed": id})
+}
+
 func (h *SaleHandler) create(w http.ResponseWriter, r *http.Request) {
 	var req domain.CreateSaleRequest
 	if err := Decode(r, &req); err != nil {
@@ -60,8 +152,61 @@ func (h *SaleHandler) create(w http.ResponseWriter, r *http.Request) {
 	}
 	sale, err := h.svc.Create(r.Context(), req)
 	if err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
+		ServiceError(w, err, http.StatusBadRequest)
 		return
 	}
 	JSON(w, http.StatusCreated, sale)
 }
+
+func (h *SaleHandler) listRefunds(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	refunds, err := h.svc.ListRefunds(r.Context(), id)
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if refunds == nil {
+		refunds = []domain.Refund{}
+	}
+	JSON(w, http.StatusOK, refunds)
+}
+
+func (h *SaleHandler) cancel(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req domain.CancelSaleRequest
+	if r.ContentLength != 0 {
+		if err := Decode(r, &req); err != nil {
+			Error(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	refund, err := h.svc.Cancel(r.Context(), id, req)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusCreated, refund)
+}
+
+func (h *SaleHandler) refund(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req domain.CreateRefundRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	refund, err := h.svc.Refund(r.Context(), id, req)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusCreated, refund)
+}
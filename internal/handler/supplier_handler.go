@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"boucherie-api/internal/domain"
+	mw "boucherie-api/internal/middleware"
+	"boucherie-api/internal/service"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// SupplierHandler handles HTTP requests for suppliers, the goods received
+// from them and what the shop owes them.
+type SupplierHandler struct {
+	svc      *service.SupplierService
+	invoices *service.SupplierInvoiceService
+	validate *validator.Validate
+}
+
+// NewSupplierHandler creates a new supplier handler.
+func NewSupplierHandler(svc *service.SupplierService, invoices *service.SupplierInvoiceService) *SupplierHandler {
+	return &SupplierHandler{svc: svc, invoices: invoices, validate: validator.New()}
+}
+
+// Routes registers supplier routes. They are reserved to the owner and cashiers.
+func (h *SupplierHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Use(mw.RequireRole(domain.RoleOwner, domain.RoleCashier))
+	r.Get("/", h.list)
+	r.Post("/", h.create)
+	r.Get("/{id}", h.get)
+	r.Put("/{id}", h.update)
+	r.Get("/{id}/receptions", h.receptions)
+	r.Post("/{id}/receptions", h.receive)
+	r.Get("/{id}/invoices", h.supplierInvoices)
+	r.Post("/{id}/invoices", h.createInvoice)
+	r.Post("/{id}/payments", h.pay)
+	return r
+}
+
+// InvoiceRoutes registers supplier invoice routes, mounted at /supplier-invoices.
+func (h *SupplierHandler) InvoiceRoutes() http.Handler {
+	r := chi.NewRouter()
+	r.Use(mw.RequireRole(domain.RoleOwner, domain.RoleCashier))
+	r.Get("/", h.listInvoices)
+	r.Get("/{id}", h.getInvoice)
+	r.Post("/{id}/payments", h.addPayment)
+	return r
+}
+
+func (h *SupplierHandler) list(w http.ResponseWriter, r *http.Request) {
+	suppliers, err := h.svc.List(r.Context())
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, suppliers)
+}
+
+func (h *SupplierHandler) get(w http.ResponseWriter, r *http.Request) {
+	supplier, err := h.svc.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, supplier)
+}
+
+func (h *SupplierHandler) create(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateSupplierRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	supplier, err := h.svc.Create(r.Context(), req)
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	JSON(w, http.StatusCreated, supplier)
+}
+
+func (h *SupplierHandler) update(w http.ResponseWriter, r *http.Request) {
+	var req domain.UpdateSupplierRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	supplier, err := h.svc.Update(r.Context(), chi.URLParam(r, "id"), req)
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, supplier)
+}
+
+// receptions lists the goods received from a supplier; ?uninvoiced=true
+// keeps those not invoiced yet.
+func (h *SupplierHandler) receptions(w http.ResponseWriter, r *http.Request) {
+	uninvoiced := r.URL.Query().Get("uninvoiced") == "true"
+	list, err := h.svc.Receptions(r.Context(), chi.URLParam(r, "id"), uninvoiced)
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, list)
+}
+
+func (h *SupplierHandler) receive(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateReceptionRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	rc, err := h.svc.Receive(r.Context(), chi.URLParam(r, "id"), req)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusCreated, rc)
+}
+
+func (h *SupplierHandler) supplierInvoices(w http.ResponseWriter, r *http.Request) {
+	invoices, err := h.invoices.ListBySupplier(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, invoices)
+}
+
+func (h *SupplierHandler) createInvoice(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateSupplierInvoiceRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	inv, err := h.invoices.Create(r.Context(), chi.URLParam(r, "id"), req)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusCreated, inv)
+}
+
+// pay spreads a payment over the supplier's open invoices.
+func (h *SupplierHandler) pay(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateSupplierPaymentRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	receipt, err := h.invoices.Pay(r.Context(), chi.URLParam(r, "id"), req)
+	if err != nil {
+		ServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+	JSON(w, http.StatusCreated, receipt)
+}
+
+func (h *SupplierHandler) listInvoices(w http.ResponseWriter, r *http.Request) {
+	var status *domain.CreditStatus
+	if s := r.URL.Query().Get("status"); s != "" {
+		cs := domain.CreditStatus(s)
+		status = &cs
+	}
+	invoices, err := h.invoices.List(r.Context(), status)
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, invoices)
+}
+
+func (h *SupplierHandler) getInvoice(w http.ResponseWriter, r *http.Request) {
+	inv, err := h.invoices.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, inv)
+}
+
+func (h *SupplierHandler) addPayment(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreatePaymentRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	inv, err := h.invoices.AddPayment(r.Context(), chi.URLParam(r, "id"), req)
+	if err != nil {
+		ServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+	JSON(w, http.StatusOK, inv)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"boucherie-api/internal/domain"
+	mw "boucherie-api/internal/middleware"
+	"boucherie-api/internal/service"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// UserHandler handles HTTP requests for login and staff accounts.
+type UserHandler struct {
+	svc      *service.UserService
+	validate *validator.Validate
+}
+
+// NewUserHandler creates a new user handler.
+func NewUserHandler(svc *service.UserService) *UserHandler {
+	return &UserHandler{svc: svc, validate: validator.New()}
+}
+
+// AuthRoutes registers the public authentication routes.
+func (h *UserHandler) AuthRoutes() http.Handler {
+	r := chi.NewRouter()
+	r.Post("/login", h.login)
+	return r
+}
+
+// Routes registers staff account routes. They expect an authenticated user.
+func (h *UserHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/me", h.me)
+	r.Group(func(r chi.Router) {
+		r.Use(mw.RequireRole(domain.RoleOwner))
+		r.Get("/", h.list)
+		r.Post("/", h.create)
+		r.Put("/{id}", h.update)
+	})
+	return r
+}
+
+func (h *UserHandler) login(w http.ResponseWriter, r *http.Request) {
+	var req domain.LoginRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := h.svc.Login(r.Context(), req)
+	if err != nil {
+		ServiceError(w, err, http.StatusInternalServerError)
+		return
+	}
+	JSON(w, http.StatusOK, resp)
+}
+
+func (h *UserHandler) me(w http.ResponseWriter, r *http.Request) {
+	JSON(w, http.StatusOK, domain.UserFromContext(r.Context()))
+}
+
+func (h *UserHandler) list(w http.ResponseWriter, r *http.Request) {
+	users, err := h.svc.List(r.Context())
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if users == nil {
+		users = []domain.User{}
+	}
+	JSON(w, http.StatusOK, users)
+}
+
+func (h *UserHandler) create(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateUserRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	user, err := h.svc.Create(r.Context(), req)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	JSON(w, http.StatusCreated, user)
+}
+
+func (h *UserHandler) update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req domain.UpdateUserRequest
+	if err := Decode(r, &req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	user, err := h.svc.Update(r.Context(), id, req)
+	if err != nil {
+		ServiceError(w, err, http.StatusNotFound)
+		return
+	}
+	JSON(w, http.StatusOK, user)
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"boucherie-api/internal/domain"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Authenticator resolves the user behind an access token.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*domain.User, error)
+}
+
+// Authenticate rejects requests without a valid bearer token and stores the
+// authenticated user in the request context.
+func Authenticate(a Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				deny(w, http.StatusUnauthorized, "missing bearer token")
+				return
+			}
+			user, err := a.Authenticate(r.Context(), token)
+			if err != nil {
+				deny(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(domain.ContextWithUser(r.Context(), user)))
+		})
+	}
+}
+
+// RequireRole only lets through users having one of the given roles.
+// It must run after Authenticate.
+func RequireRole(roles ...domain.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := domain.UserFromContext(r.Context())
+			if user == nil {
+				deny(w, http.StatusUnauthorized, "not authenticated")
+				return
+			}
+			for _, role := range roles {
+				if user.Role == role {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			deny(w, http.StatusForbidden, "role "+string(user.Role)+" is not allowed to perform this action")
+		})
+	}
+}
+
+// deny writes an error in the API's JSON envelope.
+func deny(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "error": message})
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"boucherie-api/internal/domain"
+	"net/http"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestContext exposes the ID set by chi's RequestID middleware to the
+// services, which record it in the audit trail. It must run after RequestID.
+func RequestContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := domain.ContextWithRequestID(r.Context(), chimw.GetReqID(r.Context()))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
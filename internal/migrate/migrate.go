@@ -0,0 +1,359 @@
+// Package migrate applies numbered SQL migrations and records them in the
+// schema_migrations table.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Migration is one numbered schema change with its up and down scripts.
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Status describes a known migration and whether it has been applied.
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+	// Modified is true when the up script changed after being applied.
+	Modified bool `json:"modified"`
+	// Legacy is true when the migration is not recorded yet but is already
+	// reflected in a database created by the former schema.sql runner; the
+	// next Up or Down records it without running it.
+	Legacy bool `json:"legacy"`
+}
+
+// record is a row of schema_migrations.
+type record struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migrator applies migrations to a SQLite database.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New loads the migrations found at the root of fsys.
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, e := range entries {
+		m := fileName.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(m[1])
+		body, err := fs.ReadFile(fsys, path.Join(".", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig := byVersion[version]
+		if mig == nil {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+			sum := sha256.Sum256(body)
+			mig.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	mg := &Migrator{db: db}
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", mig.Version, mig.Name)
+		}
+		mg.migrations = append(mg.migrations, *mig)
+	}
+	sort.Slice(mg.migrations, func(i, j int) bool { return mg.migrations[i].Version < mg.migrations[j].Version })
+	return mg, nil
+}
+
+// Status lists every known migration with its applied state. It only reads
+// the database: a missing schema_migrations table or a legacy database is
+// reported, not fixed.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.recorded(ctx)
+	if err != nil {
+		return nil, err
+	}
+	legacy := map[int]bool{}
+	if len(applied) == 0 {
+		if legacy, err = m.legacyVersions(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := Status{Version: mig.Version, Name: mig.Name, Legacy: legacy[mig.Version]}
+		if rec, ok := applied[mig.Version]; ok {
+			at := rec.appliedAt
+			st.Applied = true
+			st.AppliedAt = &at
+			st.Modified = rec.checksum != mig.Checksum
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+// Tracked reports whether the schema_migrations table exists.
+func (m *Migrator) Tracked(ctx context.Context) (bool, error) {
+	return m.hasTable(ctx, "schema_migrations")
+}
+
+// Up applies every pending migration in order, each in its own transaction.
+// It refuses to run if an applied migration was modified since.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verify(applied); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+		err := m.run(ctx, mig.Up, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx,
+				`INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?,?,?,?)`,
+				mig.Version, mig.Name, mig.Checksum, time.Now())
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Down reverts the last steps applied migrations, newest first.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verify(applied); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if mig.Down == "" {
+			return done, fmt.Errorf("migration %d_%s has no down script", mig.Version, mig.Name)
+		}
+		err := m.run(ctx, mig.Down, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, mig.Version)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("reverting migration %d_%s: %w", mig.Version, mig.Name, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// verify checks that applied migrations still match their scripts.
+func (m *Migrator) verify(applied map[int]record) error {
+	for _, mig := range m.migrations {
+		if rec, ok := applied[mig.Version]; ok && rec.checksum != mig.Checksum {
+			return fmt.Errorf("migration %d_%s was modified after being applied (checksum mismatch)", mig.Version, mig.Name)
+		}
+	}
+	return nil
+}
+
+// run executes script and record in one transaction on a dedicated
+// connection. Foreign keys are disabled while the script runs, so tables
+// can be rebuilt as SQLite requires, and verified before committing.
+func (m *Migrator) run(ctx context.Context, script string, record func(tx *sql.Tx) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, `PRAGMA foreign_keys = ON`)
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `PRAGMA foreign_key_check`)
+	if err != nil {
+		return err
+	}
+	violation := rows.Next()
+	rows.Close()
+	if violation {
+		return fmt.Errorf("foreign key violations")
+	}
+
+	if err := record(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// applied returns the schema_migrations rows keyed by version, creating the
+// table and adopting databases set up before versioned migrations existed.
+func (m *Migrator) applied(ctx context.Context) (map[int]record, error) {
+	_, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		checksum   TEXT NOT NULL,
+		applied_at DATETIME NOT NULL
+	)`)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.adoptLegacy(ctx); err != nil {
+		return nil, err
+	}
+	return m.recorded(ctx)
+}
+
+// recorded returns the schema_migrations rows keyed by version, or an empty
+// map when the table does not exist yet.
+func (m *Migrator) recorded(ctx context.Context) (map[int]record, error) {
+	applied := make(map[int]record)
+	tracked, err := m.Tracked(ctx)
+	if err != nil || !tracked {
+		return applied, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int
+		var rec record
+		if err := rows.Scan(&version, &rec.name, &rec.checksum, &rec.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = rec
+	}
+	return applied, rows.Err()
+}
+
+// adoptLegacy records the migrations already reflected in a database that
+// was created by the former migrations/schema.sql runner.
+func (m *Migrator) adoptLegacy(ctx context.Context) error {
+	var count int
+	if err := m.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	legacy, err := m.legacyVersions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, mig := range m.migrations {
+		if !legacy[mig.Version] {
+			continue
+		}
+		_, err := m.db.ExecContext(ctx,
+			`INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?,?,?,?)`,
+			mig.Version, mig.Name, mig.Checksum, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// legacyVersions returns the migrations a schema.sql database already
+// reflects. The schema itself tells which steps are in place: the tables of
+// 0001 and 0002, and the integer amounts of 0003. It is empty for a fresh
+// database.
+func (m *Migrator) legacyVersions(ctx context.Context) (map[int]bool, error) {
+	hasClients, err := m.hasTable(ctx, "clients")
+	if err != nil || !hasClients {
+		return map[int]bool{}, err
+	}
+	hasRefunds, err := m.hasTable(ctx, "refunds")
+	if err != nil {
+		return nil, err
+	}
+	creditType, err := m.columnType(ctx, "clients", "total_credit")
+	if err != nil {
+		return nil, err
+	}
+	return map[int]bool{1: true, 2: hasRefunds, 3: creditType == "INTEGER"}, nil
+}
+
+// hasTable reports whether the database has a table called name.
+func (m *Migrator) hasTable(ctx context.Context, name string) (bool, error) {
+	var n int
+	err := m.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&n)
+	return n > 0, err
+}
+
+// columnType returns the declared type of table.column, upper-cased, or ""
+// when the column does not exist.
+func (m *Migrator) columnType(ctx context.Context, table, column string) (string, error) {
+	var typ string
+	err := m.db.QueryRowContext(ctx, `SELECT UPPER(type) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&typ)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return typ, err
+}
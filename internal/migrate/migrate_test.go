@@ -0,0 +1,337 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"boucherie-api/migrations"
+
+	_ "modernc.org/sqlite"
+)
+
+// openDB returns an empty in-memory database. A single connection keeps
+// every query on the same database.
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:?_pragma=foreign_keys(1)&_time_format=sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// exec runs statements on db and fails the test on error.
+func exec(t *testing.T, db *sql.DB, statements ...string) {
+	t.Helper()
+	for _, s := range statements {
+		if _, err := db.Exec(s); err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+	}
+}
+
+// legacyDB returns a database set up by the former schema.sql runner, with
+// amounts still stored as REAL and a time written as time.Time.String().
+func legacyDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := openDB(t)
+	script, err := fs.ReadFile(migrations.FS, "0001_init.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec(t, db,
+		string(script),
+		`INSERT INTO clients (id, name, phone, total_credit) VALUES ('c1', 'Karim', '0600000000', 12.5)`,
+		`INSERT INTO products (id, name, category, price_per_kg) VALUES ('p1', 'Entrecôte', 'boeuf', 29.9)`,
+		`INSERT INTO sales (id, client_id, client_name, total, paid_amount, credit_amount) VALUES ('s1', 'c1', 'Karim', 32.5, 20, 12.5)`,
+		`INSERT INTO credits (id, client_id, client_name, sale_id, amount, remaining_amount, created_at)
+		 VALUES ('cr1', 'c1', 'Karim', 's1', 12.5, 12.5, '2026-02-16 14:55:55.1686532 +0100 WAT m=+413.142523101')`,
+	)
+	return db
+}
+
+func newMigrator(t *testing.T, db *sql.DB, fsys fs.FS) *Migrator {
+	t.Helper()
+	m, err := New(db, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// versions returns the versions recorded in schema_migrations.
+func versions(t *testing.T, db *sql.DB) []int {
+	t.Helper()
+	rows, err := db.Query(`SELECT version FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var vs []int
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			t.Fatal(err)
+		}
+		vs = append(vs, v)
+	}
+	return vs
+}
+
+func hasTable(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n > 0
+}
+
+// widgets is a small migration set whose second step can be swapped out.
+func widgets(second, secondDown string) fstest.MapFS {
+	return fstest.MapFS{
+		"0001_widgets.up.sql":   {Data: []byte(`CREATE TABLE widgets (id TEXT PRIMARY KEY);`)},
+		"0001_widgets.down.sql": {Data: []byte(`DROP TABLE widgets;`)},
+		"0002_parts.up.sql":     {Data: []byte(second)},
+		"0002_parts.down.sql":   {Data: []byte(secondDown)},
+	}
+}
+
+const (
+	partsUp   = `CREATE TABLE parts (id TEXT PRIMARY KEY, widget_id TEXT NOT NULL REFERENCES widgets(id));`
+	partsDown = `DROP TABLE parts;`
+)
+
+func TestMigrator(t *testing.T) {
+	all, err := New(nil, migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := all.migrations[len(all.migrations)-1].Version
+
+	tests := []struct {
+		name    string
+		db      func(t *testing.T) *sql.DB
+		fsys    fs.FS
+		run     func(ctx context.Context, m *Migrator) error
+		wantErr string
+		check   func(t *testing.T, db *sql.DB)
+	}{
+		{
+			name: "fresh database up",
+			db:   openDB,
+			fsys: migrations.FS,
+			run:  func(ctx context.Context, m *Migrator) error { _, err := m.Up(ctx); return err },
+			check: func(t *testing.T, db *sql.DB) {
+				vs := versions(t, db)
+				if len(vs) != latest || vs[0] != 1 || vs[len(vs)-1] != latest {
+					t.Fatalf("recorded versions %v, want 1..%d", vs, latest)
+				}
+			},
+		},
+		{
+			name: "legacy database adopted and converted",
+			db:   legacyDB,
+			fsys: migrations.FS,
+			run:  func(ctx context.Context, m *Migrator) error { _, err := m.Up(ctx); return err },
+			check: func(t *testing.T, db *sql.DB) {
+				if vs := versions(t, db); len(vs) != latest {
+					t.Fatalf("recorded versions %v, want 1..%d", vs, latest)
+				}
+				var typ string
+				var credit, price, remaining int64
+				if err := db.QueryRow(`SELECT UPPER(type) FROM pragma_table_info('clients') WHERE name = 'total_credit'`).Scan(&typ); err != nil {
+					t.Fatal(err)
+				}
+				if typ != "INTEGER" {
+					t.Fatalf("clients.total_credit is %s, want INTEGER", typ)
+				}
+				if err := db.QueryRow(`SELECT total_credit FROM clients WHERE id = 'c1'`).Scan(&credit); err != nil {
+					t.Fatal(err)
+				}
+				if err := db.QueryRow(`SELECT price_per_kg FROM products WHERE id = 'p1'`).Scan(&price); err != nil {
+					t.Fatal(err)
+				}
+				if err := db.QueryRow(`SELECT remaining_amount FROM credits WHERE id = 'cr1'`).Scan(&remaining); err != nil {
+					t.Fatal(err)
+				}
+				if credit != 1250 || price != 2990 || remaining != 1250 {
+					t.Fatalf("amounts = %d, %d, %d centimes, want 1250, 2990, 1250", credit, price, remaining)
+				}
+				var createdAt string
+				if err := db.QueryRow(`SELECT date(created_at) || ' ' || time(created_at) FROM credits WHERE id = 'cr1'`).Scan(&createdAt); err != nil {
+					t.Fatal(err)
+				}
+				if createdAt != "2026-02-16 13:55:55" {
+					t.Fatalf("credit created at %q, want 2026-02-16 13:55:55 UTC", createdAt)
+				}
+			},
+		},
+		{
+			name: "checksum mismatch refused",
+			db: func(t *testing.T) *sql.DB {
+				db := openDB(t)
+				if _, err := newMigrator(t, db, widgets(partsUp, partsDown)).Up(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+				return db
+			},
+			fsys:    widgets(partsUp+"\n-- edited", partsDown),
+			run:     func(ctx context.Context, m *Migrator) error { _, err := m.Up(ctx); return err },
+			wantErr: "checksum mismatch",
+		},
+		{
+			name: "checksum mismatch refused on down",
+			db: func(t *testing.T) *sql.DB {
+				db := openDB(t)
+				if _, err := newMigrator(t, db, widgets(partsUp, partsDown)).Up(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+				return db
+			},
+			fsys:    widgets(partsUp+"\n-- edited", partsDown),
+			run:     func(ctx context.Context, m *Migrator) error { _, err := m.Down(ctx, 1); return err },
+			wantErr: "checksum mismatch",
+			check: func(t *testing.T, db *sql.DB) {
+				if !hasTable(t, db, "parts") {
+					t.Fatal("parts was dropped")
+				}
+			},
+		},
+		{
+			name:    "foreign key violation rolls back",
+			db:      openDB,
+			fsys:    widgets(partsUp+`INSERT INTO parts (id, widget_id) VALUES ('p1', 'missing');`, partsDown),
+			run:     func(ctx context.Context, m *Migrator) error { _, err := m.Up(ctx); return err },
+			wantErr: "foreign key violations",
+			check: func(t *testing.T, db *sql.DB) {
+				if vs := versions(t, db); len(vs) != 1 || vs[0] != 1 {
+					t.Fatalf("recorded versions %v, want [1]", vs)
+				}
+				if hasTable(t, db, "parts") {
+					t.Fatal("parts survived the failed migration")
+				}
+			},
+		},
+		{
+			name: "down reverts the last migration",
+			db: func(t *testing.T) *sql.DB {
+				db := openDB(t)
+				if _, err := newMigrator(t, db, widgets(partsUp, partsDown)).Up(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+				return db
+			},
+			fsys: widgets(partsUp, partsDown),
+			run:  func(ctx context.Context, m *Migrator) error { _, err := m.Down(ctx, 1); return err },
+			check: func(t *testing.T, db *sql.DB) {
+				if vs := versions(t, db); len(vs) != 1 || vs[0] != 1 {
+					t.Fatalf("recorded versions %v, want [1]", vs)
+				}
+				if hasTable(t, db, "parts") || !hasTable(t, db, "widgets") {
+					t.Fatal("down did not drop parts only")
+				}
+			},
+		},
+		{
+			name: "failing down rolls back",
+			db: func(t *testing.T) *sql.DB {
+				db := openDB(t)
+				if _, err := newMigrator(t, db, widgets(partsUp, partsDown)).Up(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+				return db
+			},
+			fsys:    widgets(partsUp, partsDown+`DROP TABLE missing;`),
+			run:     func(ctx context.Context, m *Migrator) error { _, err := m.Down(ctx, 1); return err },
+			wantErr: "reverting migration 2_parts",
+			check: func(t *testing.T, db *sql.DB) {
+				if vs := versions(t, db); len(vs) != 2 {
+					t.Fatalf("recorded versions %v, want [1 2]", vs)
+				}
+				if !hasTable(t, db, "parts") {
+					t.Fatal("parts was dropped by the failed down")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := tt.db(t)
+			err := tt.run(context.Background(), newMigrator(t, db, tt.fsys))
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, db)
+			}
+		})
+	}
+}
+
+func TestStatusMakesNoWrites(t *testing.T) {
+	tests := []struct {
+		name       string
+		db         func(t *testing.T) *sql.DB
+		wantLegacy []int
+	}{
+		{name: "fresh database", db: openDB},
+		{name: "legacy database", db: legacyDB, wantLegacy: []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := tt.db(t)
+			var schema, changes int
+			if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master`).Scan(&schema); err != nil {
+				t.Fatal(err)
+			}
+			if err := db.QueryRow(`SELECT total_changes()`).Scan(&changes); err != nil {
+				t.Fatal(err)
+			}
+
+			statuses, err := newMigrator(t, db, migrations.FS).Status(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var legacy []int
+			for _, st := range statuses {
+				if st.Applied {
+					t.Fatalf("migration %d reported as applied", st.Version)
+				}
+				if st.Legacy {
+					legacy = append(legacy, st.Version)
+				}
+			}
+			if len(legacy) != len(tt.wantLegacy) || (len(legacy) > 0 && legacy[0] != tt.wantLegacy[0]) {
+				t.Fatalf("legacy migrations %v, want %v", legacy, tt.wantLegacy)
+			}
+
+			var schemaAfter, changesAfter int
+			if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master`).Scan(&schemaAfter); err != nil {
+				t.Fatal(err)
+			}
+			if err := db.QueryRow(`SELECT total_changes()`).Scan(&changesAfter); err != nil {
+				t.Fatal(err)
+			}
+			if schemaAfter != schema || changesAfter != changes {
+				t.Fatalf("status wrote to the database: %d→%d schema objects, %d→%d row changes",
+					schema, schemaAfter, changes, changesAfter)
+			}
+			if hasTable(t, db, "schema_migrations") {
+				t.Fatal("status created schema_migrations")
+			}
+		})
+	}
+}
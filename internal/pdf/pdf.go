@@ -0,0 +1,155 @@
+// Package pdf writes minimal PDF documents made of monospaced text lines.
+// It only covers what the API needs (receipts and statements) and has no
+// external dependency.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// courierAdvance is the glyph width of the standard Courier font, in text space units per point of font size.
+const courierAdvance = 0.6
+
+// Line is a single line of text on a page.
+type Line struct {
+	Text string
+	Bold bool
+}
+
+// Document is a PDF made of fixed-size pages of Courier text.
+type Document struct {
+	width    float64
+	height   float64
+	margin   float64
+	fontSize float64
+	pages    [][]Line
+}
+
+// New creates a document whose pages measure width×height points.
+func New(width, height, margin, fontSize float64) *Document {
+	return &Document{width: width, height: height, margin: margin, fontSize: fontSize}
+}
+
+// Leading returns the vertical distance between two lines, in points.
+func (d *Document) Leading() float64 {
+	return d.fontSize * 1.25
+}
+
+// LinesPerPage returns how many lines fit on one page.
+func (d *Document) LinesPerPage() int {
+	return int((d.height - 2*d.margin) / d.Leading())
+}
+
+// Columns returns how many characters fit on one line.
+func (d *Document) Columns() int {
+	return int((d.width - 2*d.margin) / (d.fontSize * courierAdvance))
+}
+
+// AddPage appends a page with the given lines. Lines beyond LinesPerPage are dropped.
+func (d *Document) AddPage(lines []Line) {
+	d.pages = append(d.pages, lines)
+}
+
+// AddLines lays out lines over as many pages as needed.
+func (d *Document) AddLines(lines []Line) {
+	per := d.LinesPerPage()
+	for len(lines) > per {
+		d.AddPage(lines[:per])
+		lines = lines[per:]
+	}
+	if len(lines) > 0 || len(d.pages) == 0 {
+		d.AddPage(lines)
+	}
+}
+
+// Bytes renders the document.
+func (d *Document) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage(nil)
+	}
+
+	var objects []string
+	add := func(body string) int {
+		objects = append(objects, body)
+		return len(objects)
+	}
+
+	catalog := add("") // filled once the page tree number is known
+	pagesObj := add("")
+	regular := add("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	bold := add("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+
+	var kids []string
+	for _, lines := range d.pages {
+		content := d.pageContent(lines)
+		stream := add(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+		page := add(fmt.Sprintf(
+			"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+			pagesObj, num(d.width), num(d.height), regular, bold, stream))
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+	objects[catalog-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj)
+	objects[pagesObj-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, catalog, xref)
+	return buf.Bytes()
+}
+
+func (d *Document) pageContent(lines []Line) string {
+	var b strings.Builder
+	y := d.height - d.margin - d.fontSize
+	for _, line := range lines {
+		font := "F1"
+		if line.Bold {
+			font = "F2"
+		}
+		fmt.Fprintf(&b, "BT /%s %s Tf %s %s Td (%s) Tj ET\n", font, num(d.fontSize), num(d.margin), num(y), escape(line.Text))
+		y -= d.Leading()
+	}
+	return b.String()
+}
+
+// escape encodes s as a WinAnsi PDF string literal body.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x80:
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&b, "\\%03o", r)
+		case r == '€':
+			b.WriteString("\\200")
+		case r == '’':
+			b.WriteString("\\222")
+		case r == 'œ':
+			b.WriteString("\\234")
+		case r == 'Œ':
+			b.WriteString("\\214")
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+func num(v float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
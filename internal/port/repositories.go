@@ -3,15 +3,24 @@ package port
 import (
 	"boucherie-api/internal/domain"
 	"context"
+	"time"
 )
 
+// Transactor runs a unit of work atomically. Repository calls made with the
+// context passed to fn take part in the same transaction, which is committed
+// when fn returns nil and rolled back otherwise.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 // ClientRepository defines the contract for client persistence.
 type ClientRepository interface {
 	FindAll(ctx context.Context) ([]domain.Client, error)
 	FindByID(ctx context.Context, id string) (*domain.Client, error)
 	Create(ctx context.Context, client *domain.Client) error
 	Update(ctx context.Context, client *domain.Client) error
-	UpdateTotalCredit(ctx context.Context, clientID string, delta float64) error
+	UpdateTotalCredit(ctx context.Context, clientID string, delta domain.Money) error
+	SetTotalCredit(ctx context.Context, clientID string, total domain.Money) error
 }
 
 // ProductRepository defines the contract for product persistence.
@@ -25,37 +34,167 @@ type ProductRepository interface {
 
 // SaleRepository defines the contract for sale persistence.
 type SaleRepository interface {
-	FindAll(ctx context.Context, clientID *string, date *string) ([]domain.Sale, error)
+	FindAll(ctx context.Context, clientID *string, day *time.Time) ([]domain.Sale, error)
 	FindByID(ctx context.Context, id string) (*domain.Sale, error)
 	Create(ctx context.Context, sale *domain.Sale) error
 }
 
+// RefundRepository defines the contract for sale refund persistence.
+type RefundRepository interface {
+	FindBySaleID(ctx context.Context, saleID string) ([]domain.Refund, error)
+	Create(ctx context.Context, refund *domain.Refund) error
+}
+
 // CreditRepository defines the contract for credit persistence.
 type CreditRepository interface {
 	FindAll(ctx context.Context, status *domain.CreditStatus) ([]domain.Credit, error)
 	FindByID(ctx context.Context, id string) (*domain.Credit, error)
 	FindByClientID(ctx context.Context, clientID string) ([]domain.Credit, error)
+	FindBySaleID(ctx context.Context, saleID string) (*domain.Credit, error)
 	Create(ctx context.Context, credit *domain.Credit) error
 	Update(ctx context.Context, credit *domain.Credit) error
 	AddPayment(ctx context.Context, payment *domain.Payment) error
+	RemainingByClient(ctx context.Context) (map[string]domain.Money, error)
 }
 
 // OrderRepository defines the contract for order persistence.
 type OrderRepository interface {
 	FindAll(ctx context.Context, status *domain.OrderStatus) ([]domain.Order, error)
 	FindByID(ctx context.Context, id string) (*domain.Order, error)
+	// FindByPickupDate returns the orders picked up between from and to
+	// (inclusive YYYY-MM-DD dates), cancelled ones included.
+	FindByPickupDate(ctx context.Context, from, to string) ([]domain.Order, error)
+	// CountByPickupDate counts orders by status for each pickup day between
+	// from and to. Days without orders are left out.
+	CountByPickupDate(ctx context.Context, from, to string) ([]domain.CalendarDay, error)
+	// FindByOccurrence returns the order created from a recurring order for
+	// date (YYYY-MM-DD), or nil.
+	FindByOccurrence(ctx context.Context, recurringOrderID, date string) (*domain.Order, error)
 	Create(ctx context.Context, order *domain.Order) error
 	Update(ctx context.Context, order *domain.Order) error
 	Delete(ctx context.Context, id string) error
+	CreateItem(ctx context.Context, item *domain.OrderItem) error
+	UpdateItem(ctx context.Context, item *domain.OrderItem) error
+	DeleteItem(ctx context.Context, id string) error
+	CreateDeposit(ctx context.Context, deposit *domain.OrderDeposit) error
+	FindDeposits(ctx context.Context, orderID string) ([]domain.OrderDeposit, error)
+}
+
+// RecurringOrderRepository defines the contract for recurring order template persistence.
+type RecurringOrderRepository interface {
+	FindAll(ctx context.Context, activeOnly bool) ([]domain.RecurringOrder, error)
+	FindByID(ctx context.Context, id string) (*domain.RecurringOrder, error)
+	Create(ctx context.Context, ro *domain.RecurringOrder) error
+	// Update saves the template and replaces its items.
+	Update(ctx context.Context, ro *domain.RecurringOrder) error
+	Delete(ctx context.Context, id string) error
+	AddSkip(ctx context.Context, id, date string) error
+}
+
+// StockRepository defines the contract for the stock ledger.
+type StockRepository interface {
+	Create(ctx context.Context, movement *domain.StockMovement) error
+	// FindByProduct returns a product's movements, newest first, optionally
+	// between from and to (inclusive YYYY-MM-DD dates).
+	FindByProduct(ctx context.Context, productID string, from, to *string) ([]domain.StockMovement, error)
+	// Level returns the stock of one product, nil if the product does not exist.
+	Level(ctx context.Context, productID string) (*domain.StockLevel, error)
+	// Levels returns the stock of every product.
+	Levels(ctx context.Context) ([]domain.StockLevel, error)
+	// ReservedUntil returns the kg per product reserved by orders picked up
+	// on or before date (YYYY-MM-DD).
+	ReservedUntil(ctx context.Context, date string) (map[string]float64, error)
+}
+
+// LotRepository defines the contract for lot traceability.
+type LotRepository interface {
+	FindAll(ctx context.Context, filter domain.LotFilter) ([]domain.Lot, error)
+	FindByID(ctx context.Context, id string) (*domain.Lot, error)
+	// FindOpen returns the lots of a product with meat left, oldest first.
+	FindOpen(ctx context.Context, productID string) ([]domain.Lot, error)
+	// FindByCarcass returns the lot a carcass was received with, nil if none.
+	FindByCarcass(ctx context.Context, carcassID string) (*domain.Lot, error)
+	FindDerived(ctx context.Context, parentID string) ([]domain.Lot, error)
+	Create(ctx context.Context, lot *domain.Lot) error
+	// Take records qty kg of a sale line taken from a lot.
+	Take(ctx context.Context, saleItemID, lotID string, qty float64) error
+	// Return gives back to a lot qty kg a sale line took from it.
+	Return(ctx context.Context, saleItemID, lotID string, qty float64) error
+	// Draw lowers what is left of a lot for stock leaving without a sale.
+	Draw(ctx context.Context, lotID string, qty float64) error
+	// FindByItem returns the lots a sale line was taken from, in order.
+	FindByItem(ctx context.Context, saleItemID string) ([]domain.SaleItemLot, error)
+	FindBySale(ctx context.Context, saleID string) ([]domain.SaleLot, error)
+	// FindSales returns the sale lines taken from any of the lots.
+	FindSales(ctx context.Context, lotIDs []string) ([]domain.RecallSale, error)
+}
+
+// CarcassRepository defines the contract for carcass breakdowns.
+type CarcassRepository interface {
+	FindAll(ctx context.Context, filter domain.CarcassFilter) ([]domain.Carcass, error)
+	FindByID(ctx context.Context, id string) (*domain.Carcass, error)
+	Create(ctx context.Context, carcass *domain.Carcass) error
+	// SaveBreakdown stores the status, waste and cuts of a carcass.
+	SaveBreakdown(ctx context.Context, carcass *domain.Carcass) error
+	Delete(ctx context.Context, id string) error
+}
+
+// SupplierRepository defines the contract for supplier persistence.
+type SupplierRepository interface {
+	FindAll(ctx context.Context) ([]domain.Supplier, error)
+	FindByID(ctx context.Context, id string) (*domain.Supplier, error)
+	Create(ctx context.Context, supplier *domain.Supplier) error
+	Update(ctx context.Context, supplier *domain.Supplier) error
+	UpdateBalance(ctx context.Context, supplierID string, delta domain.Money) error
+}
+
+// ReceptionRepository defines the contract for goods received from suppliers.
+type ReceptionRepository interface {
+	FindBySupplier(ctx context.Context, supplierID string, uninvoicedOnly bool) ([]domain.Reception, error)
+	FindByID(ctx context.Context, id string) (*domain.Reception, error)
+	Create(ctx context.Context, reception *domain.Reception) error
+	SetInvoice(ctx context.Context, id, invoiceID string) error
+}
+
+// SupplierInvoiceRepository defines the contract for supplier invoice persistence.
+type SupplierInvoiceRepository interface {
+	FindAll(ctx context.Context, status *domain.CreditStatus) ([]domain.SupplierInvoice, error)
+	FindByID(ctx context.Context, id string) (*domain.SupplierInvoice, error)
+	FindBySupplierID(ctx context.Context, supplierID string) ([]domain.SupplierInvoice, error)
+	Create(ctx context.Context, invoice *domain.SupplierInvoice) error
+	Update(ctx context.Context, invoice *domain.SupplierInvoice) error
+	AddPayment(ctx context.Context, payment *domain.SupplierPayment) error
+}
+
+// UserRepository defines the contract for staff account persistence.
+type UserRepository interface {
+	FindAll(ctx context.Context) ([]domain.User, error)
+	FindByID(ctx context.Context, id string) (*domain.User, error)
+	FindByUsername(ctx context.Context, username string) (*domain.User, error)
+	Count(ctx context.Context) (int, error)
+	CountActiveOwners(ctx context.Context) (int, error)
+	Create(ctx context.Context, user *domain.User) error
+	Update(ctx context.Context, user *domain.User) error
+}
+
+// AuditRepository defines the contract for the append-only audit log.
+type AuditRepository interface {
+	Find(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error)
+	Create(ctx context.Context, entry *domain.AuditEntry) error
+}
+
+// StatementRepository reads the movements making up a client's account.
+type StatementRepository interface {
+	FindEntries(ctx context.Context, clientID string, before time.Time) ([]domain.StatementEntry, error)
 }
 
 // DashboardStats holds aggregated data for the dashboard.
 type DashboardStats struct {
-	TotalRevenue    float64        `json:"totalRevenue"`
-	TotalCash       float64        `json:"totalCash"`
-	TotalCredit     float64        `json:"totalCredit"`
-	AvgTicket       float64        `json:"avgTicket"`
-	SalesCount      int            `json:"salesCount"`
-	ClientsServed   int            `json:"clientsServed"`
-	TopDebtors      []domain.Client `json:"topDebtors"`
+	TotalRevenue  domain.Money    `json:"totalRevenue"`
+	TotalCash     domain.Money    `json:"totalCash"`
+	TotalCredit   domain.Money    `json:"totalCredit"`
+	AvgTicket     domain.Money    `json:"avgTicket"`
+	SalesCount    int             `json:"salesCount"`
+	ClientsServed int             `json:"clientsServed"`
+	TopDebtors    []domain.Client `json:"topDebtors"`
 }
@@ -0,0 +1,206 @@
+// Package receipt renders sale tickets as plain text, HTML and PDF.
+package receipt
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/pdf"
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"unicode/utf8"
+)
+
+// Supported plain-text widths, in characters.
+const (
+	Width32 = 32 // 58mm paper
+	Width48 = 48 // 80mm paper
+)
+
+// Shop holds the header printed at the top of every ticket.
+type Shop struct {
+	Name    string
+	Address string
+	Phone   string
+}
+
+// Line is one labelled amount in the totals block of a ticket.
+type Line struct {
+	Label  string
+	Amount domain.Money
+}
+
+// Totals returns the totals block of a ticket, in print order.
+func Totals(rc *domain.Receipt) []Line {
+	s := rc.Sale
+	lines := []Line{
+		{"TOTAL", s.Total},
+	}
+	if s.DepositAmount > 0 {
+		lines = append(lines, Line{"Acompte", s.DepositAmount})
+	}
+	lines = append(lines,
+		Line{"Payé", s.PaidAmount},
+		Line{"À crédit", s.CreditAmount},
+	)
+	if s.RefundedAmount > 0 {
+		lines = append(lines, Line{"Remboursé", s.RefundedAmount})
+	}
+	return append(lines, Line{"Solde client", rc.ClientBalance})
+}
+
+// Text renders the ticket as fixed-width plain text.
+func Text(shop Shop, rc *domain.Receipt, width int) string {
+	if width != Width32 {
+		width = Width48
+	}
+	var b strings.Builder
+	for _, l := range textLines(shop, rc, width) {
+		b.WriteString(l.Text)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// PDF renders the ticket as a single 80mm-wide page sized to its content.
+func PDF(shop Shop, rc *domain.Receipt) []byte {
+	const (
+		pageWidth = 226.77 // 80mm
+		margin    = 8
+	)
+	fontSize := (pageWidth - 2*margin) / (Width48 * 0.6)
+	lines := textLines(shop, rc, Width48)
+	height := 2*margin + float64(len(lines)+1)*fontSize*1.25
+
+	doc := pdf.New(pageWidth, height, margin, fontSize)
+	doc.AddPage(lines)
+	return doc.Bytes()
+}
+
+// HTML renders the ticket as a standalone printable page.
+func HTML(shop Shop, rc *domain.Receipt) ([]byte, error) {
+	var buf bytes.Buffer
+	err := htmlTemplate.Execute(&buf, map[string]interface{}{
+		"Shop":   shop,
+		"Sale":   rc.Sale,
+		"Totals": Totals(rc),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func textLines(shop Shop, rc *domain.Receipt, width int) []pdf.Line {
+	s := rc.Sale
+	sep := pdf.Line{Text: strings.Repeat("-", width)}
+	var lines []pdf.Line
+	add := func(text string, bold bool) {
+		lines = append(lines, pdf.Line{Text: text, Bold: bold})
+	}
+
+	add(center(shop.Name, width), true)
+	for _, h := range []string{shop.Address, shop.Phone} {
+		if h != "" {
+			add(center(h, width), false)
+		}
+	}
+	lines = append(lines, sep)
+	add(truncate("Ticket : "+ShortID(s.ID), width), false)
+	add(truncate("Date   : "+s.Date.Format("02/01/2006 15:04"), width), false)
+	add(truncate("Client : "+s.ClientName, width), false)
+	if s.Status == domain.SaleStatusAnnulee {
+		add(center("*** VENTE ANNULÉE ***", width), true)
+	}
+	lines = append(lines, sep)
+
+	for _, item := range s.Items {
+		add(truncate(item.ProductName, width), false)
+		detail := fmt.Sprintf("  %.3f kg x %s", item.Quantity, Amount(item.PricePerKg))
+		add(Columns(detail, Amount(item.Subtotal), width), false)
+	}
+	lines = append(lines, sep)
+
+	for i, t := range Totals(rc) {
+		add(Columns(t.Label, Amount(t.Amount), width), i == 0)
+	}
+	lines = append(lines, sep)
+	add(center("Merci de votre visite", width), false)
+	return lines
+}
+
+// Amount formats a money amount for printing.
+func Amount(m domain.Money) string {
+	return m.String()
+}
+
+// Columns left-aligns label and right-aligns value on a line of the given width.
+func Columns(label, value string, width int) string {
+	space := width - utf8.RuneCountInString(value) - 1
+	label = truncate(label, space)
+	return label + strings.Repeat(" ", width-utf8.RuneCountInString(label)-utf8.RuneCountInString(value)) + value
+}
+
+func center(s string, width int) string {
+	s = truncate(s, width)
+	pad := (width - utf8.RuneCountInString(s)) / 2
+	return strings.Repeat(" ", pad) + s
+}
+
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width])
+}
+
+// ShortID returns the printable reference of a document ID.
+func ShortID(id string) string {
+	if len(id) > 8 {
+		return strings.ToUpper(id[:8])
+	}
+	return strings.ToUpper(id)
+}
+
+var htmlTemplate = template.Must(template.New("receipt").Funcs(template.FuncMap{
+	"amount": Amount,
+	"short":  ShortID,
+}).Parse(`<!DOCTYPE html>
+<html lang="fr">
+<head>
+<meta charset="utf-8">
+<title>Ticket {{short .Sale.ID}}</title>
+<style>
+body { font-family: monospace; width: 80mm; margin: 0 auto; }
+header, footer { text-align: center; }
+table { width: 100%; border-collapse: collapse; }
+td.num { text-align: right; }
+hr { border: 0; border-top: 1px dashed #000; }
+.total td { font-weight: bold; }
+</style>
+</head>
+<body>
+<header>
+<strong>{{.Shop.Name}}</strong>
+{{if .Shop.Address}}<div>{{.Shop.Address}}</div>{{end}}
+{{if .Shop.Phone}}<div>{{.Shop.Phone}}</div>{{end}}
+</header>
+<hr>
+<div>Ticket : {{short .Sale.ID}}</div>
+<div>Date : {{.Sale.Date.Format "02/01/2006 15:04"}}</div>
+<div>Client : {{.Sale.ClientName}}</div>
+{{if eq .Sale.Status "annulee"}}<div><strong>VENTE ANNULÉE</strong></div>{{end}}
+<hr>
+<table>
+{{range .Sale.Items}}<tr><td colspan="2">{{.ProductName}}</td></tr>
+<tr><td>{{printf "%.3f" .Quantity}} kg x {{amount .PricePerKg}}</td><td class="num">{{amount .Subtotal}}</td></tr>
+{{end}}</table>
+<hr>
+<table>
+{{range $i, $t := .Totals}}<tr{{if eq $i 0}} class="total"{{end}}><td>{{$t.Label}}</td><td class="num">{{amount $t.Amount}}</td></tr>
+{{end}}</table>
+<hr>
+<footer>Merci de votre visite</footer>
+</body>
+</html>
+`))
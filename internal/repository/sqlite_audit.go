@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"boucherie-api/internal/domain"
+	"context"
+	"database/sql"
+)
+
+// SQLiteAuditRepo implements port.AuditRepository.
+type SQLiteAuditRepo struct {
+	db *sql.DB
+}
+
+// NewAuditRepo creates a new SQLite-backed audit repository.
+func NewAuditRepo(db *sql.DB) *SQLiteAuditRepo {
+	return &SQLiteAuditRepo{db: db}
+}
+
+// Find returns audit entries matching the filter, most recent first.
+func (r *SQLiteAuditRepo) Find(ctx context.Context, f domain.AuditFilter) ([]domain.AuditEntry, error) {
+	query := `SELECT id, entity, entity_id, action, user_id, user_name, request_id, before, after, date FROM audit_log WHERE 1=1`
+	var args []interface{}
+
+	if f.Entity != "" {
+		query += ` AND entity = ?`
+		args = append(args, f.Entity)
+	}
+	if f.EntityID != "" {
+		query += ` AND entity_id = ?`
+		args = append(args, f.EntityID)
+	}
+	if f.From != "" {
+		from, err := localDay(f.From)
+		if err != nil {
+			return nil, err
+		}
+		query += ` AND datetime(date) >= datetime(?)`
+		args = append(args, from)
+	}
+	if f.To != "" {
+		to, err := localDay(f.To)
+		if err != nil {
+			return nil, err
+		}
+		query += ` AND datetime(date) < datetime(?)`
+		args = append(args, to.AddDate(0, 0, 1))
+	}
+	query += ` ORDER BY date DESC, rowid DESC`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.AuditEntry
+	for rows.Next() {
+		var e domain.AuditEntry
+		var before, after sql.NullString
+		if err := rows.Scan(&e.ID, &e.Entity, &e.EntityID, &e.Action, &e.UserID, &e.UserName, &e.RequestID, &before, &after, &e.Date); err != nil {
+			return nil, err
+		}
+		if before.Valid {
+			e.Before = []byte(before.String)
+		}
+		if after.Valid {
+			e.After = []byte(after.String)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// Create appends an entry to the audit log.
+func (r *SQLiteAuditRepo) Create(ctx context.Context, e *domain.AuditEntry) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO audit_log (id, entity, entity_id, action, user_id, user_name, request_id, before, after, date) VALUES (?,?,?,?,?,?,?,?,?,?)`,
+		e.ID, e.Entity, e.EntityID, e.Action, e.UserID, e.UserName, e.RequestID, nullJSON(e.Before), nullJSON(e.After), e.Date,
+	)
+	return err
+}
+
+// nullJSON stores an empty document as NULL.
+func nullJSON(b []byte) interface{} {
+	if len(b) == 0 {
+		return nil
+	}
+	return string(b)
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"boucherie-api/internal/domain"
+	"context"
+	"database/sql"
+)
+
+// SQLiteCarcassRepo implements port.CarcassRepository.
+type SQLiteCarcassRepo struct {
+	db *sql.DB
+}
+
+// NewCarcassRepo creates a new SQLite-backed carcass repository.
+func NewCarcassRepo(db *sql.DB) *SQLiteCarcassRepo {
+	return &SQLiteCarcassRepo{db: db}
+}
+
+// carcassColumns selects a carcass without its cuts.
+const carcassColumns = `SELECT id, category, description, supplier, supplier_id, weight, cost, status, waste_kg,
+	user_id, user_name, received_at, broken_down_at
+	FROM carcasses`
+
+func scanCarcass(row rowScanner) (*domain.Carcass, error) {
+	var c domain.Carcass
+	err := row.Scan(&c.ID, &c.Category, &c.Description, &c.Supplier, &c.SupplierID, &c.Weight, &c.Cost, &c.Status, &c.WasteKg,
+		&c.UserID, &c.UserName, &c.ReceivedAt, &c.BrokenDownAt)
+	if err != nil {
+		return nil, err
+	}
+	c.Cuts = []domain.CarcassCut{}
+	return &c, nil
+}
+
+// FindAll returns carcasses with their cuts, most recently received first.
+func (r *SQLiteCarcassRepo) FindAll(ctx context.Context, filter domain.CarcassFilter) ([]domain.Carcass, error) {
+	query := carcassColumns + ` WHERE 1=1`
+	var args []interface{}
+	if filter.Category != "" {
+		query += ` AND category = ?`
+		args = append(args, filter.Category)
+	}
+	if filter.SupplierID != "" {
+		query += ` AND supplier_id = ?`
+		args = append(args, filter.SupplierID)
+	}
+	if filter.From != "" {
+		from, err := localDay(filter.From)
+		if err != nil {
+			return nil, err
+		}
+		query += ` AND datetime(received_at) >= datetime(?)`
+		args = append(args, from)
+	}
+	if filter.To != "" {
+		to, err := localDay(filter.To)
+		if err != nil {
+			return nil, err
+		}
+		query += ` AND datetime(received_at) < datetime(?)`
+		args = append(args, to.AddDate(0, 0, 1))
+	}
+	query += ` ORDER BY received_at DESC`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	list := []domain.Carcass{}
+	for rows.Next() {
+		c, err := scanCarcass(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		list = append(list, *c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		if err := r.loadCuts(ctx, &list[i]); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// FindByID returns a single carcass with its cuts.
+func (r *SQLiteCarcassRepo) FindByID(ctx context.Context, id string) (*domain.Carcass, error) {
+	c, err := scanCarcass(executor(ctx, r.db).QueryRowContext(ctx, carcassColumns+` WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadCuts(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Create inserts a received carcass.
+func (r *SQLiteCarcassRepo) Create(ctx context.Context, c *domain.Carcass) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO carcasses (id, category, description, supplier, supplier_id, weight, cost, status, waste_kg, user_id, user_name, received_at, broken_down_at) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+		c.ID, c.Category, c.Description, c.Supplier, c.SupplierID, c.Weight, c.Cost, c.Status, c.WasteKg, c.UserID, c.UserName, c.ReceivedAt, c.BrokenDownAt,
+	)
+	return err
+}
+
+// SaveBreakdown stores the status, waste and cuts of a carcass in a
+// transaction.
+func (r *SQLiteCarcassRepo) SaveBreakdown(ctx context.Context, c *domain.Carcass) error {
+	return withTx(ctx, r.db, func(q dbtx) error {
+		_, err := q.ExecContext(ctx,
+			`UPDATE carcasses SET status=?, waste_kg=?, broken_down_at=? WHERE id=?`,
+			c.Status, c.WasteKg, c.BrokenDownAt, c.ID,
+		)
+		if err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, `DELETE FROM carcass_cuts WHERE carcass_id = ?`, c.ID); err != nil {
+			return err
+		}
+		for _, cut := range c.Cuts {
+			_, err := q.ExecContext(ctx,
+				`INSERT INTO carcass_cuts (id, carcass_id, product_id, product_name, lot_id, quantity, price_per_kg, cost) VALUES (?,?,?,?,?,?,?,?)`,
+				cut.ID, c.ID, cut.ProductID, cut.ProductName, cut.LotID, cut.Quantity, cut.PricePerKg, cut.Cost,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete removes a carcass and its cuts (CASCADE).
+func (r *SQLiteCarcassRepo) Delete(ctx context.Context, id string) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx, `DELETE FROM carcasses WHERE id = ?`, id)
+	return err
+}
+
+// loadCuts reads the cuts of a carcass.
+func (r *SQLiteCarcassRepo) loadCuts(ctx context.Context, c *domain.Carcass) error {
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT id, carcass_id, product_id, product_name, lot_id, quantity, price_per_kg, cost
+		 FROM carcass_cuts WHERE carcass_id = ? ORDER BY rowid`, c.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cut domain.CarcassCut
+		if err := rows.Scan(&cut.ID, &cut.CarcassID, &cut.ProductID, &cut.ProductName, &cut.LotID, &cut.Quantity, &cut.PricePerKg, &cut.Cost); err != nil {
+			return err
+		}
+		c.Cuts = append(c.Cuts, cut)
+	}
+	return rows.Err()
+}
@@ -18,8 +18,8 @@ func NewClientRepo(db *sql.DB) *SQLiteClientRepo {
 
 // FindAll returns every client ordered by creation date (newest first).
 func (r *SQLiteClientRepo) FindAll(ctx context.Context) ([]domain.Client, error) {
-	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, name, phone, email, avatar, total_credit, created_at FROM clients ORDER BY created_at DESC`)
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT id, name, phone, email, avatar, total_credit, payment_term_days, credit_limit, created_at FROM clients ORDER BY created_at DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -28,7 +28,7 @@ func (r *SQLiteClientRepo) FindAll(ctx context.Context) ([]domain.Client, error)
 	var clients []domain.Client
 	for rows.Next() {
 		var c domain.Client
-		if err := rows.Scan(&c.ID, &c.Name, &c.Phone, &c.Email, &c.Avatar, &c.TotalCredit, &c.CreatedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.Name, &c.Phone, &c.Email, &c.Avatar, &c.TotalCredit, &c.PaymentTermDays, &c.CreditLimit, &c.CreatedAt); err != nil {
 			return nil, err
 		}
 		clients = append(clients, c)
@@ -39,9 +39,9 @@ func (r *SQLiteClientRepo) FindAll(ctx context.Context) ([]domain.Client, error)
 // FindByID returns a single client by ID.
 func (r *SQLiteClientRepo) FindByID(ctx context.Context, id string) (*domain.Client, error) {
 	var c domain.Client
-	err := r.db.QueryRowContext(ctx,
-		`SELECT id, name, phone, email, avatar, total_credit, created_at FROM clients WHERE id = ?`, id,
-	).Scan(&c.ID, &c.Name, &c.Phone, &c.Email, &c.Avatar, &c.TotalCredit, &c.CreatedAt)
+	err := executor(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, name, phone, email, avatar, total_credit, payment_term_days, credit_limit, created_at FROM clients WHERE id = ?`, id,
+	).Scan(&c.ID, &c.Name, &c.Phone, &c.Email, &c.Avatar, &c.TotalCredit, &c.PaymentTermDays, &c.CreditLimit, &c.CreatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -53,27 +53,36 @@ func (r *SQLiteClientRepo) FindByID(ctx context.Context, id string) (*domain.Cli
 
 // Create inserts a new client.
 func (r *SQLiteClientRepo) Create(ctx context.Context, client *domain.Client) error {
-	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO clients (id, name, phone, email, avatar, total_credit, created_at) VALUES (?,?,?,?,?,?,?)`,
-		client.ID, client.Name, client.Phone, client.Email, client.Avatar, client.TotalCredit, client.CreatedAt,
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO clients (id, name, phone, email, avatar, total_credit, payment_term_days, credit_limit, created_at) VALUES (?,?,?,?,?,?,?,?,?)`,
+		client.ID, client.Name, client.Phone, client.Email, client.Avatar, client.TotalCredit, client.PaymentTermDays, client.CreditLimit, client.CreatedAt,
 	)
 	return err
 }
 
 // Update modifies an existing client.
 func (r *SQLiteClientRepo) Update(ctx context.Context, client *domain.Client) error {
-	_, err := r.db.ExecContext(ctx,
-		`UPDATE clients SET name=?, phone=?, email=?, avatar=? WHERE id=?`,
-		client.Name, client.Phone, client.Email, client.Avatar, client.ID,
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`UPDATE clients SET name=?, phone=?, email=?, avatar=?, payment_term_days=?, credit_limit=? WHERE id=?`,
+		client.Name, client.Phone, client.Email, client.Avatar, client.PaymentTermDays, client.CreditLimit, client.ID,
 	)
 	return err
 }
 
 // UpdateTotalCredit atomically adds delta to a client's total credit.
-func (r *SQLiteClientRepo) UpdateTotalCredit(ctx context.Context, clientID string, delta float64) error {
-	_, err := r.db.ExecContext(ctx,
+func (r *SQLiteClientRepo) UpdateTotalCredit(ctx context.Context, clientID string, delta domain.Money) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
 		`UPDATE clients SET total_credit = total_credit + ? WHERE id = ?`,
 		delta, clientID,
 	)
 	return err
 }
+
+// SetTotalCredit overwrites a client's total credit, e.g. when reconciling it.
+func (r *SQLiteClientRepo) SetTotalCredit(ctx context.Context, clientID string, total domain.Money) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`UPDATE clients SET total_credit = ? WHERE id = ?`,
+		total, clientID,
+	)
+	return err
+}
@@ -26,7 +26,7 @@ func (r *SQLiteCreditRepo) FindAll(ctx context.Context, status *domain.CreditSta
 	}
 	query += ` ORDER BY created_at DESC`
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +51,7 @@ func (r *SQLiteCreditRepo) FindAll(ctx context.Context, status *domain.CreditSta
 
 // FindByID returns a single credit with its payments.
 func (r *SQLiteCreditRepo) FindByID(ctx context.Context, id string) (*domain.Credit, error) {
-	row := r.db.QueryRowContext(ctx,
+	row := executor(ctx, r.db).QueryRowContext(ctx,
 		`SELECT id, client_id, client_name, sale_id, amount, remaining_amount, status, created_at, due_date FROM credits WHERE id = ?`, id)
 
 	var c domain.Credit
@@ -77,7 +77,7 @@ func (r *SQLiteCreditRepo) FindByID(ctx context.Context, id string) (*domain.Cre
 
 // FindByClientID returns credits for a specific client.
 func (r *SQLiteCreditRepo) FindByClientID(ctx context.Context, clientID string) ([]domain.Credit, error) {
-	rows, err := r.db.QueryContext(ctx,
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
 		`SELECT id, client_id, client_name, sale_id, amount, remaining_amount, status, created_at, due_date FROM credits WHERE client_id = ? ORDER BY created_at DESC`, clientID)
 	if err != nil {
 		return nil, err
@@ -100,29 +100,64 @@ func (r *SQLiteCreditRepo) FindByClientID(ctx context.Context, clientID string)
 	return credits, rows.Err()
 }
 
+// FindBySaleID returns the credit opened for a sale, if any.
+func (r *SQLiteCreditRepo) FindBySaleID(ctx context.Context, saleID string) (*domain.Credit, error) {
+	var id string
+	err := executor(ctx, r.db).QueryRowContext(ctx, `SELECT id FROM credits WHERE sale_id = ?`, saleID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.FindByID(ctx, id)
+}
+
 // Create inserts a new credit record.
 func (r *SQLiteCreditRepo) Create(ctx context.Context, credit *domain.Credit) error {
-	_, err := r.db.ExecContext(ctx,
+	_, err := executor(ctx, r.db).ExecContext(ctx,
 		`INSERT INTO credits (id, client_id, client_name, sale_id, amount, remaining_amount, status, created_at, due_date) VALUES (?,?,?,?,?,?,?,?,?)`,
 		credit.ID, credit.ClientID, credit.ClientName, credit.SaleID, credit.Amount, credit.RemainingAmount, credit.Status, credit.CreatedAt, credit.DueDate,
 	)
 	return err
 }
 
-// Update modifies an existing credit (remaining_amount, status).
+// Update modifies an existing credit (remaining_amount, status, due_date).
 func (r *SQLiteCreditRepo) Update(ctx context.Context, credit *domain.Credit) error {
-	_, err := r.db.ExecContext(ctx,
-		`UPDATE credits SET remaining_amount=?, status=? WHERE id=?`,
-		credit.RemainingAmount, credit.Status, credit.ID,
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`UPDATE credits SET remaining_amount=?, status=?, due_date=? WHERE id=?`,
+		credit.RemainingAmount, credit.Status, credit.DueDate, credit.ID,
 	)
 	return err
 }
 
+// RemainingByClient returns, per client, the sum of what remains due on
+// their credits. Clients without credit are absent from the map.
+func (r *SQLiteCreditRepo) RemainingByClient(ctx context.Context) (map[string]domain.Money, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT client_id, COALESCE(SUM(remaining_amount), 0) FROM credits GROUP BY client_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make(map[string]domain.Money)
+	for rows.Next() {
+		var clientID string
+		var total domain.Money
+		if err := rows.Scan(&clientID, &total); err != nil {
+			return nil, err
+		}
+		totals[clientID] = total
+	}
+	return totals, rows.Err()
+}
+
 // AddPayment inserts a payment record for a credit.
 func (r *SQLiteCreditRepo) AddPayment(ctx context.Context, payment *domain.Payment) error {
-	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO payments (id, credit_id, amount, date, method) VALUES (?,?,?,?,?)`,
-		payment.ID, payment.CreditID, payment.Amount, payment.Date, payment.Method,
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO payments (id, credit_id, receipt_id, amount, date, method, user_id, user_name) VALUES (?,?,?,?,?,?,?,?)`,
+		payment.ID, payment.CreditID, payment.ReceiptID, payment.Amount, payment.Date, payment.Method, payment.UserID, payment.UserName,
 	)
 	return err
 }
@@ -140,8 +175,8 @@ func (r *SQLiteCreditRepo) scanCredit(rows *sql.Rows) (*domain.Credit, error) {
 }
 
 func (r *SQLiteCreditRepo) findPaymentsByCreditID(ctx context.Context, creditID string) ([]domain.Payment, error) {
-	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, credit_id, amount, date, method FROM payments WHERE credit_id = ? ORDER BY date DESC`, creditID)
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT id, credit_id, receipt_id, amount, date, method, user_id, user_name FROM payments WHERE credit_id = ? ORDER BY date DESC`, creditID)
 	if err != nil {
 		return nil, err
 	}
@@ -150,7 +185,7 @@ func (r *SQLiteCreditRepo) findPaymentsByCreditID(ctx context.Context, creditID
 	var payments []domain.Payment
 	for rows.Next() {
 		var p domain.Payment
-		if err := rows.Scan(&p.ID, &p.CreditID, &p.Amount, &p.Date, &p.Method); err != nil {
+		if err := rows.Scan(&p.ID, &p.CreditID, &p.ReceiptID, &p.Amount, &p.Date, &p.Method, &p.UserID, &p.UserName); err != nil {
 			return nil, err
 		}
 		payments = append(payments, p)
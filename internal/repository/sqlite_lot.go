@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"boucherie-api/internal/domain"
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// SQLiteLotRepo implements port.LotRepository.
+type SQLiteLotRepo struct {
+	db *sql.DB
+}
+
+// NewLotRepo creates a new SQLite-backed lot repository.
+func NewLotRepo(db *sql.DB) *SQLiteLotRepo {
+	return &SQLiteLotRepo{db: db}
+}
+
+// lotColumns selects a lot with the name of its product.
+const lotColumns = `SELECT l.id, l.number, l.product_id, COALESCE(p.name, ''), l.carcass_id, l.parent_id,
+	l.reception_id, l.supplier_id, l.supplier_name, l.origin, l.slaughter_date, l.approval_number, l.animal_id,
+	l.quantity, l.remaining, l.received_at
+	FROM lots l LEFT JOIN products p ON p.id = l.product_id`
+
+func scanLot(row rowScanner) (*domain.Lot, error) {
+	var l domain.Lot
+	err := row.Scan(&l.ID, &l.Number, &l.ProductID, &l.ProductName, &l.CarcassID, &l.ParentID,
+		&l.ReceptionID, &l.SupplierID, &l.SupplierName, &l.Origin, &l.SlaughterDate, &l.ApprovalNumber, &l.AnimalID,
+		&l.Quantity, &l.Remaining, &l.ReceivedAt)
+	if err != nil {
+		return nil, err
+	}
+	l.Remaining = domain.RoundKg(l.Remaining)
+	return &l, nil
+}
+
+// FindAll returns lots matching the filter, most recently received first.
+func (r *SQLiteLotRepo) FindAll(ctx context.Context, filter domain.LotFilter) ([]domain.Lot, error) {
+	query := lotColumns + ` WHERE 1=1`
+	var args []interface{}
+	if filter.ProductID != "" {
+		query += ` AND l.product_id = ?`
+		args = append(args, filter.ProductID)
+	}
+	if filter.SupplierID != "" {
+		query += ` AND l.supplier_id = ?`
+		args = append(args, filter.SupplierID)
+	}
+	if filter.Number != "" {
+		query += ` AND l.number = ?`
+		args = append(args, filter.Number)
+	}
+	if filter.OpenOnly {
+		query += ` AND l.remaining > 0`
+	}
+	return r.findLots(ctx, query+` ORDER BY l.received_at DESC`, args...)
+}
+
+// FindByID returns a single lot.
+func (r *SQLiteLotRepo) FindByID(ctx context.Context, id string) (*domain.Lot, error) {
+	l, err := scanLot(executor(ctx, r.db).QueryRowContext(ctx, lotColumns+` WHERE l.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return l, err
+}
+
+// FindOpen returns the lots of a product with meat left, oldest first.
+func (r *SQLiteLotRepo) FindOpen(ctx context.Context, productID string) ([]domain.Lot, error) {
+	return r.findLots(ctx, lotColumns+` WHERE l.product_id = ? AND l.remaining > 0 ORDER BY l.received_at, l.rowid`, productID)
+}
+
+// FindByCarcass returns the lot a carcass was received with, if any.
+func (r *SQLiteLotRepo) FindByCarcass(ctx context.Context, carcassID string) (*domain.Lot, error) {
+	l, err := scanLot(executor(ctx, r.db).QueryRowContext(ctx,
+		lotColumns+` WHERE l.carcass_id = ? AND l.product_id = '' ORDER BY l.received_at LIMIT 1`, carcassID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return l, err
+}
+
+// FindDerived returns the lots derived from a lot.
+func (r *SQLiteLotRepo) FindDerived(ctx context.Context, parentID string) ([]domain.Lot, error) {
+	return r.findLots(ctx, lotColumns+` WHERE l.parent_id = ? ORDER BY l.received_at, l.rowid`, parentID)
+}
+
+// Create inserts a new lot.
+func (r *SQLiteLotRepo) Create(ctx context.Context, l *domain.Lot) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO lots (id, number, product_id, carcass_id, parent_id, reception_id, supplier_id, supplier_name, origin, slaughter_date, approval_number, animal_id, quantity, remaining, received_at) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+		l.ID, l.Number, l.ProductID, l.CarcassID, l.ParentID, l.ReceptionID, l.SupplierID, l.SupplierName,
+		l.Origin, l.SlaughterDate, l.ApprovalNumber, l.AnimalID, l.Quantity, l.Remaining, l.ReceivedAt,
+	)
+	return err
+}
+
+// Take records that a sale line took qty kg from a lot and lowers what is
+// left of the lot, never below zero.
+func (r *SQLiteLotRepo) Take(ctx context.Context, saleItemID, lotID string, qty float64) error {
+	return withTx(ctx, r.db, func(q dbtx) error {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO sale_item_lots (sale_item_id, lot_id, quantity) VALUES (?,?,?)`, saleItemID, lotID, qty)
+		if err != nil {
+			return err
+		}
+		_, err = q.ExecContext(ctx, `UPDATE lots SET remaining = MAX(remaining - ?, 0) WHERE id = ?`, qty, lotID)
+		return err
+	})
+}
+
+// Return gives back to a lot qty kg a sale line took from it, e.g. on a
+// refund: the sale line keeps what it did not return, and the lot never holds
+// more than it was received with.
+func (r *SQLiteLotRepo) Return(ctx context.Context, saleItemID, lotID string, qty float64) error {
+	return withTx(ctx, r.db, func(q dbtx) error {
+		// A line returning all it took from the lot no longer comes from it
+		_, err := q.ExecContext(ctx,
+			`DELETE FROM sale_item_lots WHERE sale_item_id = ? AND lot_id = ? AND quantity - ? < 0.0005`, saleItemID, lotID, qty)
+		if err != nil {
+			return err
+		}
+		_, err = q.ExecContext(ctx,
+			`UPDATE sale_item_lots SET quantity = quantity - ? WHERE sale_item_id = ? AND lot_id = ?`, qty, saleItemID, lotID)
+		if err != nil {
+			return err
+		}
+		_, err = q.ExecContext(ctx, `UPDATE lots SET remaining = MIN(remaining + ?, quantity) WHERE id = ?`, qty, lotID)
+		return err
+	})
+}
+
+// Draw lowers what is left of a lot by qty kg, never below zero, for stock
+// leaving without a sale: waste, a count found short, a transfer.
+func (r *SQLiteLotRepo) Draw(ctx context.Context, lotID string, qty float64) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx, `UPDATE lots SET remaining = MAX(remaining - ?, 0) WHERE id = ?`, qty, lotID)
+	return err
+}
+
+// FindByItem returns the lots a sale line was taken from, in the order they
+// were taken.
+func (r *SQLiteLotRepo) FindByItem(ctx context.Context, saleItemID string) ([]domain.SaleItemLot, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT sil.lot_id, COALESCE(l.number, ''), sil.quantity
+		 FROM sale_item_lots sil LEFT JOIN lots l ON l.id = sil.lot_id
+		 WHERE sil.sale_item_id = ? ORDER BY sil.rowid`, saleItemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lots := []domain.SaleItemLot{}
+	for rows.Next() {
+		var l domain.SaleItemLot
+		if err := rows.Scan(&l.LotID, &l.LotNumber, &l.Quantity); err != nil {
+			return nil, err
+		}
+		l.Quantity = domain.RoundKg(l.Quantity)
+		lots = append(lots, l)
+	}
+	return lots, rows.Err()
+}
+
+// FindBySale returns the lots the lines of a sale were taken from.
+func (r *SQLiteLotRepo) FindBySale(ctx context.Context, saleID string) ([]domain.SaleLot, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT si.id, si.product_id, si.product_name, sil.quantity, sil.lot_id
+		 FROM sale_item_lots sil JOIN sale_items si ON si.id = sil.sale_item_id
+		 WHERE si.sale_id = ? ORDER BY si.rowid, sil.rowid`, saleID)
+	if err != nil {
+		return nil, err
+	}
+	list := []domain.SaleLot{}
+	for rows.Next() {
+		var sl domain.SaleLot
+		if err := rows.Scan(&sl.SaleItemID, &sl.ProductID, &sl.ProductName, &sl.Quantity, &sl.Lot.ID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		list = append(list, sl)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		l, err := r.FindByID(ctx, list[i].Lot.ID)
+		if err != nil {
+			return nil, err
+		}
+		if l != nil {
+			list[i].Lot = *l
+		}
+	}
+	return list, nil
+}
+
+// FindSales returns the sale lines taken from any of the lots, with the
+// client who received them, oldest first.
+func (r *SQLiteLotRepo) FindSales(ctx context.Context, lotIDs []string) ([]domain.RecallSale, error) {
+	sales := []domain.RecallSale{}
+	if len(lotIDs) == 0 {
+		return sales, nil
+	}
+	args := make([]interface{}, len(lotIDs))
+	for i, id := range lotIDs {
+		args[i] = id
+	}
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT s.id, si.id, s.date, s.client_id, s.client_name, COALESCE(c.phone, ''),
+		        si.product_id, si.product_name, sil.lot_id, sil.quantity
+		 FROM sale_item_lots sil
+		 JOIN sale_items si ON si.id = sil.sale_item_id
+		 JOIN sales s ON s.id = si.sale_id
+		 LEFT JOIN clients c ON c.id = s.client_id
+		 WHERE sil.lot_id IN (?`+strings.Repeat(",?", len(lotIDs)-1)+`)
+		 ORDER BY s.date`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rs domain.RecallSale
+		if err := rows.Scan(&rs.SaleID, &rs.SaleItemID, &rs.Date, &rs.ClientID, &rs.ClientName, &rs.ClientPhone,
+			&rs.ProductID, &rs.ProductName, &rs.LotID, &rs.Quantity); err != nil {
+			return nil, err
+		}
+		sales = append(sales, rs)
+	}
+	return sales, rows.Err()
+}
+
+func (r *SQLiteLotRepo) findLots(ctx context.Context, query string, args ...interface{}) ([]domain.Lot, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lots := []domain.Lot{}
+	for rows.Next() {
+		l, err := scanLot(rows)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, *l)
+	}
+	return lots, rows.Err()
+}
@@ -4,6 +4,7 @@ import (
 	"boucherie-api/internal/domain"
 	"context"
 	"database/sql"
+	"time"
 )
 
 // SQLiteOrderRepo implements port.OrderRepository.
@@ -16,17 +17,117 @@ func NewOrderRepo(db *sql.DB) *SQLiteOrderRepo {
 	return &SQLiteOrderRepo{db: db}
 }
 
+// orderColumns selects an order without its items. The deposit is what was
+// received against the order less what was paid back.
+const orderColumns = `SELECT id, client_id, client_name, client_phone, pickup_date, notes, status, sale_id, created_at,
+	confirmed_at, ready_at, delivered_at, cancelled_at,
+	COALESCE((SELECT SUM(CASE d.kind WHEN 'acompte' THEN d.amount ELSE -d.amount END)
+		FROM order_deposits d WHERE d.order_id = orders.id), 0),
+	deposit_outcome, recurring_order_id
+	FROM orders`
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanOrder reads a row selected with orderColumns.
+func scanOrder(row rowScanner) (*domain.Order, error) {
+	var o domain.Order
+	err := row.Scan(&o.ID, &o.ClientID, &o.ClientName, &o.ClientPhone, &o.PickupDate, &o.Notes, &o.Status, &o.SaleID, &o.CreatedAt,
+		&o.ConfirmedAt, &o.ReadyAt, &o.DeliveredAt, &o.CancelledAt, &o.Deposit, &o.DepositOutcome, &o.RecurringOrderID)
+	if err != nil {
+		return nil, err
+	}
+	o.PickupDate = time.Date(o.PickupDate.Year(), o.PickupDate.Month(), o.PickupDate.Day(), 0, 0, 0, 0, time.Local)
+	return &o, nil
+}
+
+// pickupDay returns the value stored for a pickup date: midnight UTC of its
+// calendar day, so that date(pickup_date) gives the day back whatever the
+// server's time zone. Orders carry it as local midnight.
+func pickupDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// localDay returns local midnight of a YYYY-MM-DD day. Filters on the day a
+// timestamp falls on compare it with datetime() against local day bounds, so
+// days are the shop's and not UTC ones.
+func localDay(day string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", day, time.Local)
+}
+
 // FindAll returns orders, optionally filtered by status.
 func (r *SQLiteOrderRepo) FindAll(ctx context.Context, status *domain.OrderStatus) ([]domain.Order, error) {
-	query := `SELECT id, client_id, client_name, client_phone, pickup_date, notes, status, created_at FROM orders WHERE 1=1`
+	query := orderColumns + ` WHERE 1=1`
 	var args []interface{}
 	if status != nil {
 		query += ` AND status = ?`
 		args = append(args, string(*status))
 	}
 	query += ` ORDER BY created_at DESC`
+	return r.findOrders(ctx, query, args...)
+}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+// FindByOccurrence returns the order created from a recurring order for date.
+func (r *SQLiteOrderRepo) FindByOccurrence(ctx context.Context, recurringOrderID, date string) (*domain.Order, error) {
+	o, err := scanOrder(executor(ctx, r.db).QueryRowContext(ctx,
+		orderColumns+` WHERE recurring_order_id = ? AND date(pickup_date) = ?`, recurringOrderID, date))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	items, err := r.findItemsByOrderID(ctx, o.ID)
+	if err != nil {
+		return nil, err
+	}
+	o.Items = items
+	o.Estimate()
+	return o, nil
+}
+
+// FindByPickupDate returns the orders picked up between from and to, by pickup date.
+func (r *SQLiteOrderRepo) FindByPickupDate(ctx context.Context, from, to string) ([]domain.Order, error) {
+	return r.findOrders(ctx,
+		orderColumns+` WHERE date(pickup_date) BETWEEN ? AND ? ORDER BY pickup_date, created_at`, from, to)
+}
+
+// CountByPickupDate counts orders by status for each pickup day between from and to.
+func (r *SQLiteOrderRepo) CountByPickupDate(ctx context.Context, from, to string) ([]domain.CalendarDay, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT date(pickup_date) AS day, status, COUNT(*) FROM orders
+		 WHERE date(pickup_date) BETWEEN ? AND ?
+		 GROUP BY day, status ORDER BY day`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []domain.CalendarDay
+	for rows.Next() {
+		var day string
+		var status domain.OrderStatus
+		var n int
+		if err := rows.Scan(&day, &status, &n); err != nil {
+			return nil, err
+		}
+		if len(days) == 0 || days[len(days)-1].Date != day {
+			days = append(days, domain.CalendarDay{Date: day, ByStatus: map[domain.OrderStatus]int{}})
+		}
+		d := &days[len(days)-1]
+		d.ByStatus[status] = n
+		if status != domain.OrderStatusAnnulee {
+			d.Total += n
+		}
+	}
+	return days, rows.Err()
+}
+
+// findOrders runs a query selecting orderColumns and loads each order's items.
+func (r *SQLiteOrderRepo) findOrders(ctx context.Context, query string, args ...interface{}) ([]domain.Order, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -34,8 +135,8 @@ func (r *SQLiteOrderRepo) FindAll(ctx context.Context, status *domain.OrderStatu
 
 	var orders []domain.Order
 	for rows.Next() {
-		var o domain.Order
-		if err := rows.Scan(&o.ID, &o.ClientID, &o.ClientName, &o.ClientPhone, &o.PickupDate, &o.Notes, &o.Status, &o.CreatedAt); err != nil {
+		o, err := scanOrder(rows)
+		if err != nil {
 			return nil, err
 		}
 		items, err := r.findItemsByOrderID(ctx, o.ID)
@@ -43,17 +144,15 @@ func (r *SQLiteOrderRepo) FindAll(ctx context.Context, status *domain.OrderStatu
 			return nil, err
 		}
 		o.Items = items
-		orders = append(orders, o)
+		o.Estimate()
+		orders = append(orders, *o)
 	}
 	return orders, rows.Err()
 }
 
 // FindByID returns a single order with its items.
 func (r *SQLiteOrderRepo) FindByID(ctx context.Context, id string) (*domain.Order, error) {
-	var o domain.Order
-	err := r.db.QueryRowContext(ctx,
-		`SELECT id, client_id, client_name, client_phone, pickup_date, notes, status, created_at FROM orders WHERE id = ?`, id,
-	).Scan(&o.ID, &o.ClientID, &o.ClientName, &o.ClientPhone, &o.PickupDate, &o.Notes, &o.Status, &o.CreatedAt)
+	o, err := scanOrder(executor(ctx, r.db).QueryRowContext(ctx, orderColumns+` WHERE id = ?`, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -65,55 +164,104 @@ func (r *SQLiteOrderRepo) FindByID(ctx context.Context, id string) (*domain.Orde
 		return nil, err
 	}
 	o.Items = items
-	return &o, nil
+	o.Estimate()
+	return o, nil
 }
 
 // Create inserts an order and its items in a transaction.
 func (r *SQLiteOrderRepo) Create(ctx context.Context, order *domain.Order) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
-	_, err = tx.ExecContext(ctx,
-		`INSERT INTO orders (id, client_id, client_name, client_phone, pickup_date, notes, status, created_at) VALUES (?,?,?,?,?,?,?,?)`,
-		order.ID, order.ClientID, order.ClientName, order.ClientPhone, order.PickupDate, order.Notes, order.Status, order.CreatedAt,
-	)
-	if err != nil {
-		return err
-	}
-
-	for _, item := range order.Items {
-		_, err = tx.ExecContext(ctx,
-			`INSERT INTO order_items (id, order_id, product_id, product_name, quantity) VALUES (?,?,?,?,?)`,
-			item.ID, order.ID, item.ProductID, item.ProductName, item.Quantity,
+	return withTx(ctx, r.db, func(q dbtx) error {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO orders (id, client_id, client_name, client_phone, pickup_date, notes, status, recurring_order_id, created_at) VALUES (?,?,?,?,?,?,?,?,?)`,
+			order.ID, order.ClientID, order.ClientName, order.ClientPhone, pickupDay(order.PickupDate), order.Notes, order.Status, order.RecurringOrderID, order.CreatedAt,
 		)
 		if err != nil {
 			return err
 		}
-	}
-	return tx.Commit()
+
+		for _, item := range order.Items {
+			_, err = q.ExecContext(ctx,
+				`INSERT INTO order_items (id, order_id, product_id, product_name, quantity, price_per_kg) VALUES (?,?,?,?,?,?)`,
+				item.ID, order.ID, item.ProductID, item.ProductName, item.Quantity, item.PricePerKg,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
-// Update modifies an existing order (status, pickup_date, notes).
+// Update modifies an existing order (status and its timestamps, pickup_date, notes, sale_id, deposit_outcome).
 func (r *SQLiteOrderRepo) Update(ctx context.Context, order *domain.Order) error {
-	_, err := r.db.ExecContext(ctx,
-		`UPDATE orders SET status=?, pickup_date=?, notes=? WHERE id=?`,
-		order.Status, order.PickupDate, order.Notes, order.ID,
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`UPDATE orders SET status=?, pickup_date=?, notes=?, sale_id=?, deposit_outcome=?, confirmed_at=?, ready_at=?, delivered_at=?, cancelled_at=? WHERE id=?`,
+		order.Status, pickupDay(order.PickupDate), order.Notes, order.SaleID, order.DepositOutcome, order.ConfirmedAt, order.ReadyAt, order.DeliveredAt, order.CancelledAt, order.ID,
 	)
 	return err
 }
 
 // Delete removes an order and its items (CASCADE).
 func (r *SQLiteOrderRepo) Delete(ctx context.Context, id string) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM orders WHERE id = ?`, id)
+	_, err := executor(ctx, r.db).ExecContext(ctx, `DELETE FROM orders WHERE id = ?`, id)
+	return err
+}
+
+// CreateItem adds a line to an existing order.
+func (r *SQLiteOrderRepo) CreateItem(ctx context.Context, item *domain.OrderItem) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO order_items (id, order_id, product_id, product_name, quantity, price_per_kg) VALUES (?,?,?,?,?,?)`,
+		item.ID, item.OrderID, item.ProductID, item.ProductName, item.Quantity, item.PricePerKg,
+	)
+	return err
+}
+
+// UpdateItem changes the quantity of an order line.
+func (r *SQLiteOrderRepo) UpdateItem(ctx context.Context, item *domain.OrderItem) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`UPDATE order_items SET quantity=? WHERE id=?`, item.Quantity, item.ID)
+	return err
+}
+
+// DeleteItem removes an order line.
+func (r *SQLiteOrderRepo) DeleteItem(ctx context.Context, id string) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx, `DELETE FROM order_items WHERE id = ?`, id)
 	return err
 }
 
+// CreateDeposit records money received or paid back against an order.
+func (r *SQLiteOrderRepo) CreateDeposit(ctx context.Context, d *domain.OrderDeposit) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO order_deposits (id, order_id, kind, amount, method, user_id, user_name, date) VALUES (?,?,?,?,?,?,?,?)`,
+		d.ID, d.OrderID, d.Kind, d.Amount, d.Method, d.UserID, d.UserName, d.Date,
+	)
+	return err
+}
+
+// FindDeposits returns the deposits of an order, oldest first.
+func (r *SQLiteOrderRepo) FindDeposits(ctx context.Context, orderID string) ([]domain.OrderDeposit, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT id, order_id, kind, amount, method, user_id, user_name, date FROM order_deposits
+		 WHERE order_id = ? ORDER BY date`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deposits := []domain.OrderDeposit{}
+	for rows.Next() {
+		var d domain.OrderDeposit
+		if err := rows.Scan(&d.ID, &d.OrderID, &d.Kind, &d.Amount, &d.Method, &d.UserID, &d.UserName, &d.Date); err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, d)
+	}
+	return deposits, rows.Err()
+}
+
 func (r *SQLiteOrderRepo) findItemsByOrderID(ctx context.Context, orderID string) ([]domain.OrderItem, error) {
-	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, order_id, product_id, product_name, quantity FROM order_items WHERE order_id = ?`, orderID)
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT id, order_id, product_id, product_name, quantity, price_per_kg FROM order_items WHERE order_id = ?`, orderID)
 	if err != nil {
 		return nil, err
 	}
@@ -122,7 +270,7 @@ func (r *SQLiteOrderRepo) findItemsByOrderID(ctx context.Context, orderID string
 	var items []domain.OrderItem
 	for rows.Next() {
 		var item domain.OrderItem
-		if err := rows.Scan(&item.ID, &item.OrderID, &item.ProductID, &item.ProductName, &item.Quantity); err != nil {
+		if err := rows.Scan(&item.ID, &item.OrderID, &item.ProductID, &item.ProductName, &item.Quantity, &item.PricePerKg); err != nil {
 			return nil, err
 		}
 		items = append(items, item)
@@ -26,7 +26,7 @@ func (r *SQLiteProductRepo) FindAll(ctx context.Context, category *domain.MeatCa
 	}
 	query += ` ORDER BY name`
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +49,7 @@ func (r *SQLiteProductRepo) FindAll(ctx context.Context, category *domain.MeatCa
 func (r *SQLiteProductRepo) FindByID(ctx context.Context, id string) (*domain.Product, error) {
 	var p domain.Product
 	var inStock int
-	err := r.db.QueryRowContext(ctx,
+	err := executor(ctx, r.db).QueryRowContext(ctx,
 		`SELECT id, name, category, price_per_kg, image, in_stock FROM products WHERE id = ?`, id,
 	).Scan(&p.ID, &p.Name, &p.Category, &p.PricePerKg, &p.Image, &inStock)
 	if err == sql.ErrNoRows {
@@ -68,7 +68,7 @@ func (r *SQLiteProductRepo) Create(ctx context.Context, product *domain.Product)
 	if product.InStock {
 		inStock = 1
 	}
-	_, err := r.db.ExecContext(ctx,
+	_, err := executor(ctx, r.db).ExecContext(ctx,
 		`INSERT INTO products (id, name, category, price_per_kg, image, in_stock) VALUES (?,?,?,?,?,?)`,
 		product.ID, product.Name, product.Category, product.PricePerKg, product.Image, inStock,
 	)
@@ -81,7 +81,7 @@ func (r *SQLiteProductRepo) Update(ctx context.Context, product *domain.Product)
 	if product.InStock {
 		inStock = 1
 	}
-	_, err := r.db.ExecContext(ctx,
+	_, err := executor(ctx, r.db).ExecContext(ctx,
 		`UPDATE products SET name=?, category=?, price_per_kg=?, image=?, in_stock=? WHERE id=?`,
 		product.Name, product.Category, product.PricePerKg, product.Image, inStock, product.ID,
 	)
@@ -90,6 +90,6 @@ func (r *SQLiteProductRepo) Update(ctx context.Context, product *domain.Product)
 
 // Delete removes a product by ID.
 func (r *SQLiteProductRepo) Delete(ctx context.Context, id string) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM products WHERE id = ?`, id)
+	_, err := executor(ctx, r.db).ExecContext(ctx, `DELETE FROM products WHERE id = ?`, id)
 	return err
 }
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"boucherie-api/internal/domain"
+	"context"
+	"database/sql"
+)
+
+// SQLiteReceptionRepo implements port.ReceptionRepository.
+type SQLiteReceptionRepo struct {
+	db *sql.DB
+}
+
+// NewReceptionRepo creates a new SQLite-backed reception repository.
+func NewReceptionRepo(db *sql.DB) *SQLiteReceptionRepo {
+	return &SQLiteReceptionRepo{db: db}
+}
+
+// receptionColumns selects a reception without its items.
+const receptionColumns = `SELECT id, supplier_id, supplier_name, invoice_id, total, note, user_id, user_name, date
+	FROM receptions`
+
+func scanReception(row rowScanner) (*domain.Reception, error) {
+	var rc domain.Reception
+	var invoiceID sql.NullString
+	err := row.Scan(&rc.ID, &rc.SupplierID, &rc.SupplierName, &invoiceID, &rc.Total, &rc.Note, &rc.UserID, &rc.UserName, &rc.Date)
+	if err != nil {
+		return nil, err
+	}
+	if invoiceID.Valid {
+		rc.InvoiceID = &invoiceID.String
+	}
+	rc.Items = []domain.ReceptionItem{}
+	return &rc, nil
+}
+
+// FindBySupplier returns a supplier's receptions with their items, newest
+// first, optionally only those not invoiced yet.
+func (r *SQLiteReceptionRepo) FindBySupplier(ctx context.Context, supplierID string, uninvoicedOnly bool) ([]domain.Reception, error) {
+	query := receptionColumns + ` WHERE supplier_id = ?`
+	if uninvoicedOnly {
+		query += ` AND invoice_id IS NULL`
+	}
+	query += ` ORDER BY date DESC`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	list := []domain.Reception{}
+	for rows.Next() {
+		rc, err := scanReception(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		list = append(list, *rc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		if err := r.loadItems(ctx, &list[i]); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// FindByID returns a single reception with its items.
+func (r *SQLiteReceptionRepo) FindByID(ctx context.Context, id string) (*domain.Reception, error) {
+	rc, err := scanReception(executor(ctx, r.db).QueryRowContext(ctx, receptionColumns+` WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadItems(ctx, rc); err != nil {
+		return nil, err
+	}
+	return rc, nil
+}
+
+// Create inserts a reception and its items in a transaction.
+func (r *SQLiteReceptionRepo) Create(ctx context.Context, rc *domain.Reception) error {
+	return withTx(ctx, r.db, func(q dbtx) error {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO receptions (id, supplier_id, supplier_name, invoice_id, total, note, user_id, user_name, date) VALUES (?,?,?,?,?,?,?,?,?)`,
+			rc.ID, rc.SupplierID, rc.SupplierName, rc.InvoiceID, rc.Total, rc.Note, rc.UserID, rc.UserName, rc.Date,
+		)
+		if err != nil {
+			return err
+		}
+		for _, item := range rc.Items {
+			_, err := q.ExecContext(ctx,
+				`INSERT INTO reception_items (id, reception_id, product_id, carcass_id, lot_id, name, quantity, price_per_kg, subtotal) VALUES (?,?,?,?,?,?,?,?,?)`,
+				item.ID, rc.ID, item.ProductID, item.CarcassID, item.LotID, item.Name, item.Quantity, item.PricePerKg, item.Subtotal,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetInvoice links a reception to the invoice covering it.
+func (r *SQLiteReceptionRepo) SetInvoice(ctx context.Context, id, invoiceID string) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx, `UPDATE receptions SET invoice_id = ? WHERE id = ?`, invoiceID, id)
+	return err
+}
+
+// loadItems reads the lines of a reception.
+func (r *SQLiteReceptionRepo) loadItems(ctx context.Context, rc *domain.Reception) error {
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT id, product_id, carcass_id, lot_id, name, quantity, price_per_kg, subtotal
+		 FROM reception_items WHERE reception_id = ? ORDER BY rowid`, rc.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.ReceptionItem
+		if err := rows.Scan(&item.ID, &item.ProductID, &item.CarcassID, &item.LotID, &item.Name, &item.Quantity, &item.PricePerKg, &item.Subtotal); err != nil {
+			return err
+		}
+		rc.Items = append(rc.Items, item)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"boucherie-api/internal/domain"
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLiteRecurringOrderRepo implements port.RecurringOrderRepository.
+type SQLiteRecurringOrderRepo struct {
+	db *sql.DB
+}
+
+// NewRecurringOrderRepo creates a new SQLite-backed recurring order repository.
+func NewRecurringOrderRepo(db *sql.DB) *SQLiteRecurringOrderRepo {
+	return &SQLiteRecurringOrderRepo{db: db}
+}
+
+// recurringColumns selects a template without its items and skips.
+const recurringColumns = `SELECT id, client_id, client_name, weekdays, start_date, end_date, notes, active, created_at
+	FROM recurring_orders`
+
+func scanRecurring(row rowScanner) (*domain.RecurringOrder, error) {
+	var ro domain.RecurringOrder
+	var weekdays string
+	err := row.Scan(&ro.ID, &ro.ClientID, &ro.ClientName, &weekdays, &ro.StartDate, &ro.EndDate, &ro.Notes, &ro.Active, &ro.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	ro.Weekdays = parseWeekdays(weekdays)
+	return &ro, nil
+}
+
+// FindAll returns templates, optionally only the active ones.
+func (r *SQLiteRecurringOrderRepo) FindAll(ctx context.Context, activeOnly bool) ([]domain.RecurringOrder, error) {
+	query := recurringColumns
+	if activeOnly {
+		query += ` WHERE active = 1`
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var list []domain.RecurringOrder
+	for rows.Next() {
+		ro, err := scanRecurring(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		list = append(list, *ro)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		if err := r.loadDetails(ctx, &list[i]); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// FindByID returns a single template with its items and skips.
+func (r *SQLiteRecurringOrderRepo) FindByID(ctx context.Context, id string) (*domain.RecurringOrder, error) {
+	ro, err := scanRecurring(executor(ctx, r.db).QueryRowContext(ctx, recurringColumns+` WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadDetails(ctx, ro); err != nil {
+		return nil, err
+	}
+	return ro, nil
+}
+
+// Create inserts a template and its items in a transaction.
+func (r *SQLiteRecurringOrderRepo) Create(ctx context.Context, ro *domain.RecurringOrder) error {
+	return withTx(ctx, r.db, func(q dbtx) error {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO recurring_orders (id, client_id, client_name, weekdays, start_date, end_date, notes, active, created_at) VALUES (?,?,?,?,?,?,?,?,?)`,
+			ro.ID, ro.ClientID, ro.ClientName, formatWeekdays(ro.Weekdays), ro.StartDate, ro.EndDate, ro.Notes, ro.Active, ro.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		return insertRecurringItems(ctx, q, ro)
+	})
+}
+
+// Update saves the template and replaces its items in a transaction.
+func (r *SQLiteRecurringOrderRepo) Update(ctx context.Context, ro *domain.RecurringOrder) error {
+	return withTx(ctx, r.db, func(q dbtx) error {
+		_, err := q.ExecContext(ctx,
+			`UPDATE recurring_orders SET weekdays=?, end_date=?, notes=?, active=? WHERE id=?`,
+			formatWeekdays(ro.Weekdays), ro.EndDate, ro.Notes, ro.Active, ro.ID,
+		)
+		if err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, `DELETE FROM recurring_order_items WHERE recurring_order_id = ?`, ro.ID); err != nil {
+			return err
+		}
+		return insertRecurringItems(ctx, q, ro)
+	})
+}
+
+// Delete removes a template, its items and skips (CASCADE).
+func (r *SQLiteRecurringOrderRepo) Delete(ctx context.Context, id string) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx, `DELETE FROM recurring_orders WHERE id = ?`, id)
+	return err
+}
+
+// AddSkip marks the occurrence on date as not to be ordered.
+func (r *SQLiteRecurringOrderRepo) AddSkip(ctx context.Context, id, date string) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`INSERT OR IGNORE INTO recurring_order_skips (recurring_order_id, date) VALUES (?,?)`, id, date)
+	return err
+}
+
+func insertRecurringItems(ctx context.Context, q dbtx, ro *domain.RecurringOrder) error {
+	for _, item := range ro.Items {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO recurring_order_items (id, recurring_order_id, product_id, product_name, quantity) VALUES (?,?,?,?,?)`,
+			item.ID, ro.ID, item.ProductID, item.ProductName, item.Quantity,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadDetails reads the items and skipped occurrences of a template.
+func (r *SQLiteRecurringOrderRepo) loadDetails(ctx context.Context, ro *domain.RecurringOrder) error {
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT id, recurring_order_id, product_id, product_name, quantity FROM recurring_order_items WHERE recurring_order_id = ?`, ro.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item domain.RecurringOrderItem
+		if err := rows.Scan(&item.ID, &item.RecurringOrderID, &item.ProductID, &item.ProductName, &item.Quantity); err != nil {
+			return err
+		}
+		ro.Items = append(ro.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	skips, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT date FROM recurring_order_skips WHERE recurring_order_id = ? ORDER BY date`, ro.ID)
+	if err != nil {
+		return err
+	}
+	defer skips.Close()
+	ro.Skips = []string{}
+	for skips.Next() {
+		var d string
+		if err := skips.Scan(&d); err != nil {
+			return err
+		}
+		ro.Skips = append(ro.Skips, d)
+	}
+	return skips.Err()
+}
+
+// formatWeekdays stores weekdays as "2,5".
+func formatWeekdays(days []time.Weekday) string {
+	parts := make([]string, len(days))
+	for i, d := range days {
+		parts[i] = strconv.Itoa(int(d))
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseWeekdays(s string) []time.Weekday {
+	var days []time.Weekday
+	for _, part := range strings.Split(s, ",") {
+		if n, err := strconv.Atoi(part); err == nil {
+			days = append(days, time.Weekday(n))
+		}
+	}
+	return days
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"boucherie-api/internal/domain"
+	"context"
+	"database/sql"
+)
+
+// SQLiteRefundRepo implements port.RefundRepository.
+type SQLiteRefundRepo struct {
+	db *sql.DB
+}
+
+// NewRefundRepo creates a new SQLite-backed refund repository.
+func NewRefundRepo(db *sql.DB) *SQLiteRefundRepo {
+	return &SQLiteRefundRepo{db: db}
+}
+
+// FindBySaleID returns every refund recorded against a sale, oldest first.
+func (r *SQLiteRefundRepo) FindBySaleID(ctx context.Context, saleID string) ([]domain.Refund, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT id, sale_id, client_id, kind, total, credit_amount, cash_amount, method, reason, date FROM refunds WHERE sale_id = ? ORDER BY date`, saleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refunds []domain.Refund
+	for rows.Next() {
+		var rf domain.Refund
+		if err := rows.Scan(&rf.ID, &rf.SaleID, &rf.ClientID, &rf.Kind, &rf.Total, &rf.CreditAmount, &rf.CashAmount, &rf.Method, &rf.Reason, &rf.Date); err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, rf)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range refunds {
+		items, err := r.findItemsByRefundID(ctx, refunds[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		refunds[i].Items = items
+	}
+	return refunds, nil
+}
+
+// Create inserts a refund and its items in a single transaction.
+func (r *SQLiteRefundRepo) Create(ctx context.Context, refund *domain.Refund) error {
+	return withTx(ctx, r.db, func(q dbtx) error {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO refunds (id, sale_id, client_id, kind, total, credit_amount, cash_amount, method, reason, date) VALUES (?,?,?,?,?,?,?,?,?,?)`,
+			refund.ID, refund.SaleID, refund.ClientID, refund.Kind, refund.Total, refund.CreditAmount, refund.CashAmount, refund.Method, refund.Reason, refund.Date,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range refund.Items {
+			_, err = q.ExecContext(ctx,
+				`INSERT INTO refund_items (id, refund_id, sale_item_id, product_id, product_name, quantity, amount) VALUES (?,?,?,?,?,?,?)`,
+				item.ID, refund.ID, item.SaleItemID, item.ProductID, item.ProductName, item.Quantity, item.Amount,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *SQLiteRefundRepo) findItemsByRefundID(ctx context.Context, refundID string) ([]domain.RefundItem, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT id, refund_id, sale_item_id, product_id, product_name, quantity, amount FROM refund_items WHERE refund_id = ?`, refundID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.RefundItem
+	for rows.Next() {
+		var item domain.RefundItem
+		if err := rows.Scan(&item.ID, &item.RefundID, &item.SaleItemID, &item.ProductID, &item.ProductName, &item.Quantity, &item.Amount); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -4,6 +4,7 @@ import (
 	"boucherie-api/internal/domain"
 	"context"
 	"database/sql"
+	"time"
 )
 
 // SQLiteSaleRepo implements port.SaleRepository.
@@ -16,22 +17,37 @@ func NewSaleRepo(db *sql.DB) *SQLiteSaleRepo {
 	return &SQLiteSaleRepo{db: db}
 }
 
-// FindAll returns sales with optional filtering by client and/or date.
-func (r *SQLiteSaleRepo) FindAll(ctx context.Context, clientID *string, date *string) ([]domain.Sale, error) {
-	query := `SELECT id, client_id, client_name, total, paid_amount, credit_amount, date FROM sales WHERE 1=1`
+// saleColumns selects a sale together with its refunded amount and derived status.
+const saleColumns = `SELECT s.id, s.client_id, s.client_name, s.total, s.paid_amount, s.deposit_amount, s.credit_amount, s.payment_method, s.user_id, s.user_name, s.credit_override_by, s.order_id, s.client_balance, s.date,
+	COALESCE(r.refunded, 0),
+	CASE
+		WHEN r.cancelled > 0 THEN 'annulee'
+		WHEN r.refunded >= s.total THEN 'remboursee'
+		WHEN r.refunded > 0 THEN 'remboursee_partiellement'
+		ELSE 'validee'
+	END
+	FROM sales s
+	LEFT JOIN (
+		SELECT sale_id, SUM(total) AS refunded, SUM(kind = 'annulation') AS cancelled FROM refunds GROUP BY sale_id
+	) r ON r.sale_id = s.id`
+
+// FindAll returns sales with optional filtering by client and/or the day
+// starting at the given local midnight.
+func (r *SQLiteSaleRepo) FindAll(ctx context.Context, clientID *string, day *time.Time) ([]domain.Sale, error) {
+	query := saleColumns + ` WHERE 1=1`
 	var args []interface{}
 
 	if clientID != nil {
-		query += ` AND client_id = ?`
+		query += ` AND s.client_id = ?`
 		args = append(args, *clientID)
 	}
-	if date != nil {
-		query += ` AND date(date) = ?`
-		args = append(args, *date)
+	if day != nil {
+		query += ` AND datetime(s.date) >= datetime(?) AND datetime(s.date) < datetime(?)`
+		args = append(args, *day, day.AddDate(0, 0, 1))
 	}
-	query += ` ORDER BY date DESC`
+	query += ` ORDER BY s.date DESC`
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -40,7 +56,7 @@ func (r *SQLiteSaleRepo) FindAll(ctx context.Context, clientID *string, date *st
 	var sales []domain.Sale
 	for rows.Next() {
 		var s domain.Sale
-		if err := rows.Scan(&s.ID, &s.ClientID, &s.ClientName, &s.Total, &s.PaidAmount, &s.CreditAmount, &s.Date); err != nil {
+		if err := rows.Scan(&s.ID, &s.ClientID, &s.ClientName, &s.Total, &s.PaidAmount, &s.DepositAmount, &s.CreditAmount, &s.PaymentMethod, &s.UserID, &s.UserName, &s.CreditOverrideBy, &s.OrderID, &s.ClientBalance, &s.Date, &s.RefundedAmount, &s.Status); err != nil {
 			return nil, err
 		}
 		// Load items for this sale
@@ -57,9 +73,8 @@ func (r *SQLiteSaleRepo) FindAll(ctx context.Context, clientID *string, date *st
 // FindByID returns a single sale with its items.
 func (r *SQLiteSaleRepo) FindByID(ctx context.Context, id string) (*domain.Sale, error) {
 	var s domain.Sale
-	err := r.db.QueryRowContext(ctx,
-		`SELECT id, client_id, client_name, total, paid_amount, credit_amount, date FROM sales WHERE id = ?`, id,
-	).Scan(&s.ID, &s.ClientID, &s.ClientName, &s.Total, &s.PaidAmount, &s.CreditAmount, &s.Date)
+	err := executor(ctx, r.db).QueryRowContext(ctx, saleColumns+` WHERE s.id = ?`, id).
+		Scan(&s.ID, &s.ClientID, &s.ClientName, &s.Total, &s.PaidAmount, &s.DepositAmount, &s.CreditAmount, &s.PaymentMethod, &s.UserID, &s.UserName, &s.CreditOverrideBy, &s.OrderID, &s.ClientBalance, &s.Date, &s.RefundedAmount, &s.Status)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -76,35 +91,31 @@ func (r *SQLiteSaleRepo) FindByID(ctx context.Context, id string) (*domain.Sale,
 
 // Create inserts a sale and its items in a single transaction.
 func (r *SQLiteSaleRepo) Create(ctx context.Context, sale *domain.Sale) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
-	_, err = tx.ExecContext(ctx,
-		`INSERT INTO sales (id, client_id, client_name, total, paid_amount, credit_amount, date) VALUES (?,?,?,?,?,?,?)`,
-		sale.ID, sale.ClientID, sale.ClientName, sale.Total, sale.PaidAmount, sale.CreditAmount, sale.Date,
-	)
-	if err != nil {
-		return err
-	}
-
-	for _, item := range sale.Items {
-		_, err = tx.ExecContext(ctx,
-			`INSERT INTO sale_items (id, sale_id, product_id, product_name, quantity, subtotal) VALUES (?,?,?,?,?,?)`,
-			item.ID, sale.ID, item.ProductID, item.ProductName, item.Quantity, item.Subtotal,
+	return withTx(ctx, r.db, func(q dbtx) error {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO sales (id, client_id, client_name, total, paid_amount, deposit_amount, credit_amount, payment_method, user_id, user_name, credit_override_by, order_id, client_balance, date) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+			sale.ID, sale.ClientID, sale.ClientName, sale.Total, sale.PaidAmount, sale.DepositAmount, sale.CreditAmount, sale.PaymentMethod, sale.UserID, sale.UserName, sale.CreditOverrideBy, sale.OrderID, sale.ClientBalance, sale.Date,
 		)
 		if err != nil {
 			return err
 		}
-	}
-	return tx.Commit()
+
+		for _, item := range sale.Items {
+			_, err = q.ExecContext(ctx,
+				`INSERT INTO sale_items (id, sale_id, product_id, product_name, quantity, price_per_kg, subtotal) VALUES (?,?,?,?,?,?,?)`,
+				item.ID, sale.ID, item.ProductID, item.ProductName, item.Quantity, item.PricePerKg, item.Subtotal,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *SQLiteSaleRepo) findItemsBySaleID(ctx context.Context, saleID string) ([]domain.SaleItem, error) {
-	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, sale_id, product_id, product_name, quantity, subtotal FROM sale_items WHERE sale_id = ?`, saleID,
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT id, sale_id, product_id, product_name, quantity, price_per_kg, subtotal FROM sale_items WHERE sale_id = ?`, saleID,
 	)
 	if err != nil {
 		return nil, err
@@ -114,7 +125,7 @@ func (r *SQLiteSaleRepo) findItemsBySaleID(ctx context.Context, saleID string) (
 	var items []domain.SaleItem
 	for rows.Next() {
 		var item domain.SaleItem
-		if err := rows.Scan(&item.ID, &item.SaleID, &item.ProductID, &item.ProductName, &item.Quantity, &item.Subtotal); err != nil {
+		if err := rows.Scan(&item.ID, &item.SaleID, &item.ProductID, &item.ProductName, &item.Quantity, &item.PricePerKg, &item.Subtotal); err != nil {
 			return nil, err
 		}
 		items = append(items, item)
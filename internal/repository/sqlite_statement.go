@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"boucherie-api/internal/domain"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// SQLiteStatementRepo implements port.StatementRepository.
+type SQLiteStatementRepo struct {
+	db *sql.DB
+}
+
+// NewStatementRepo creates a new SQLite-backed statement repository.
+func NewStatementRepo(db *sql.DB) *SQLiteStatementRepo {
+	return &SQLiteStatementRepo{db: db}
+}
+
+// FindEntries returns every movement on a client's account recorded before
+// the given instant, in no particular order: credit granted on sales,
+// payments grouped by receipt, and refunds that reduced a credit. Times are
+// compared with datetime() so that stored time zone offsets are honoured.
+func (r *SQLiteStatementRepo) FindEntries(ctx context.Context, clientID string, before time.Time) ([]domain.StatementEntry, error) {
+	q := executor(ctx, r.db)
+	var entries []domain.StatementEntry
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT created_at, sale_id, amount FROM credits WHERE client_id = ? AND datetime(created_at) < datetime(?)`,
+		clientID, before)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		e := domain.StatementEntry{Kind: domain.StatementSale}
+		if err := rows.Scan(&e.Date, &e.SaleID, &e.Debit); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		e.Reference = e.SaleID
+		entries = append(entries, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Payments recorded together are one movement on the statement
+	rows, err = q.QueryContext(ctx,
+		`SELECT p.receipt_id, p.date, p.method, p.amount
+		 FROM payments p JOIN credits c ON c.id = p.credit_id
+		 WHERE c.client_id = ? AND datetime(p.date) < datetime(?)
+		 ORDER BY p.date`,
+		clientID, before)
+	if err != nil {
+		return nil, err
+	}
+	receipts := make(map[string]int)
+	for rows.Next() {
+		e := domain.StatementEntry{Kind: domain.StatementPayment}
+		if err := rows.Scan(&e.Reference, &e.Date, &e.Method, &e.Credit); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if i, ok := receipts[e.Reference]; ok {
+			entries[i].Credit += e.Credit
+			continue
+		}
+		receipts[e.Reference] = len(entries)
+		entries = append(entries, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = q.QueryContext(ctx,
+		`SELECT id, sale_id, date, method, credit_amount FROM refunds
+		 WHERE client_id = ? AND credit_amount > 0 AND datetime(date) < datetime(?)`,
+		clientID, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		e := domain.StatementEntry{Kind: domain.StatementRefund}
+		if err := rows.Scan(&e.Reference, &e.SaleID, &e.Date, &e.Method, &e.Credit); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
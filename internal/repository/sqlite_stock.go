@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"boucherie-api/internal/domain"
+	"context"
+	"database/sql"
+)
+
+// SQLiteStockRepo implements port.StockRepository.
+type SQLiteStockRepo struct {
+	db *sql.DB
+}
+
+// NewStockRepo creates a new SQLite-backed stock repository.
+func NewStockRepo(db *sql.DB) *SQLiteStockRepo {
+	return &SQLiteStockRepo{db: db}
+}
+
+// stockLevelColumns selects the stock of products. A product is tracked once
+// stock was received or counted for it. Confirmed and ready orders reserve
+// their lines.
+const stockLevelColumns = `SELECT p.id, p.name, p.category, p.in_stock,
+	COALESCE((SELECT SUM(m.quantity) FROM stock_movements m WHERE m.product_id = p.id), 0),
+	COALESCE((SELECT SUM(oi.quantity) FROM order_items oi JOIN orders o ON o.id = oi.order_id
+		WHERE oi.product_id = p.id AND o.status IN ('confirmee', 'prete')), 0),
+	EXISTS(SELECT 1 FROM stock_movements m WHERE m.product_id = p.id AND m.kind IN ('reception', 'ajustement'))
+	FROM products p`
+
+func scanStockLevel(row rowScanner) (*domain.StockLevel, error) {
+	var l domain.StockLevel
+	if err := row.Scan(&l.ProductID, &l.ProductName, &l.Category, &l.InStock, &l.OnHand, &l.Reserved, &l.Tracked); err != nil {
+		return nil, err
+	}
+	l.OnHand = domain.RoundKg(l.OnHand)
+	l.Reserved = domain.RoundKg(l.Reserved)
+	l.Available = domain.RoundKg(l.OnHand - l.Reserved)
+	return &l, nil
+}
+
+// Create appends a movement to the ledger.
+func (r *SQLiteStockRepo) Create(ctx context.Context, m *domain.StockMovement) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO stock_movements (id, product_id, kind, quantity, reference, note, user_id, user_name, date) VALUES (?,?,?,?,?,?,?,?,?)`,
+		m.ID, m.ProductID, m.Kind, m.Quantity, m.Reference, m.Note, m.UserID, m.UserName, m.Date,
+	)
+	return err
+}
+
+// FindByProduct returns a product's movements, newest first.
+func (r *SQLiteStockRepo) FindByProduct(ctx context.Context, productID string, from, to *string) ([]domain.StockMovement, error) {
+	query := `SELECT id, product_id, kind, quantity, reference, note, user_id, user_name, date
+		FROM stock_movements WHERE product_id = ?`
+	args := []interface{}{productID}
+	if from != nil {
+		day, err := localDay(*from)
+		if err != nil {
+			return nil, err
+		}
+		query += ` AND datetime(date) >= datetime(?)`
+		args = append(args, day)
+	}
+	if to != nil {
+		day, err := localDay(*to)
+		if err != nil {
+			return nil, err
+		}
+		query += ` AND datetime(date) < datetime(?)`
+		args = append(args, day.AddDate(0, 0, 1))
+	}
+	query += ` ORDER BY date DESC`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := []domain.StockMovement{}
+	for rows.Next() {
+		var m domain.StockMovement
+		if err := rows.Scan(&m.ID, &m.ProductID, &m.Kind, &m.Quantity, &m.Reference, &m.Note, &m.UserID, &m.UserName, &m.Date); err != nil {
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+// Level returns the stock of one product.
+func (r *SQLiteStockRepo) Level(ctx context.Context, productID string) (*domain.StockLevel, error) {
+	l, err := scanStockLevel(executor(ctx, r.db).QueryRowContext(ctx, stockLevelColumns+` WHERE p.id = ?`, productID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return l, err
+}
+
+// ReservedUntil returns the kg per product reserved by confirmed and ready
+// orders picked up on or before date.
+func (r *SQLiteStockRepo) ReservedUntil(ctx context.Context, date string) (map[string]float64, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT oi.product_id, SUM(oi.quantity) FROM order_items oi JOIN orders o ON o.id = oi.order_id
+		 WHERE o.status IN ('confirmee', 'prete') AND date(o.pickup_date) <= ?
+		 GROUP BY oi.product_id`, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reserved := make(map[string]float64)
+	for rows.Next() {
+		var id string
+		var kg float64
+		if err := rows.Scan(&id, &kg); err != nil {
+			return nil, err
+		}
+		reserved[id] = domain.RoundKg(kg)
+	}
+	return reserved, rows.Err()
+}
+
+// Levels returns the stock of every product, by category and name.
+func (r *SQLiteStockRepo) Levels(ctx context.Context) ([]domain.StockLevel, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, stockLevelColumns+` ORDER BY p.category, p.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	levels := []domain.StockLevel{}
+	for rows.Next() {
+		l, err := scanStockLevel(rows)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, *l)
+	}
+	return levels, rows.Err()
+}
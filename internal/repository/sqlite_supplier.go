@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"boucherie-api/internal/domain"
+	"context"
+	"database/sql"
+)
+
+// SQLiteSupplierRepo implements port.SupplierRepository.
+type SQLiteSupplierRepo struct {
+	db *sql.DB
+}
+
+// NewSupplierRepo creates a new SQLite-backed supplier repository.
+func NewSupplierRepo(db *sql.DB) *SQLiteSupplierRepo {
+	return &SQLiteSupplierRepo{db: db}
+}
+
+// supplierColumns selects a supplier with the total of its receptions not
+// invoiced yet.
+const supplierColumns = `SELECT s.id, s.name, s.phone, s.email, s.address, s.payment_term_days, s.balance,
+	COALESCE((SELECT SUM(r.total) FROM receptions r WHERE r.supplier_id = s.id AND r.invoice_id IS NULL), 0),
+	s.created_at
+	FROM suppliers s`
+
+func scanSupplier(row rowScanner) (*domain.Supplier, error) {
+	var s domain.Supplier
+	err := row.Scan(&s.ID, &s.Name, &s.Phone, &s.Email, &s.Address, &s.PaymentTermDays, &s.Balance, &s.Uninvoiced, &s.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// FindAll returns every supplier by name.
+func (r *SQLiteSupplierRepo) FindAll(ctx context.Context) ([]domain.Supplier, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, supplierColumns+` ORDER BY s.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := []domain.Supplier{}
+	for rows.Next() {
+		s, err := scanSupplier(rows)
+		if err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, *s)
+	}
+	return suppliers, rows.Err()
+}
+
+// FindByID returns a single supplier by ID.
+func (r *SQLiteSupplierRepo) FindByID(ctx context.Context, id string) (*domain.Supplier, error) {
+	s, err := scanSupplier(executor(ctx, r.db).QueryRowContext(ctx, supplierColumns+` WHERE s.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return s, err
+}
+
+// Create inserts a new supplier.
+func (r *SQLiteSupplierRepo) Create(ctx context.Context, s *domain.Supplier) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO suppliers (id, name, phone, email, address, payment_term_days, balance, created_at) VALUES (?,?,?,?,?,?,?,?)`,
+		s.ID, s.Name, s.Phone, s.Email, s.Address, s.PaymentTermDays, s.Balance, s.CreatedAt,
+	)
+	return err
+}
+
+// Update modifies an existing supplier.
+func (r *SQLiteSupplierRepo) Update(ctx context.Context, s *domain.Supplier) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`UPDATE suppliers SET name=?, phone=?, email=?, address=?, payment_term_days=? WHERE id=?`,
+		s.Name, s.Phone, s.Email, s.Address, s.PaymentTermDays, s.ID,
+	)
+	return err
+}
+
+// UpdateBalance atomically adds delta to what the shop owes a supplier.
+func (r *SQLiteSupplierRepo) UpdateBalance(ctx context.Context, supplierID string, delta domain.Money) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`UPDATE suppliers SET balance = balance + ? WHERE id = ?`,
+		delta, supplierID,
+	)
+	return err
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"boucherie-api/internal/domain"
+	"context"
+	"database/sql"
+)
+
+// SQLiteSupplierInvoiceRepo implements port.SupplierInvoiceRepository.
+type SQLiteSupplierInvoiceRepo struct {
+	db *sql.DB
+}
+
+// NewSupplierInvoiceRepo creates a new SQLite-backed supplier invoice repository.
+func NewSupplierInvoiceRepo(db *sql.DB) *SQLiteSupplierInvoiceRepo {
+	return &SQLiteSupplierInvoiceRepo{db: db}
+}
+
+// invoiceColumns selects an invoice without its payments and receptions.
+const invoiceColumns = `SELECT id, supplier_id, supplier_name, number, amount, remaining_amount, status, date, due_date, created_at
+	FROM supplier_invoices`
+
+func scanInvoice(row rowScanner) (*domain.SupplierInvoice, error) {
+	var inv domain.SupplierInvoice
+	var dueDate sql.NullTime
+	err := row.Scan(&inv.ID, &inv.SupplierID, &inv.SupplierName, &inv.Number, &inv.Amount, &inv.RemainingAmount,
+		&inv.Status, &inv.Date, &dueDate, &inv.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if dueDate.Valid {
+		inv.DueDate = &dueDate.Time
+	}
+	return &inv, nil
+}
+
+// FindAll returns all invoices, optionally filtered by status, newest first.
+func (r *SQLiteSupplierInvoiceRepo) FindAll(ctx context.Context, status *domain.CreditStatus) ([]domain.SupplierInvoice, error) {
+	query := invoiceColumns + ` WHERE 1=1`
+	var args []interface{}
+	if status != nil {
+		query += ` AND status = ?`
+		args = append(args, string(*status))
+	}
+	return r.findInvoices(ctx, query+` ORDER BY date DESC`, args...)
+}
+
+// FindBySupplierID returns the invoices of a supplier, newest first.
+func (r *SQLiteSupplierInvoiceRepo) FindBySupplierID(ctx context.Context, supplierID string) ([]domain.SupplierInvoice, error) {
+	return r.findInvoices(ctx, invoiceColumns+` WHERE supplier_id = ? ORDER BY date DESC`, supplierID)
+}
+
+// FindByID returns a single invoice with its payments and receptions.
+func (r *SQLiteSupplierInvoiceRepo) FindByID(ctx context.Context, id string) (*domain.SupplierInvoice, error) {
+	inv, err := scanInvoice(executor(ctx, r.db).QueryRowContext(ctx, invoiceColumns+` WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadDetails(ctx, inv); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// Create inserts a new invoice record.
+func (r *SQLiteSupplierInvoiceRepo) Create(ctx context.Context, inv *domain.SupplierInvoice) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO supplier_invoices (id, supplier_id, supplier_name, number, amount, remaining_amount, status, date, due_date, created_at) VALUES (?,?,?,?,?,?,?,?,?,?)`,
+		inv.ID, inv.SupplierID, inv.SupplierName, inv.Number, inv.Amount, inv.RemainingAmount, inv.Status, inv.Date, inv.DueDate, inv.CreatedAt,
+	)
+	return err
+}
+
+// Update modifies an existing invoice (remaining_amount, status, due_date).
+func (r *SQLiteSupplierInvoiceRepo) Update(ctx context.Context, inv *domain.SupplierInvoice) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`UPDATE supplier_invoices SET remaining_amount=?, status=?, due_date=? WHERE id=?`,
+		inv.RemainingAmount, inv.Status, inv.DueDate, inv.ID,
+	)
+	return err
+}
+
+// AddPayment inserts a payment record for an invoice.
+func (r *SQLiteSupplierInvoiceRepo) AddPayment(ctx context.Context, p *domain.SupplierPayment) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO supplier_payments (id, invoice_id, receipt_id, amount, date, method, user_id, user_name) VALUES (?,?,?,?,?,?,?,?)`,
+		p.ID, p.InvoiceID, p.ReceiptID, p.Amount, p.Date, p.Method, p.UserID, p.UserName,
+	)
+	return err
+}
+
+func (r *SQLiteSupplierInvoiceRepo) findInvoices(ctx context.Context, query string, args ...interface{}) ([]domain.SupplierInvoice, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	list := []domain.SupplierInvoice{}
+	for rows.Next() {
+		inv, err := scanInvoice(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		list = append(list, *inv)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		if err := r.loadDetails(ctx, &list[i]); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// loadDetails reads the payments of an invoice and the receptions it covers.
+func (r *SQLiteSupplierInvoiceRepo) loadDetails(ctx context.Context, inv *domain.SupplierInvoice) error {
+	rows, err := executor(ctx, r.db).QueryContext(ctx,
+		`SELECT id, invoice_id, receipt_id, amount, date, method, user_id, user_name
+		 FROM supplier_payments WHERE invoice_id = ? ORDER BY date DESC`, inv.ID)
+	if err != nil {
+		return err
+	}
+	inv.Payments = []domain.SupplierPayment{}
+	for rows.Next() {
+		var p domain.SupplierPayment
+		if err := rows.Scan(&p.ID, &p.InvoiceID, &p.ReceiptID, &p.Amount, &p.Date, &p.Method, &p.UserID, &p.UserName); err != nil {
+			rows.Close()
+			return err
+		}
+		inv.Payments = append(inv.Payments, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = executor(ctx, r.db).QueryContext(ctx,
+		`SELECT id FROM receptions WHERE invoice_id = ? ORDER BY date`, inv.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	inv.ReceptionIDs = []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		inv.ReceptionIDs = append(inv.ReceptionIDs, id)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// dbtx is the subset of *sql.DB and *sql.Tx used by the repositories.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// txKey is the context key under which the active transaction is stored.
+type txKey struct{}
+
+// SQLiteTxManager implements port.Transactor using SQLite transactions.
+type SQLiteTxManager struct {
+	db *sql.DB
+}
+
+// NewTxManager creates a new SQLite-backed transaction manager.
+func NewTxManager(db *sql.DB) *SQLiteTxManager {
+	return &SQLiteTxManager{db: db}
+}
+
+// WithinTx runs fn inside a transaction. Repositories called with the context
+// passed to fn join that transaction. If ctx already carries a transaction,
+// fn joins it and the outermost caller decides whether to commit.
+func (m *SQLiteTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// executor returns the transaction carried by ctx, or db when there is none.
+func executor(ctx context.Context, db *sql.DB) dbtx {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// withTx runs fn on the transaction carried by ctx, or on a new transaction
+// committed when fn succeeds. Used by multi-statement writes such as
+// inserting a sale with its items.
+func withTx(ctx context.Context, db *sql.DB, fn func(q dbtx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(tx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"boucherie-api/internal/domain"
+	"context"
+	"database/sql"
+)
+
+// SQLiteUserRepo implements port.UserRepository.
+type SQLiteUserRepo struct {
+	db *sql.DB
+}
+
+// NewUserRepo creates a new SQLite-backed user repository.
+func NewUserRepo(db *sql.DB) *SQLiteUserRepo {
+	return &SQLiteUserRepo{db: db}
+}
+
+const userColumns = `SELECT id, username, name, role, password_hash, active, created_at FROM users`
+
+// FindAll returns every user ordered by name.
+func (r *SQLiteUserRepo) FindAll(ctx context.Context) ([]domain.User, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, userColumns+` ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var u domain.User
+		var active int
+		if err := rows.Scan(&u.ID, &u.Username, &u.Name, &u.Role, &u.PasswordHash, &active, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		u.Active = active == 1
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// FindByID returns a single user by ID.
+func (r *SQLiteUserRepo) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.findOne(ctx, userColumns+` WHERE id = ?`, id)
+}
+
+// FindByUsername returns a single user by login name.
+func (r *SQLiteUserRepo) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return r.findOne(ctx, userColumns+` WHERE username = ?`, username)
+}
+
+// Count returns the number of user accounts.
+func (r *SQLiteUserRepo) Count(ctx context.Context) (int, error) {
+	var n int
+	err := executor(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM users`).Scan(&n)
+	return n, err
+}
+
+// CountActiveOwners returns the number of active owner accounts.
+func (r *SQLiteUserRepo) CountActiveOwners(ctx context.Context) (int, error) {
+	var n int
+	err := executor(ctx, r.db).QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM users WHERE role = ? AND active = 1`, domain.RoleOwner).Scan(&n)
+	return n, err
+}
+
+// Create inserts a new user.
+func (r *SQLiteUserRepo) Create(ctx context.Context, user *domain.User) error {
+	active := 0
+	if user.Active {
+		active = 1
+	}
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO users (id, username, name, role, password_hash, active, created_at) VALUES (?,?,?,?,?,?,?)`,
+		user.ID, user.Username, user.Name, user.Role, user.PasswordHash, active, user.CreatedAt,
+	)
+	return err
+}
+
+// Update modifies an existing user.
+func (r *SQLiteUserRepo) Update(ctx context.Context, user *domain.User) error {
+	active := 0
+	if user.Active {
+		active = 1
+	}
+	_, err := executor(ctx, r.db).ExecContext(ctx,
+		`UPDATE users SET name=?, role=?, password_hash=?, active=? WHERE id=?`,
+		user.Name, user.Role, user.PasswordHash, active, user.ID,
+	)
+	return err
+}
+
+func (r *SQLiteUserRepo) findOne(ctx context.Context, query string, arg string) (*domain.User, error) {
+	var u domain.User
+	var active int
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, arg).
+		Scan(&u.ID, &u.Username, &u.Name, &u.Role, &u.PasswordHash, &active, &u.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.Active = active == 1
+	return &u, nil
+}
@@ -0,0 +1,46 @@
+// Package scheduler runs periodic background jobs inside the server.
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Job is a unit of background work. Errors are logged, never fatal.
+type Job func(ctx context.Context) error
+
+// Every runs job in the background once right away and then at each interval
+// until ctx is cancelled. Runs never overlap.
+func Every(ctx context.Context, name string, interval time.Duration, job Job) {
+	go func() {
+		run(ctx, name, job)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				run(ctx, name, job)
+			}
+		}
+	}()
+}
+
+func run(ctx context.Context, name string, job Job) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Error().Str("job", name).Interface("panic", rec).Msg("scheduled job panicked")
+		}
+	}()
+
+	start := time.Now()
+	if err := job(ctx); err != nil {
+		log.Error().Err(err).Str("job", name).Msg("scheduled job failed")
+		return
+	}
+	log.Debug().Str("job", name).Dur("duration", time.Since(start)).Msg("scheduled job done")
+}
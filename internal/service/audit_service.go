@@ -0,0 +1,66 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/port"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditService records and queries the audit trail.
+type AuditService struct {
+	repo port.AuditRepository
+}
+
+// NewAuditService creates a new audit service.
+func NewAuditService(repo port.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// List returns audit entries matching the filter, most recent first.
+func (s *AuditService) List(ctx context.Context, f domain.AuditFilter) ([]domain.AuditEntry, error) {
+	for _, d := range []string{f.From, f.To} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return nil, errors.New("invalid date format, expected YYYY-MM-DD")
+		}
+	}
+	return s.repo.Find(ctx, f)
+}
+
+// record appends an entry for a change to an entity, attributed to the user
+// and HTTP request carried by ctx. before is nil on creation and after is nil
+// on deletion. Callers run it in the same transaction as the change itself.
+func (s *AuditService) record(ctx context.Context, entity domain.AuditEntity, id string, action domain.AuditAction, before, after interface{}) error {
+	entry := &domain.AuditEntry{
+		ID:        uuid.New().String(),
+		Entity:    entity,
+		EntityID:  id,
+		Action:    action,
+		RequestID: domain.RequestIDFromContext(ctx),
+		Date:      time.Now(),
+	}
+	entry.UserID, entry.UserName = actor(ctx)
+
+	var err error
+	if entry.Before, err = marshalAudit(before); err != nil {
+		return err
+	}
+	if entry.After, err = marshalAudit(after); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, entry)
+}
+
+func marshalAudit(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/repository"
+	"context"
+	"testing"
+)
+
+func TestAuditRolledBackWithFailedOperation(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	// The sale is audited before the balance update fails
+	svc := newTestSales(t, db, failingClients{repository.NewClientRepo(db)})
+	if _, err := svc.Create(ctx, domain.CreateSaleRequest{
+		ClientID:   "c1",
+		Items:      []domain.CreateSaleItemRequest{{ProductID: "p1", Quantity: 2}},
+		PaidAmount: 1000,
+	}); err == nil {
+		t.Fatal("sale succeeded despite the failing balance update")
+	}
+	if n := count(t, db, "audit_log"); n != 0 {
+		t.Fatalf("%d audit rows left by the rolled back sale, want 0", n)
+	}
+
+	// The same sale with a working repository is audited: sale, credit and
+	// client balance
+	svc.clientRepo = repository.NewClientRepo(db)
+	sale := sell(t, svc, 1000)
+	entries, err := svc.audit.List(ctx, domain.AuditFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[domain.AuditEntity]int{}
+	for _, e := range entries {
+		got[e.Entity]++
+		if e.Entity == domain.AuditSale && e.EntityID != sale.ID {
+			t.Errorf("sale audited as %s, want %s", e.EntityID, sale.ID)
+		}
+	}
+	if got[domain.AuditSale] != 1 || got[domain.AuditCredit] != 1 || got[domain.AuditClient] != 1 {
+		t.Fatalf("audited entities %v, want one sale, credit and client", got)
+	}
+}
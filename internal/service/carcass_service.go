@@ -0,0 +1,302 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/port"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CarcassService handles carcass receptions and their breakdown (découpe)
+// into catalog products.
+type CarcassService struct {
+	repo        port.CarcassRepository
+	productRepo port.ProductRepository
+	stock       *StockService
+	tx          port.Transactor
+	audit       *AuditService
+}
+
+// NewCarcassService creates a new carcass service.
+func NewCarcassService(repo port.CarcassRepository, productRepo port.ProductRepository, stock *StockService, tx port.Transactor, audit *AuditService) *CarcassService {
+	return &CarcassService{repo: repo, productRepo: productRepo, stock: stock, tx: tx, audit: audit}
+}
+
+// List returns carcasses matching the filter, most recently received first.
+func (s *CarcassService) List(ctx context.Context, f domain.CarcassFilter) ([]domain.Carcass, error) {
+	for _, d := range []string{f.From, f.To} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return nil, errors.New("invalid date format, expected YYYY-MM-DD")
+		}
+	}
+	list, err := s.repo.FindAll(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].Compute()
+	}
+	return list, nil
+}
+
+// Get returns a single carcass with its cuts.
+func (s *CarcassService) Get(ctx context.Context, id string) (*domain.Carcass, error) {
+	c, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, errors.New("carcass not found")
+	}
+	c.Compute()
+	return c, nil
+}
+
+// Create registers a carcass received whole, waiting to be cut up.
+func (s *CarcassService) Create(ctx context.Context, req domain.CreateCarcassRequest) (*domain.Carcass, error) {
+	if !validCategory(req.Category) {
+		return nil, fmt.Errorf("invalid category %q", req.Category)
+	}
+	receivedAt := time.Now()
+	if req.ReceivedAt != "" {
+		day, err := time.ParseInLocation("2006-01-02", req.ReceivedAt, time.Local)
+		if err != nil {
+			return nil, errors.New("invalid receivedAt, expected YYYY-MM-DD")
+		}
+		if day.After(receivedAt) {
+			return nil, errors.New("receivedAt cannot be in the future")
+		}
+		receivedAt = day
+	}
+
+	userID, userName := actor(ctx)
+	c := &domain.Carcass{
+		ID:          uuid.New().String(),
+		Category:    req.Category,
+		Description: req.Description,
+		Supplier:    req.Supplier,
+		SupplierID:  req.SupplierID,
+		Weight:      req.Weight,
+		Cost:        req.Cost,
+		Status:      domain.CarcassRecue,
+		Cuts:        []domain.CarcassCut{},
+		UserID:      userID,
+		UserName:    userName,
+		ReceivedAt:  receivedAt,
+	}
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, c); err != nil {
+			return err
+		}
+		if err := s.audit.record(ctx, domain.AuditCarcass, c.ID, domain.AuditCreate, nil, c); err != nil {
+			return err
+		}
+		if req.Lot == nil {
+			return nil
+		}
+		lot := newLot(req.Lot, c.Weight, receivedAt)
+		lot.CarcassID, lot.Remaining = c.ID, 0
+		lot.SupplierName = c.Supplier
+		return s.stock.receiveLot(ctx, lot)
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.Compute()
+	return c, nil
+}
+
+// Breakdown records the cuts a carcass was turned into and the waste. The
+// cuts are received into stock, each in a lot traced to the carcass, and
+// share the carcass cost in proportion to their selling value. A carcass is
+// broken down once.
+func (s *CarcassService) Breakdown(ctx context.Context, id string, req domain.BreakdownRequest) (*domain.Carcass, error) {
+	var c *domain.Carcass
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if c, err = s.repo.FindByID(ctx, id); err != nil {
+			return err
+		}
+		if c == nil {
+			return errors.New("carcass not found")
+		}
+		if c.Status != domain.CarcassRecue {
+			return errors.New("carcass already broken down")
+		}
+		before := *c
+
+		cuts, kg := make([]domain.CarcassCut, 0, len(req.Cuts)), req.WasteKg
+		for _, r := range req.Cuts {
+			product, err := s.productRepo.FindByID(ctx, r.ProductID)
+			if err != nil {
+				return err
+			}
+			if product == nil {
+				return fmt.Errorf("product %s not found", r.ProductID)
+			}
+			// Cuts of another meat would distort the category's yields
+			if product.Category != c.Category {
+				return fmt.Errorf("product %s is %s, not %s like the carcass", product.Name, product.Category, c.Category)
+			}
+			cuts = append(cuts, domain.CarcassCut{
+				ID:          uuid.New().String(),
+				CarcassID:   c.ID,
+				ProductID:   product.ID,
+				ProductName: product.Name,
+				Quantity:    r.Quantity,
+				PricePerKg:  product.PricePerKg,
+			})
+			kg += r.Quantity
+		}
+		if kg > c.Weight+quantityEpsilon {
+			return fmt.Errorf("cuts and waste weigh %.3f kg, more than the carcass (%.3f kg)", kg, c.Weight)
+		}
+		allocateCost(c.Cost, cuts)
+
+		now := time.Now()
+		carcassLot, err := s.stock.carcassLot(ctx, c.ID)
+		if err != nil {
+			return err
+		}
+		var lots []*domain.Lot
+		for i := range cuts {
+			lot := newLot(nil, cuts[i].Quantity, now)
+			if carcassLot != nil {
+				lot.Trace(carcassLot)
+			} else {
+				lot.SupplierName = c.Supplier
+			}
+			lot.ProductID, lot.CarcassID = cuts[i].ProductID, c.ID
+			cuts[i].LotID = lot.ID
+			lots = append(lots, lot)
+		}
+		c.Cuts = cuts
+		c.WasteKg = req.WasteKg
+		c.Status = domain.CarcassDecoupee
+		c.BrokenDownAt = &now
+		if err := s.repo.SaveBreakdown(ctx, c); err != nil {
+			return err
+		}
+		for i, cut := range cuts {
+			m := newStockMovement(ctx, cut.ProductID, domain.StockReception, cut.Quantity, c.ID)
+			m.Note = "découpe"
+			if err := s.stock.record(ctx, m); err != nil {
+				return err
+			}
+			if err := s.stock.receiveLot(ctx, lots[i]); err != nil {
+				return err
+			}
+		}
+		return s.audit.record(ctx, domain.AuditCarcass, c.ID, domain.AuditUpdate, before, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.Compute()
+	return c, nil
+}
+
+// Delete removes a carcass registered by mistake. Once broken down, its cuts
+// are in stock and it is kept.
+func (s *CarcassService) Delete(ctx context.Context, id string) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		c, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return errors.New("carcass not found")
+		}
+		if c.Status != domain.CarcassRecue {
+			return errors.New("cannot delete a carcass already broken down")
+		}
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditCarcass, id, domain.AuditDelete, c, nil)
+	})
+}
+
+// Yields sums up the carcasses of a category broken down over a period:
+// average yield and real cost per kg of each cut.
+func (s *CarcassService) Yields(ctx context.Context, f domain.CarcassFilter) (*domain.YieldReport, error) {
+	if !validCategory(f.Category) {
+		return nil, fmt.Errorf("invalid category %q", f.Category)
+	}
+	list, err := s.List(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.YieldReport{Category: f.Category, From: f.From, To: f.To, Cuts: []domain.CutYield{}}
+	byProduct := make(map[string]int)
+	for _, c := range list {
+		if c.Status != domain.CarcassDecoupee {
+			continue
+		}
+		report.Carcasses++
+		report.Weight += c.Weight
+		report.Cost += c.Cost
+		report.CutKg += c.CutKg
+		report.WasteKg += c.WasteKg
+		for _, cut := range c.Cuts {
+			i, ok := byProduct[cut.ProductID]
+			if !ok {
+				i = len(report.Cuts)
+				byProduct[cut.ProductID] = i
+				report.Cuts = append(report.Cuts, domain.CutYield{ProductID: cut.ProductID, ProductName: cut.ProductName})
+			}
+			report.Cuts[i].Quantity += cut.Quantity
+			report.Cuts[i].Cost += cut.Cost
+		}
+	}
+
+	report.Compute()
+	return report, nil
+}
+
+// allocateCost shares cost between the cuts in proportion to their selling
+// value. The last cut takes the rounding remainder so the shares add up.
+func allocateCost(cost domain.Money, cuts []domain.CarcassCut) {
+	var total domain.Money
+	for _, cut := range cuts {
+		total += cut.PricePerKg.MulKg(cut.Quantity)
+	}
+	remaining := cost
+	for i := range cuts {
+		if i == len(cuts)-1 {
+			cuts[i].Cost = remaining
+			break
+		}
+		if total > 0 {
+			cuts[i].Cost = cost.Prorate(int64(cuts[i].PricePerKg.MulKg(cuts[i].Quantity)), int64(total))
+		} else {
+			cuts[i].Cost = cost.Prorate(domain.Grams(cuts[i].Quantity), domain.Grams(totalKg(cuts)))
+		}
+		remaining -= cuts[i].Cost
+	}
+}
+
+func totalKg(cuts []domain.CarcassCut) float64 {
+	var kg float64
+	for _, cut := range cuts {
+		kg += cut.Quantity
+	}
+	return kg
+}
+
+func validCategory(category domain.MeatCategory) bool {
+	for _, c := range domain.ValidCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
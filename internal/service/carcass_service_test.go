@@ -0,0 +1,164 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/repository"
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+// newTestCarcasses returns a carcass service over db with beef cuts at
+// 34,90, 12,90 and 8,90 €/kg and a lamb product.
+func newTestCarcasses(t *testing.T, db *sql.DB) *CarcassService {
+	t.Helper()
+	productRepo := repository.NewProductRepo(db)
+	for _, p := range []domain.Product{
+		{ID: "filet", Name: "Filet", Category: domain.CategoryBoeuf, PricePerKg: 3490},
+		{ID: "bavette", Name: "Bavette", Category: domain.CategoryBoeuf, PricePerKg: 1290},
+		{ID: "pot-au-feu", Name: "Pot-au-feu", Category: domain.CategoryBoeuf, PricePerKg: 890},
+		{ID: "gigot", Name: "Gigot", Category: domain.CategoryAgneau, PricePerKg: 2490},
+	} {
+		p.InStock = true
+		if err := productRepo.Create(context.Background(), &p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tx := repository.NewTxManager(db)
+	audit := NewAuditService(repository.NewAuditRepo(db))
+	stock := NewStockService(repository.NewStockRepo(db), repository.NewLotRepo(db), productRepo, tx, audit)
+	return NewCarcassService(repository.NewCarcassRepo(db), productRepo, stock, tx, audit)
+}
+
+func TestBreakdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		cuts    []domain.CarcassCutRequest
+		wasteKg float64
+		wantErr string
+	}{
+		{
+			name: "costs add up to the carcass cost",
+			cuts: []domain.CarcassCutRequest{
+				{ProductID: "filet", Quantity: 12.345},
+				{ProductID: "bavette", Quantity: 40.1},
+				{ProductID: "pot-au-feu", Quantity: 30.007},
+			},
+			wasteKg: 15,
+		},
+		{
+			name:    "whole weight in cuts and waste",
+			cuts:    []domain.CarcassCutRequest{{ProductID: "filet", Quantity: 60}, {ProductID: "bavette", Quantity: 30}},
+			wasteKg: 10,
+		},
+		{
+			name:    "cuts and waste heavier than the carcass",
+			cuts:    []domain.CarcassCutRequest{{ProductID: "filet", Quantity: 60}, {ProductID: "bavette", Quantity: 30}},
+			wasteKg: 10.5,
+			wantErr: "more than the carcass",
+		},
+		{
+			name:    "cut of another category",
+			cuts:    []domain.CarcassCutRequest{{ProductID: "filet", Quantity: 20}, {ProductID: "gigot", Quantity: 5}},
+			wantErr: "not boeuf like the carcass",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			svc := newTestCarcasses(t, db)
+			ctx := context.Background()
+			c, err := svc.Create(ctx, domain.CreateCarcassRequest{Category: domain.CategoryBoeuf, Weight: 100, Cost: 33333})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = svc.Breakdown(ctx, c.ID, domain.BreakdownRequest{Cuts: tt.cuts, WasteKg: tt.wasteKg})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				got, err := svc.Get(ctx, c.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Status != domain.CarcassRecue || len(got.Cuts) != 0 || count(t, db, "stock_movements") != 0 {
+					t.Fatalf("rejected breakdown left status %s, %d cuts, %d stock movements",
+						got.Status, len(got.Cuts), count(t, db, "stock_movements"))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Read back what was stored, not what Breakdown returned
+			got, err := svc.Get(ctx, c.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sum domain.Money
+			for _, cut := range got.Cuts {
+				sum += cut.Cost
+			}
+			if sum != got.Cost {
+				t.Fatalf("cut costs add up to %s, want the carcass cost %s", sum, got.Cost)
+			}
+			if got.Status != domain.CarcassDecoupee || len(got.Cuts) != len(tt.cuts) {
+				t.Fatalf("status %s with %d cuts, want %s with %d", got.Status, len(got.Cuts), domain.CarcassDecoupee, len(tt.cuts))
+			}
+		})
+	}
+}
+
+func TestAllocateCost(t *testing.T) {
+	tests := []struct {
+		name string
+		cost domain.Money
+		cuts []domain.CarcassCut
+		want []domain.Money
+	}{
+		{
+			name: "by selling value",
+			cost: 10000,
+			cuts: []domain.CarcassCut{{Quantity: 1, PricePerKg: 3000}, {Quantity: 2, PricePerKg: 1000}},
+			want: []domain.Money{6000, 4000},
+		},
+		{
+			name: "rounding remainder on the last cut",
+			cost: 10000,
+			cuts: []domain.CarcassCut{{Quantity: 1, PricePerKg: 1000}, {Quantity: 1, PricePerKg: 1000}, {Quantity: 1, PricePerKg: 1000}},
+			want: []domain.Money{3333, 3333, 3334},
+		},
+		{
+			name: "no selling value falls back to weight",
+			cost: 10001,
+			cuts: []domain.CarcassCut{{Quantity: 3}, {Quantity: 1}},
+			want: []domain.Money{7501, 2500},
+		},
+		{
+			name: "free carcass",
+			cost: 0,
+			cuts: []domain.CarcassCut{{Quantity: 3, PricePerKg: 1000}, {Quantity: 1, PricePerKg: 500}},
+			want: []domain.Money{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocateCost(tt.cost, tt.cuts)
+			var sum domain.Money
+			for i, cut := range tt.cuts {
+				sum += cut.Cost
+				if cut.Cost != tt.want[i] {
+					t.Errorf("cut %d costs %s, want %s", i, cut.Cost, tt.want[i])
+				}
+			}
+			if sum != tt.cost {
+				t.Fatalf("costs add up to %s, want %s", sum, tt.cost)
+			}
+		})
+	}
+}
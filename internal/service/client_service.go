@@ -5,6 +5,7 @@ import (
 	"boucherie-api/internal/port"
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,12 +13,14 @@ import (
 
 // ClientService handles client business logic.
 type ClientService struct {
-	repo port.ClientRepository
+	repo  port.ClientRepository
+	tx    port.Transactor
+	audit *AuditService
 }
 
 // NewClientService creates a new client service.
-func NewClientService(repo port.ClientRepository) *ClientService {
-	return &ClientService{repo: repo}
+func NewClientService(repo port.ClientRepository, tx port.Transactor, audit *AuditService) *ClientService {
+	return &ClientService{repo: repo, tx: tx, audit: audit}
 }
 
 // List returns all clients.
@@ -37,17 +40,29 @@ func (s *ClientService) Get(ctx context.Context, id string) (*domain.Client, err
 	return client, nil
 }
 
-// Create validates and creates a new client.
+// Create validates and creates a new client. Only the owner may give the
+// client their own payment term or credit limit.
 func (s *ClientService) Create(ctx context.Context, req domain.CreateClientRequest) (*domain.Client, error) {
+	if (req.PaymentTermDays != nil || req.CreditLimit != nil) && !hasRole(ctx, domain.RoleOwner) {
+		return nil, fmt.Errorf("%w: only the owner can set payment terms and credit limits", ErrForbidden)
+	}
 	client := &domain.Client{
-		ID:          uuid.New().String(),
-		Name:        req.Name,
-		Phone:       req.Phone,
-		Email:       req.Email,
-		TotalCredit: 0,
-		CreatedAt:   time.Now(),
+		ID:              uuid.New().String(),
+		Name:            req.Name,
+		Phone:           req.Phone,
+		Email:           req.Email,
+		TotalCredit:     0,
+		PaymentTermDays: req.PaymentTermDays,
+		CreditLimit:     req.CreditLimit,
+		CreatedAt:       time.Now(),
 	}
-	if err := s.repo.Create(ctx, client); err != nil {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, client); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditClient, client.ID, domain.AuditCreate, nil, client)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return client, nil
@@ -55,26 +70,88 @@ func (s *ClientService) Create(ctx context.Context, req domain.CreateClientReque
 
 // Update modifies an existing client's fields.
 func (s *ClientService) Update(ctx context.Context, id string, req domain.UpdateClientRequest) (*domain.Client, error) {
-	client, err := s.repo.FindByID(ctx, id)
+	var client *domain.Client
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		client, err = s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if client == nil {
+			return errors.New("client not found")
+		}
+		before := *client
+
+		if req.Name != nil {
+			client.Name = *req.Name
+		}
+		if req.Phone != nil {
+			client.Phone = *req.Phone
+		}
+		if req.Email != nil {
+			client.Email = *req.Email
+		}
+		if req.PaymentTermDays != nil {
+			var days *int
+			if *req.PaymentTermDays >= 0 {
+				d := *req.PaymentTermDays
+				days = &d
+			}
+			if !sameValue(days, client.PaymentTermDays) {
+				if !hasRole(ctx, domain.RoleOwner) {
+					return fmt.Errorf("%w: only the owner can change payment terms", ErrForbidden)
+				}
+				client.PaymentTermDays = days
+			}
+		}
+		if req.CreditLimit != nil {
+			var limit *domain.Money
+			if *req.CreditLimit >= 0 {
+				l := *req.CreditLimit
+				limit = &l
+			}
+			if !sameValue(limit, client.CreditLimit) {
+				if !hasRole(ctx, domain.RoleOwner) {
+					return fmt.Errorf("%w: only the owner can change credit limits", ErrForbidden)
+				}
+				client.CreditLimit = limit
+			}
+		}
+
+		if err := s.repo.Update(ctx, client); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditClient, client.ID, domain.AuditUpdate, before, client)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if client == nil {
-		return nil, errors.New("client not found")
-	}
+	return client, nil
+}
 
-	if req.Name != nil {
-		client.Name = *req.Name
+// sameValue reports whether two optional overrides are both unset or equal.
+func sameValue[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
 	}
-	if req.Phone != nil {
-		client.Phone = *req.Phone
+	return *a == *b
+}
+
+// adjustBalance adds delta to a client's balance and records the change in
+// the audit log. Callers run it inside the transaction of the sale, payment
+// or refund that moves the balance.
+func adjustBalance(ctx context.Context, clients port.ClientRepository, audit *AuditService, clientID string, delta domain.Money) error {
+	client, err := clients.FindByID(ctx, clientID)
+	if err != nil {
+		return err
 	}
-	if req.Email != nil {
-		client.Email = *req.Email
+	if client == nil {
+		return errors.New("client not found")
 	}
-
-	if err := s.repo.Update(ctx, client); err != nil {
-		return nil, err
+	before := *client
+	if err := clients.UpdateTotalCredit(ctx, clientID, delta); err != nil {
+		return err
 	}
-	return client, nil
+	client.TotalCredit += delta
+	return audit.record(ctx, domain.AuditClient, clientID, domain.AuditUpdate, before, client)
 }
@@ -5,6 +5,8 @@ import (
 	"boucherie-api/internal/port"
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,11 +16,32 @@ import (
 type CreditService struct {
 	creditRepo port.CreditRepository
 	clientRepo port.ClientRepository
+	tx         port.Transactor
+	audit      *AuditService
+	terms      domain.PaymentTerms
+
+	// ownerThreshold is the payment amount above which only the owner may
+	// record a payment. Zero disables the check.
+	ownerThreshold domain.Money
 }
 
 // NewCreditService creates a new credit service.
-func NewCreditService(creditRepo port.CreditRepository, clientRepo port.ClientRepository) *CreditService {
-	return &CreditService{creditRepo: creditRepo, clientRepo: clientRepo}
+func NewCreditService(
+	creditRepo port.CreditRepository,
+	clientRepo port.ClientRepository,
+	tx port.Transactor,
+	audit *AuditService,
+	terms domain.PaymentTerms,
+	ownerThreshold domain.Money,
+) *CreditService {
+	return &CreditService{
+		creditRepo:     creditRepo,
+		clientRepo:     clientRepo,
+		tx:             tx,
+		audit:          audit,
+		terms:          terms,
+		ownerThreshold: ownerThreshold,
+	}
 }
 
 // List returns all credits, optionally filtered by status.
@@ -32,48 +55,226 @@ func (s *CreditService) ListByClient(ctx context.Context, clientID string) ([]do
 }
 
 // AddPayment registers a payment on a credit, updates remaining amount, and adjusts client balance.
+// The payment, the credit update and the balance change are committed together.
 func (s *CreditService) AddPayment(ctx context.Context, creditID string, req domain.CreatePaymentRequest) (*domain.Credit, error) {
-	credit, err := s.creditRepo.FindByID(ctx, creditID)
+	if err := checkOwnerThreshold(ctx, s.ownerThreshold, req.Amount); err != nil {
+		return nil, err
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		credit, err := s.creditRepo.FindByID(ctx, creditID)
+		if err != nil {
+			return err
+		}
+		if credit == nil {
+			return errors.New("credit not found")
+		}
+		if credit.Status == domain.CreditStatusPaye {
+			return errors.New("credit already fully paid")
+		}
+		if req.Amount > credit.RemainingAmount {
+			return errors.New("payment exceeds remaining amount")
+		}
+
+		payment := s.newPayment(ctx, uuid.New().String(), req.Amount, req.Method, time.Now())
+		if err := s.applyPayment(ctx, credit, payment); err != nil {
+			return err
+		}
+
+		// Update client balance (decrease)
+		return adjustBalance(ctx, s.clientRepo, s.audit, credit.ClientID, -req.Amount)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if credit == nil {
-		return nil, errors.New("credit not found")
+
+	// Reload with payments
+	return s.creditRepo.FindByID(ctx, creditID)
+}
+
+// PayOnAccount spreads a client's payment over their open credits, oldest
+// first or overdue first, and records one payment per credit it touches
+// under a single receipt. The client balance is updated once.
+func (s *CreditService) PayOnAccount(ctx context.Context, clientID string, req domain.CreateClientPaymentRequest) (*domain.ClientPaymentReceipt, error) {
+	if err := checkOwnerThreshold(ctx, s.ownerThreshold, req.Amount); err != nil {
+		return nil, err
 	}
-	if credit.Status == domain.CreditStatusPaye {
-		return nil, errors.New("credit already fully paid")
+
+	var receipt *domain.ClientPaymentReceipt
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		client, err := s.clientRepo.FindByID(ctx, clientID)
+		if err != nil {
+			return err
+		}
+		if client == nil {
+			return errors.New("client not found")
+		}
+
+		credits, err := s.creditRepo.FindByClientID(ctx, clientID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		var open []domain.Credit
+		var owed domain.Money
+		for _, c := range credits {
+			if c.Status != domain.CreditStatusPaye && c.RemainingAmount > 0 {
+				open = append(open, c)
+				owed += c.RemainingAmount
+			}
+		}
+		if len(open) == 0 {
+			return errors.New("client has no open credit")
+		}
+		if req.Amount > owed {
+			return errors.New("payment exceeds client balance")
+		}
+		sortForAllocation(open, req.Strategy, now)
+
+		receipt = &domain.ClientPaymentReceipt{
+			ReceiptID:  uuid.New().String(),
+			ClientID:   client.ID,
+			ClientName: client.Name,
+			Amount:     req.Amount,
+			Method:     req.Method,
+			Date:       now,
+		}
+		left := req.Amount
+		for i := range open {
+			if left == 0 {
+				break
+			}
+			payment := s.newPayment(ctx, receipt.ReceiptID, left.Min(open[i].RemainingAmount), req.Method, now)
+			if err := s.applyPayment(ctx, &open[i], payment); err != nil {
+				return err
+			}
+			receipt.Payments = append(receipt.Payments, *payment)
+			left -= payment.Amount
+		}
+
+		if err := adjustBalance(ctx, s.clientRepo, s.audit, client.ID, -req.Amount); err != nil {
+			return err
+		}
+		receipt.TotalCredit = client.TotalCredit - req.Amount
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if req.Amount > credit.RemainingAmount {
-		return nil, errors.New("payment exceeds remaining amount")
+	return receipt, nil
+}
+
+// checkOwnerThreshold refuses payments above threshold unless the owner
+// records them. It applies to payments received from clients and made to
+// suppliers alike; a zero threshold disables it.
+func checkOwnerThreshold(ctx context.Context, threshold, amount domain.Money) error {
+	if threshold > 0 && amount > threshold && !hasRole(ctx, domain.RoleOwner) {
+		return fmt.Errorf("%w: payments above %s must be recorded by the owner", ErrForbidden, threshold)
 	}
+	return nil
+}
 
-	// Create payment
-	payment := &domain.Payment{
-		ID:       uuid.New().String(),
-		CreditID: creditID,
-		Amount:   req.Amount,
-		Date:     time.Now(),
-		Method:   req.Method,
+// newPayment builds a payment attributed to the user performing the request.
+func (s *CreditService) newPayment(ctx context.Context, receiptID string, amount domain.Money, method domain.PaymentMethod, date time.Time) *domain.Payment {
+	userID, userName := actor(ctx)
+	return &domain.Payment{
+		ID:        uuid.New().String(),
+		ReceiptID: receiptID,
+		Amount:    amount,
+		Date:      date,
+		Method:    method,
+		UserID:    userID,
+		UserName:  userName,
 	}
+}
+
+// applyPayment records payment against credit and updates the credit's
+// remaining amount and status. The client balance is left to the caller.
+func (s *CreditService) applyPayment(ctx context.Context, credit *domain.Credit, payment *domain.Payment) error {
+	payment.CreditID = credit.ID
 	if err := s.creditRepo.AddPayment(ctx, payment); err != nil {
-		return nil, err
+		return err
+	}
+	if err := s.audit.record(ctx, domain.AuditPayment, payment.ID, domain.AuditCreate, nil, payment); err != nil {
+		return err
 	}
 
-	// Update credit
-	credit.RemainingAmount -= req.Amount
-	if credit.RemainingAmount <= 0 {
+	before := *credit
+	credit.RemainingAmount -= payment.Amount
+	if credit.RemainingAmount < 0 {
 		credit.RemainingAmount = 0
-		credit.Status = domain.CreditStatusPaye
 	}
+	credit.Status = credit.StatusAt(payment.Date)
 	if err := s.creditRepo.Update(ctx, credit); err != nil {
-		return nil, err
+		return err
 	}
+	return s.audit.record(ctx, domain.AuditCredit, credit.ID, domain.AuditUpdate, before, credit)
+}
 
-	// Update client balance (decrease)
-	if err := s.clientRepo.UpdateTotalCredit(ctx, credit.ClientID, -req.Amount); err != nil {
-		return nil, err
-	}
+// sortForAllocation orders open credits in the order a payment on account
+// settles them. Oldest first is the default; overdue first settles late
+// credits, earliest due first, before the others.
+func sortForAllocation(credits []domain.Credit, strategy domain.AllocationStrategy, now time.Time) {
+	sort.SliceStable(credits, func(i, j int) bool {
+		a, b := &credits[i], &credits[j]
+		if strategy == domain.AllocateOverdueFirst {
+			if ao, bo := a.IsOverdue(now), b.IsOverdue(now); ao != bo {
+				return ao
+			}
+			if a.DueDate != nil && b.DueDate != nil && !a.DueDate.Equal(*b.DueDate) {
+				return a.DueDate.Before(*b.DueDate)
+			}
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+}
 
-	// Reload with payments
-	return s.creditRepo.FindByID(ctx, creditID)
+// RefreshOverdue flags open credits past their due date as en_retard and
+// returns the others to en_cours, e.g. after a due date was pushed back.
+// Open credits granted before due dates were tracked get one from the
+// payment terms. It returns the number of credits changed.
+func (s *CreditService) RefreshOverdue(ctx context.Context, now time.Time) (int, error) {
+	changed := 0
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		clients := make(map[string]*domain.Client)
+		for _, status := range []domain.CreditStatus{domain.CreditStatusEnCours, domain.CreditStatusEnRetard} {
+			credits, err := s.creditRepo.FindAll(ctx, &status)
+			if err != nil {
+				return err
+			}
+			for i := range credits {
+				credit := &credits[i]
+				before := *credit
+
+				if credit.DueDate == nil {
+					client, ok := clients[credit.ClientID]
+					if !ok {
+						if client, err = s.clientRepo.FindByID(ctx, credit.ClientID); err != nil {
+							return err
+						}
+						clients[credit.ClientID] = client
+					}
+					due := s.terms.DueDate(client, credit.CreatedAt)
+					credit.DueDate = &due
+				}
+				credit.Status = credit.StatusAt(now)
+				if credit.Status == before.Status && before.DueDate != nil {
+					continue
+				}
+
+				if err := s.creditRepo.Update(ctx, credit); err != nil {
+					return err
+				}
+				if err := s.audit.record(ctx, domain.AuditCredit, credit.ID, domain.AuditUpdate, before, credit); err != nil {
+					return err
+				}
+				changed++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return changed, nil
 }
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/repository"
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestCredits returns a credit service over db and gives client c1 three
+// open credits, by name: "old" (30,00 €, granted 40 days ago, due in 5
+// days), "late" (20,00 €, granted 20 days ago, due 2 days ago) and "new"
+// (40,00 €, granted 5 days ago, due in 25 days).
+func newTestCredits(t *testing.T, db *sql.DB) (*CreditService, map[string]string) {
+	t.Helper()
+	sales := newTestSales(t, db, nil)
+	now := time.Now()
+	ids := map[string]string{}
+	for _, c := range []struct {
+		name           string
+		paid           domain.Money
+		granted, dueIn int
+	}{
+		{"old", 1000, -40, 5},
+		{"late", 2000, -20, -2},
+		{"new", 0, -5, 25},
+	} {
+		sale := sell(t, sales, c.paid)
+		credit, err := repository.NewCreditRepo(db).FindBySaleID(context.Background(), sale.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		due := now.AddDate(0, 0, c.dueIn)
+		credit.DueDate = &due
+		if _, err := db.Exec(`UPDATE credits SET created_at = ?, due_date = ?, status = ? WHERE id = ?`,
+			now.AddDate(0, 0, c.granted), due, credit.StatusAt(now), credit.ID); err != nil {
+			t.Fatal(err)
+		}
+		ids[c.name] = credit.ID
+	}
+	return NewCreditService(repository.NewCreditRepo(db), repository.NewClientRepo(db), sales.tx, sales.audit, domain.PaymentTerms{DefaultDays: 30}, 0), ids
+}
+
+func TestPayOnAccount(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   domain.Money
+		strategy domain.AllocationStrategy
+		want     []string                // credits paid, by name, in order
+		left     map[string]domain.Money // what is left on each credit
+		wantErr  string
+	}{
+		{
+			name:   "oldest first, partial on the last",
+			amount: 4000,
+			want:   []string{"old", "late"},
+			left:   map[string]domain.Money{"old": 0, "late": 1000, "new": 4000},
+		},
+		{
+			name:     "overdue first, partial on the last",
+			amount:   4000,
+			strategy: domain.AllocateOverdueFirst,
+			want:     []string{"late", "old"},
+			left:     map[string]domain.Money{"old": 1000, "late": 0, "new": 4000},
+		},
+		{
+			name:   "whole balance",
+			amount: 9000,
+			want:   []string{"old", "late", "new"},
+			left:   map[string]domain.Money{"old": 0, "late": 0, "new": 0},
+		},
+		{
+			name:    "more than owed",
+			amount:  9001,
+			left:    map[string]domain.Money{"old": 3000, "late": 2000, "new": 4000},
+			wantErr: "payment exceeds client balance",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			svc, ids := newTestCredits(t, db)
+			ctx := context.Background()
+			if b := balance(t, db, "c1"); b != 9000 {
+				t.Fatalf("total_credit = %s before paying, want 90.00", b)
+			}
+
+			receipt, err := svc.PayOnAccount(ctx, "c1", domain.CreateClientPaymentRequest{
+				Amount: tt.amount, Method: domain.PaymentCash, Strategy: tt.strategy,
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				if n := count(t, db, "payments"); n != 0 {
+					t.Fatalf("%d payments recorded, want none", n)
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(receipt.Payments) != len(tt.want) {
+					t.Fatalf("%d payments, want %d", len(receipt.Payments), len(tt.want))
+				}
+				var paid domain.Money
+				for i, p := range receipt.Payments {
+					if p.CreditID != ids[tt.want[i]] {
+						t.Errorf("payment %d went to %s, want the %q credit", i, p.CreditID, tt.want[i])
+					}
+					if p.ReceiptID != receipt.ReceiptID {
+						t.Errorf("payment %d has receipt %s, want %s", i, p.ReceiptID, receipt.ReceiptID)
+					}
+					paid += p.Amount
+				}
+				if paid != tt.amount {
+					t.Fatalf("payments add up to %s, want %s", paid, tt.amount)
+				}
+				var receipts int
+				if err := db.QueryRow(`SELECT COUNT(DISTINCT receipt_id) FROM payments`).Scan(&receipts); err != nil {
+					t.Fatal(err)
+				}
+				if receipts != 1 || count(t, db, "payments") != len(tt.want) {
+					t.Fatalf("%d payments stored under %d receipts, want %d under one", count(t, db, "payments"), receipts, len(tt.want))
+				}
+				if receipt.TotalCredit != 9000-tt.amount {
+					t.Errorf("receipt total credit = %s, want %s", receipt.TotalCredit, 9000-tt.amount)
+				}
+			}
+
+			for name, want := range tt.left {
+				c, err := svc.creditRepo.FindByID(ctx, ids[name])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if c.RemainingAmount != want {
+					t.Errorf("%q credit has %s left, want %s", name, c.RemainingAmount, want)
+				}
+				if (want == 0) != (c.Status == domain.CreditStatusPaye) {
+					t.Errorf("%q credit status = %s with %s left", name, c.Status, c.RemainingAmount)
+				}
+			}
+			wantBalance := domain.Money(9000)
+			if tt.wantErr == "" {
+				wantBalance -= tt.amount
+			}
+			if b := balance(t, db, "c1"); b != wantBalance {
+				t.Fatalf("total_credit = %s, want %s", b, wantBalance)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Errors wrapped by services so handlers can answer with a matching status.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrConflict     = errors.New("conflict")
+)
+
+// hasRole reports whether the user performing the request has one of roles.
+func hasRole(ctx context.Context, roles ...domain.Role) bool {
+	u := domain.UserFromContext(ctx)
+	if u == nil {
+		return false
+	}
+	for _, r := range roles {
+		if u.Role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// actor returns the ID and name of the user performing the request, if any.
+func actor(ctx context.Context) (id, name string) {
+	if u := domain.UserFromContext(ctx); u != nil {
+		return u.ID, u.Name
+	}
+	return "", ""
+}
+
+// CreditLimitError rejects a sale that would take a client over their credit
+// limit. Its fields tell the till how much credit is still available.
+type CreditLimitError struct {
+	ClientID        string       `json:"clientId"`
+	ClientName      string       `json:"clientName"`
+	Limit           domain.Money `json:"limit"`
+	Balance         domain.Money `json:"balance"`
+	Requested       domain.Money `json:"requested"`
+	Available       domain.Money `json:"available"`
+	OverrideAllowed bool         `json:"overrideAllowed"`
+}
+
+func (e *CreditLimitError) Error() string {
+	if !e.OverrideAllowed {
+		return "credit is not allowed for " + e.ClientName
+	}
+	return fmt.Sprintf("credit limit exceeded for %s: %s owed + %s requested is over the %s limit",
+		e.ClientName, e.Balance, e.Requested, e.Limit)
+}
+
+// TransitionError rejects moving an order to a status its current status
+// does not lead to.
+type TransitionError struct {
+	From    domain.OrderStatus   `json:"from"`
+	To      domain.OrderStatus   `json:"to"`
+	Allowed []domain.OrderStatus `json:"allowed"`
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("order cannot go from %s to %s", e.From, e.To)
+}
+
+// CapacityError rejects an order for a pickup day that cannot take it. Reason
+// is one of the domain.Slot* reasons; the weights are set for category_full.
+type CapacityError struct {
+	Date      string              `json:"date"`
+	Reason    string              `json:"reason"`
+	Category  domain.MeatCategory `json:"category,omitempty"`
+	LimitKg   float64             `json:"limitKg,omitempty"`
+	BookedKg  float64             `json:"bookedKg,omitempty"`
+	RequestKg float64             `json:"requestedKg,omitempty"`
+}
+
+func (e *CapacityError) Error() string {
+	switch e.Reason {
+	case domain.SlotClosed:
+		return "no pickups on " + e.Date
+	case domain.SlotCutoff:
+		return "too late to order for " + e.Date
+	case domain.SlotCategoryFull:
+		return fmt.Sprintf("not enough %s left on %s: %g kg booked of %g kg, %g kg requested",
+			e.Category, e.Date, e.BookedKg, e.LimitKg, e.RequestKg)
+	}
+	return "no more orders taken for " + e.Date
+}
@@ -5,6 +5,8 @@ import (
 	"boucherie-api/internal/port"
 	"context"
 	"errors"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,11 +17,40 @@ type OrderService struct {
 	orderRepo   port.OrderRepository
 	clientRepo  port.ClientRepository
 	productRepo port.ProductRepository
+	stockRepo   port.StockRepository
+	stock       *StockService
+	tx          port.Transactor
+	audit       *AuditService
+	sales       *SaleService
+	deposits    domain.DepositPolicy
+	capacity    domain.OrderCapacity
 }
 
 // NewOrderService creates a new order service.
-func NewOrderService(orderRepo port.OrderRepository, clientRepo port.ClientRepository, productRepo port.ProductRepository) *OrderService {
-	return &OrderService{orderRepo: orderRepo, clientRepo: clientRepo, productRepo: productRepo}
+func NewOrderService(
+	orderRepo port.OrderRepository,
+	clientRepo port.ClientRepository,
+	productRepo port.ProductRepository,
+	stockRepo port.StockRepository,
+	stock *StockService,
+	tx port.Transactor,
+	audit *AuditService,
+	sales *SaleService,
+	deposits domain.DepositPolicy,
+	capacity domain.OrderCapacity,
+) *OrderService {
+	return &OrderService{
+		orderRepo:   orderRepo,
+		clientRepo:  clientRepo,
+		productRepo: productRepo,
+		stockRepo:   stockRepo,
+		stock:       stock,
+		tx:          tx,
+		audit:       audit,
+		sales:       sales,
+		deposits:    deposits,
+		capacity:    capacity,
+	}
 }
 
 // List returns orders, optionally filtered by status.
@@ -39,8 +70,289 @@ func (s *OrderService) Get(ctx context.Context, id string) (*domain.Order, error
 	return order, nil
 }
 
-// Create validates and creates a new order.
+// Planning aggregates the orders picked up on date (YYYY-MM-DD, today by
+// default) into the list of products to prepare, with the weight each client
+// ordered. Cancelled orders are left out.
+func (s *OrderService) Planning(ctx context.Context, date string) (*domain.Planning, error) {
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, errors.New("invalid date format, expected YYYY-MM-DD")
+	}
+	orders, err := s.orderRepo.FindByPickupDate(ctx, date, date)
+	if err != nil {
+		return nil, err
+	}
+
+	planning := &domain.Planning{Date: date, Products: []domain.PlanningProduct{}}
+	byProduct := make(map[string]int)
+	for _, o := range orders {
+		if o.Status == domain.OrderStatusAnnulee {
+			continue
+		}
+		planning.OrderCount++
+		for _, it := range o.Items {
+			i, ok := byProduct[it.ProductID]
+			if !ok {
+				i = len(planning.Products)
+				byProduct[it.ProductID] = i
+				planning.Products = append(planning.Products, domain.PlanningProduct{
+					ProductID:   it.ProductID,
+					ProductName: it.ProductName,
+				})
+			}
+			p := &planning.Products[i]
+			p.TotalQuantity += it.Quantity
+			p.Clients = append(p.Clients, domain.PlanningClient{
+				OrderID:     o.ID,
+				ClientID:    o.ClientID,
+				ClientName:  o.ClientName,
+				ClientPhone: o.ClientPhone,
+				Status:      o.Status,
+				Quantity:    it.Quantity,
+			})
+		}
+	}
+	sort.Slice(planning.Products, func(i, j int) bool {
+		return planning.Products[i].ProductName < planning.Products[j].ProductName
+	})
+	return planning, nil
+}
+
+// Calendar counts the orders picked up each day of the week (Monday to
+// Sunday) containing date (YYYY-MM-DD, today by default).
+func (s *OrderService) Calendar(ctx context.Context, date string) (*domain.Calendar, error) {
+	day := time.Now()
+	if date != "" {
+		var err error
+		if day, err = time.Parse("2006-01-02", date); err != nil {
+			return nil, errors.New("invalid date format, expected YYYY-MM-DD")
+		}
+	}
+	monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	cal := &domain.Calendar{
+		From: monday.Format("2006-01-02"),
+		To:   monday.AddDate(0, 0, 6).Format("2006-01-02"),
+	}
+	counts, err := s.orderRepo.CountByPickupDate(ctx, cal.From, cal.To)
+	if err != nil {
+		return nil, err
+	}
+	byDate := make(map[string]domain.CalendarDay, len(counts))
+	for _, c := range counts {
+		byDate[c.Date] = c
+	}
+	for i := 0; i < 7; i++ {
+		d := monday.AddDate(0, 0, i).Format("2006-01-02")
+		c, ok := byDate[d]
+		if !ok {
+			c = domain.CalendarDay{Date: d, ByStatus: map[domain.OrderStatus]int{}}
+		}
+		cal.Days = append(cal.Days, c)
+	}
+	return cal, nil
+}
+
+// Slots tells, for each of the days pickup days from from (YYYY-MM-DD, today
+// by default), whether orders are still taken and how much is booked.
+func (s *OrderService) Slots(ctx context.Context, from string, days int) ([]domain.PickupSlot, error) {
+	if from == "" {
+		from = time.Now().Format("2006-01-02")
+	}
+	start, err := time.ParseInLocation("2006-01-02", from, time.Local)
+	if err != nil {
+		return nil, errors.New("invalid date format, expected YYYY-MM-DD")
+	}
+	end := start.AddDate(0, 0, days-1)
+	loads, err := s.bookedLoads(ctx, start.Format("2006-01-02"), end.Format("2006-01-02"), "")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	slots := make([]domain.PickupSlot, 0, days)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		load := loads[date]
+		if load == nil {
+			load = &dayLoad{kg: map[domain.MeatCategory]float64{}}
+		}
+		slot := domain.PickupSlot{
+			Date:       date,
+			Orders:     load.orders,
+			MaxOrders:  s.capacity.MaxOrdersPerDay,
+			Categories: []domain.CategoryLoad{},
+		}
+		if slot.MaxOrders > 0 {
+			slot.RemainingOrders = max(slot.MaxOrders-slot.Orders, 0)
+		}
+		for _, cat := range domain.ValidCategories {
+			maxKg, capped := s.capacity.MaxKgPerCategory[cat]
+			if !capped && load.kg[cat] == 0 {
+				continue
+			}
+			cl := domain.CategoryLoad{Category: cat, Kg: load.kg[cat], MaxKg: maxKg}
+			if capped {
+				cl.RemainingKg = max(maxKg-cl.Kg, 0)
+			}
+			slot.Categories = append(slot.Categories, cl)
+		}
+
+		switch {
+		case s.capacity.ClosedOn(day):
+			slot.Reason = domain.SlotClosed
+		case s.capacity.CutoffHours > 0 && now.After(s.capacity.Cutoff(day)):
+			slot.Reason = domain.SlotCutoff
+		case slot.MaxOrders > 0 && slot.Orders >= slot.MaxOrders:
+			slot.Reason = domain.SlotFull
+		}
+		slot.Available = slot.Reason == ""
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+// checkCapacity rejects an order with items for pickup on day when the day
+// is closed, past its cut-off or has no room left for it. Order exceptID is
+// left out of what is already booked.
+func (s *OrderService) checkCapacity(ctx context.Context, day time.Time, items []domain.OrderItem, exceptID string) error {
+	date := day.Format("2006-01-02")
+	if s.capacity.ClosedOn(day) {
+		return &CapacityError{Date: date, Reason: domain.SlotClosed}
+	}
+	if s.capacity.CutoffHours > 0 && time.Now().After(s.capacity.Cutoff(day)) {
+		return &CapacityError{Date: date, Reason: domain.SlotCutoff}
+	}
+	if s.capacity.MaxOrdersPerDay == 0 && len(s.capacity.MaxKgPerCategory) == 0 {
+		return nil
+	}
+
+	loads, err := s.bookedLoads(ctx, date, date, exceptID)
+	if err != nil {
+		return err
+	}
+	booked := loads[date]
+	if booked == nil {
+		booked = &dayLoad{kg: map[domain.MeatCategory]float64{}}
+	}
+	if limit := s.capacity.MaxOrdersPerDay; limit > 0 && booked.orders >= limit {
+		return &CapacityError{Date: date, Reason: domain.SlotFull}
+	}
+	return s.checkKg(ctx, date, booked, items, nil)
+}
+
+// checkItemsCapacity rejects edited lines of order that take a capped
+// category over its limit for the pickup day. The order is already booked,
+// so the day's closing, cut-off and order count are not checked again.
+func (s *OrderService) checkItemsCapacity(ctx context.Context, order *domain.Order, previous []domain.OrderItem) error {
+	if len(s.capacity.MaxKgPerCategory) == 0 {
+		return nil
+	}
+	date := order.PickupDate.Format("2006-01-02")
+	loads, err := s.bookedLoads(ctx, date, date, order.ID)
+	if err != nil {
+		return err
+	}
+	booked := loads[date]
+	if booked == nil {
+		booked = &dayLoad{kg: map[domain.MeatCategory]float64{}}
+	}
+	return s.checkKg(ctx, date, booked, order.Items, previous)
+}
+
+// checkKg rejects items that take a capped category over its limit on top
+// of what is booked for date. Categories items do not weigh more in than
+// previous are let through, so that an order can always shrink.
+func (s *OrderService) checkKg(ctx context.Context, date string, booked *dayLoad, items, previous []domain.OrderItem) error {
+	categories := map[string]domain.MeatCategory{}
+	requested := make(map[domain.MeatCategory]float64)
+	if err := s.addKg(ctx, requested, items, categories); err != nil {
+		return err
+	}
+	had := make(map[domain.MeatCategory]float64)
+	if err := s.addKg(ctx, had, previous, categories); err != nil {
+		return err
+	}
+	for _, cat := range domain.ValidCategories {
+		maxKg, capped := s.capacity.MaxKgPerCategory[cat]
+		if capped && requested[cat] > had[cat]+quantityEpsilon && booked.kg[cat]+requested[cat] > maxKg+quantityEpsilon {
+			return &CapacityError{
+				Date:      date,
+				Reason:    domain.SlotCategoryFull,
+				Category:  cat,
+				LimitKg:   maxKg,
+				BookedKg:  booked.kg[cat],
+				RequestKg: requested[cat],
+			}
+		}
+	}
+	return nil
+}
+
+// dayLoad is what is booked for a pickup day.
+type dayLoad struct {
+	orders int
+	kg     map[domain.MeatCategory]float64
+}
+
+// bookedLoads sums the orders that are not cancelled picked up between from
+// and to, by pickup day, leaving out order exceptID.
+func (s *OrderService) bookedLoads(ctx context.Context, from, to, exceptID string) (map[string]*dayLoad, error) {
+	orders, err := s.orderRepo.FindByPickupDate(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	loads := make(map[string]*dayLoad)
+	categories := make(map[string]domain.MeatCategory)
+	for _, o := range orders {
+		if o.Status == domain.OrderStatusAnnulee || o.ID == exceptID {
+			continue
+		}
+		date := o.PickupDate.Format("2006-01-02")
+		load := loads[date]
+		if load == nil {
+			load = &dayLoad{kg: map[domain.MeatCategory]float64{}}
+			loads[date] = load
+		}
+		load.orders++
+		if err := s.addKg(ctx, load.kg, o.Items, categories); err != nil {
+			return nil, err
+		}
+	}
+	return loads, nil
+}
+
+// addKg adds the weight of items to kg under their product's category.
+// categories caches the category of products already looked up.
+func (s *OrderService) addKg(ctx context.Context, kg map[domain.MeatCategory]float64, items []domain.OrderItem, categories map[string]domain.MeatCategory) error {
+	for _, it := range items {
+		cat, ok := categories[it.ProductID]
+		if !ok {
+			product, err := s.productRepo.FindByID(ctx, it.ProductID)
+			if err != nil {
+				return err
+			}
+			if product != nil {
+				cat = product.Category
+			}
+			categories[it.ProductID] = cat
+		}
+		if cat != "" {
+			kg[cat] += it.Quantity
+		}
+	}
+	return nil
+}
+
+// Create validates and creates a new order. The order comes back with
+// warnings for lines asking for more stock than is available for pickup.
 func (s *OrderService) Create(ctx context.Context, req domain.CreateOrderRequest) (*domain.Order, error) {
+	return s.create(ctx, req, "")
+}
+
+// create creates an order, from the recurring order recurringOrderID if set.
+func (s *OrderService) create(ctx context.Context, req domain.CreateOrderRequest, recurringOrderID string) (*domain.Order, error) {
 	client, err := s.clientRepo.FindByID(ctx, req.ClientID)
 	if err != nil {
 		return nil, err
@@ -49,7 +361,7 @@ func (s *OrderService) Create(ctx context.Context, req domain.CreateOrderRequest
 		return nil, errors.New("client not found")
 	}
 
-	pickupDate, err := time.Parse("2006-01-02", req.PickupDate)
+	pickupDate, err := time.ParseInLocation("2006-01-02", req.PickupDate, time.Local)
 	if err != nil {
 		return nil, errors.New("invalid pickup date format, expected YYYY-MM-DD")
 	}
@@ -68,6 +380,7 @@ func (s *OrderService) Create(ctx context.Context, req domain.CreateOrderRequest
 			ProductID:   product.ID,
 			ProductName: product.Name,
 			Quantity:    ri.Quantity,
+			PricePerKg:  product.PricePerKg,
 		})
 	}
 
@@ -81,52 +394,458 @@ func (s *OrderService) Create(ctx context.Context, req domain.CreateOrderRequest
 		Notes:       req.Notes,
 		Status:      domain.OrderStatusEnAttente,
 		CreatedAt:   time.Now(),
+
+		RecurringOrderID: recurringOrderID,
 	}
+	order.Estimate()
 
-	if err := s.orderRepo.Create(ctx, order); err != nil {
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkCapacity(ctx, order.PickupDate, order.Items, ""); err != nil {
+			return err
+		}
+		if err := s.orderRepo.Create(ctx, order); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditOrder, order.ID, domain.AuditCreate, nil, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if order.Warnings, err = s.stockWarnings(ctx, order); err != nil {
 		return nil, err
 	}
 	return order, nil
 }
 
-// Update modifies an existing order's status, date, or notes.
-func (s *OrderService) Update(ctx context.Context, id string, req domain.UpdateOrderRequest) (*domain.Order, error) {
-	order, err := s.orderRepo.FindByID(ctx, id)
+// stockWarnings lists the lines of order asking for more of a tracked product
+// than its stock less what orders picked up by then have reserved.
+func (s *OrderService) stockWarnings(ctx context.Context, order *domain.Order) ([]domain.StockWarning, error) {
+	reserved, err := s.stockRepo.ReservedUntil(ctx, order.PickupDate.Format("2006-01-02"))
 	if err != nil {
 		return nil, err
 	}
-	if order == nil {
-		return nil, errors.New("order not found")
+	var warnings []domain.StockWarning
+	requested := make(map[string]float64)
+	for _, it := range order.Items {
+		requested[it.ProductID] += it.Quantity
 	}
+	for _, it := range order.Items {
+		want, ok := requested[it.ProductID]
+		if !ok {
+			continue // product already checked
+		}
+		delete(requested, it.ProductID)
 
-	if req.Status != nil {
-		order.Status = *req.Status
+		level, err := s.stockRepo.Level(ctx, it.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if level == nil || !level.Tracked {
+			continue
+		}
+		available := level.OnHand - reserved[it.ProductID]
+		if want > available+quantityEpsilon {
+			warnings = append(warnings, domain.StockWarning{
+				ProductID:   it.ProductID,
+				ProductName: it.ProductName,
+				Requested:   want,
+				Available:   math.Max(domain.RoundKg(available), 0),
+			})
+		}
 	}
-	if req.PickupDate != nil {
-		d, err := time.Parse("2006-01-02", *req.PickupDate)
+	return warnings, nil
+}
+
+// Update modifies an existing order's pickup date or notes.
+func (s *OrderService) Update(ctx context.Context, id string, req domain.UpdateOrderRequest) (*domain.Order, error) {
+	var order *domain.Order
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.orderRepo.FindByID(ctx, id)
 		if err != nil {
-			return nil, errors.New("invalid pickup date format")
+			return err
+		}
+		if order == nil {
+			return errors.New("order not found")
+		}
+		before := *order
+
+		moved := false
+		if req.PickupDate != nil {
+			d, err := time.ParseInLocation("2006-01-02", *req.PickupDate, time.Local)
+			if err != nil {
+				return errors.New("invalid pickup date format")
+			}
+			moved = !d.Equal(order.PickupDate)
+			order.PickupDate = d
+		}
+		if req.Notes != nil {
+			order.Notes = *req.Notes
+		}
+
+		if moved && order.Status != domain.OrderStatusAnnulee {
+			if err := s.checkCapacity(ctx, order.PickupDate, order.Items, order.ID); err != nil {
+				return err
+			}
+		}
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			return err
 		}
-		order.PickupDate = d
+		return s.audit.record(ctx, domain.AuditOrder, order.ID, domain.AuditUpdate, before, order)
+	})
+	if err != nil {
+		return nil, err
 	}
-	if req.Notes != nil {
-		order.Notes = *req.Notes
+	return order, nil
+}
+
+// Transition moves an order to the next status of its lifecycle, refusing
+// moves the transition table does not allow.
+func (s *OrderService) Transition(ctx context.Context, id string, to domain.OrderStatus) (*domain.Order, error) {
+	var order *domain.Order
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.orderRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return errors.New("order not found")
+		}
+
+		before := *order
+		if err := transition(order, to, time.Now()); err != nil {
+			return err
+		}
+		switch to {
+		case domain.OrderStatusLivree:
+			if order.Deposit > 0 {
+				return errors.New("order has a deposit, deliver it through checkout")
+			}
+			if err := s.stock.deliver(ctx, order); err != nil {
+				return err
+			}
+		case domain.OrderStatusAnnulee:
+			if err := s.settleDeposit(ctx, order); err != nil {
+				return err
+			}
+		}
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditOrder, order.ID, domain.AuditUpdate, before, order)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return order, nil
+}
+
+// AddItem adds a line to an order that is not prepared yet, priced at the
+// product's current price.
+func (s *OrderService) AddItem(ctx context.Context, id string, req domain.CreateOrderItemRequest) (*domain.Order, error) {
+	return s.editItems(ctx, id, func(ctx context.Context, order *domain.Order) error {
+		product, err := s.productRepo.FindByID(ctx, req.ProductID)
+		if err != nil {
+			return err
+		}
+		if product == nil {
+			return errors.New("product not found: " + req.ProductID)
+		}
+		item := domain.OrderItem{
+			ID:          uuid.New().String(),
+			OrderID:     order.ID,
+			ProductID:   product.ID,
+			ProductName: product.Name,
+			Quantity:    req.Quantity,
+			PricePerKg:  product.PricePerKg,
+		}
+		if err := s.orderRepo.CreateItem(ctx, &item); err != nil {
+			return err
+		}
+		order.Items = append(order.Items, item)
+		return nil
+	})
+}
 
-	if err := s.orderRepo.Update(ctx, order); err != nil {
+// UpdateItem changes the quantity of a line of an order that is not prepared yet.
+func (s *OrderService) UpdateItem(ctx context.Context, id, itemID string, req domain.UpdateOrderItemRequest) (*domain.Order, error) {
+	return s.editItems(ctx, id, func(ctx context.Context, order *domain.Order) error {
+		i := findOrderItem(order.Items, itemID)
+		if i < 0 {
+			return errors.New("order item not found: " + itemID)
+		}
+		order.Items[i].Quantity = req.Quantity
+		return s.orderRepo.UpdateItem(ctx, &order.Items[i])
+	})
+}
+
+// RemoveItem removes a line from an order that is not prepared yet. An order
+// keeps at least one line; cancel it instead.
+func (s *OrderService) RemoveItem(ctx context.Context, id, itemID string) (*domain.Order, error) {
+	return s.editItems(ctx, id, func(ctx context.Context, order *domain.Order) error {
+		i := findOrderItem(order.Items, itemID)
+		if i < 0 {
+			return errors.New("order item not found: " + itemID)
+		}
+		if len(order.Items) == 1 {
+			return errors.New("cannot remove the last item, cancel the order instead")
+		}
+		if err := s.orderRepo.DeleteItem(ctx, itemID); err != nil {
+			return err
+		}
+		order.Items = append(order.Items[:i], order.Items[i+1:]...)
+		return nil
+	})
+}
+
+// editItems runs edit on the lines of an order that is not prepared yet,
+// checks the pickup day still has room for the edited lines, and records the
+// change in the order history.
+func (s *OrderService) editItems(ctx context.Context, id string, edit func(ctx context.Context, order *domain.Order) error) (*domain.Order, error) {
+	var order *domain.Order
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.orderRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return errors.New("order not found")
+		}
+		if !order.Status.ItemsEditable() {
+			return errors.New("order items can no longer be changed once the order is " + string(order.Status))
+		}
+
+		before := *order
+		before.Items = append([]domain.OrderItem(nil), order.Items...)
+		if err := edit(ctx, order); err != nil {
+			return err
+		}
+		if err := s.checkItemsCapacity(ctx, order, before.Items); err != nil {
+			return err
+		}
+		order.Estimate()
+		// The deposit was checked against the estimate when taken
+		if order.Deposit > order.EstimatedTotal {
+			return errors.New("deposit exceeds estimated total of the edited order")
+		}
+		return s.audit.record(ctx, domain.AuditOrder, order.ID, domain.AuditUpdate, before, order)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return order, nil
 }
 
+func findOrderItem(items []domain.OrderItem, id string) int {
+	for i := range items {
+		if items[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Checkout hands a ready order over to the client: the weighed lines are sold
+// through the sale service at their quoted prices (credit and client balance
+// included) less the deposit, the sale is linked to the order and the order is
+// marked delivered, all in one transaction.
+func (s *OrderService) Checkout(ctx context.Context, id string, req domain.CheckoutOrderRequest) (*domain.CheckoutResult, error) {
+	var result *domain.CheckoutResult
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return errors.New("order not found")
+		}
+		before := *order
+		if err := transition(order, domain.OrderStatusLivree, time.Now()); err != nil {
+			return err
+		}
+
+		// Lines are sold at the price quoted on the order
+		lines := make(map[string]domain.OrderItem, len(order.Items))
+		for _, it := range order.Items {
+			lines[it.ID] = it
+		}
+		saleReq := domain.CreateSaleRequest{
+			ClientID:      order.ClientID,
+			PaidAmount:    req.PaidAmount,
+			Method:        req.Method,
+			OverrideToken: req.OverrideToken,
+		}
+		var prices []domain.Money
+		seen := make(map[string]bool, len(req.Items))
+		for _, ri := range req.Items {
+			line, ok := lines[ri.OrderItemID]
+			if !ok {
+				return errors.New("order item not found: " + ri.OrderItemID)
+			}
+			if seen[ri.OrderItemID] {
+				return errors.New("order item listed twice: " + ri.OrderItemID)
+			}
+			seen[ri.OrderItemID] = true
+			saleReq.Items = append(saleReq.Items, domain.CreateSaleItemRequest{
+				ProductID: line.ProductID,
+				Quantity:  ri.Quantity,
+				LotID:     ri.LotID,
+			})
+			prices = append(prices, line.PricePerKg)
+		}
+
+		sale, err := s.sales.create(ctx, saleReq, saleOptions{orderID: order.ID, prices: prices, deposit: order.Deposit})
+		if err != nil {
+			return err
+		}
+		order.SaleID = sale.ID
+
+		// The deposit is deducted from the sale; what the sale did not use up
+		// is given back.
+		if order.Deposit > 0 {
+			if excess := order.Deposit - sale.DepositAmount; excess > 0 {
+				if err := s.repayDeposit(ctx, order, excess); err != nil {
+					return err
+				}
+			}
+			order.DepositOutcome = domain.DepositDeduit
+		}
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			return err
+		}
+		if err := s.audit.record(ctx, domain.AuditOrder, order.ID, domain.AuditUpdate, before, order); err != nil {
+			return err
+		}
+		result = &domain.CheckoutResult{Order: *order, Sale: *sale}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// transition applies a status change allowed by the transition table.
+func transition(order *domain.Order, to domain.OrderStatus, at time.Time) error {
+	if !order.Status.CanTransitionTo(to) {
+		allowed := order.Status.NextStatuses()
+		if allowed == nil {
+			allowed = []domain.OrderStatus{}
+		}
+		return &TransitionError{From: order.Status, To: to, Allowed: allowed}
+	}
+	order.MarkStatus(to, at)
+	return nil
+}
+
 // Delete removes an order by ID.
 func (s *OrderService) Delete(ctx context.Context, id string) error {
-	order, err := s.orderRepo.FindByID(ctx, id)
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return errors.New("order not found")
+		}
+		deposits, err := s.orderRepo.FindDeposits(ctx, id)
+		if err != nil {
+			return err
+		}
+		if len(deposits) > 0 {
+			return errors.New("order has deposits, cancel it instead")
+		}
+		if err := s.orderRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditOrder, id, domain.AuditDelete, order, nil)
+	})
+}
+
+// Deposits returns the deposits received and paid back on an order.
+func (s *OrderService) Deposits(ctx context.Context, id string) ([]domain.OrderDeposit, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.FindDeposits(ctx, id)
+}
+
+// AddDeposit records a deposit paid against an order before pickup.
+func (s *OrderService) AddDeposit(ctx context.Context, id string, req domain.CreateDepositRequest) (*domain.OrderDeposit, error) {
+	var deposit *domain.OrderDeposit
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return errors.New("order not found")
+		}
+		if order.Status == domain.OrderStatusLivree || order.Status == domain.OrderStatusAnnulee {
+			return errors.New("deposits are only taken before pickup")
+		}
+		if order.Deposit+req.Amount > order.EstimatedTotal {
+			return errors.New("deposit exceeds estimated total")
+		}
+
+		deposit = newDeposit(ctx, order.ID, domain.DepositKindAcompte, req.Amount, req.Method)
+		if err := s.orderRepo.CreateDeposit(ctx, deposit); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditDeposit, deposit.ID, domain.AuditCreate, nil, deposit)
+	})
 	if err != nil {
+		return nil, err
+	}
+	return deposit, nil
+}
+
+// settleDeposit applies the deposit policy to an order being cancelled: the
+// deposit is paid back if the order was cancelled early enough, kept otherwise.
+func (s *OrderService) settleDeposit(ctx context.Context, order *domain.Order) error {
+	if order.Deposit <= 0 {
+		return nil
+	}
+	if !s.deposits.Refunds(order.PickupDate, *order.CancelledAt) {
+		order.DepositOutcome = domain.DepositConserve
+		return nil
+	}
+	if err := s.repayDeposit(ctx, order, order.Deposit); err != nil {
 		return err
 	}
-	if order == nil {
-		return errors.New("order not found")
+	order.DepositOutcome = domain.DepositRembourse
+	return nil
+}
+
+// repayDeposit gives amount of the order's deposit back to the client.
+func (s *OrderService) repayDeposit(ctx context.Context, order *domain.Order, amount domain.Money) error {
+	refund := newDeposit(ctx, order.ID, domain.DepositKindRemboursement, amount, domain.PaymentCash)
+	if err := s.orderRepo.CreateDeposit(ctx, refund); err != nil {
+		return err
+	}
+	if err := s.audit.record(ctx, domain.AuditDeposit, refund.ID, domain.AuditCreate, nil, refund); err != nil {
+		return err
+	}
+	order.Deposit -= amount
+	return nil
+}
+
+// newDeposit builds a deposit movement attributed to the current user.
+func newDeposit(ctx context.Context, orderID string, kind domain.DepositKind, amount domain.Money, method domain.PaymentMethod) *domain.OrderDeposit {
+	if method == "" {
+		method = domain.PaymentCash
+	}
+	userID, userName := actor(ctx)
+	return &domain.OrderDeposit{
+		ID:       uuid.New().String(),
+		OrderID:  orderID,
+		Kind:     kind,
+		Amount:   amount,
+		Method:   method,
+		UserID:   userID,
+		UserName: userName,
+		Date:     time.Now(),
 	}
-	return s.orderRepo.Delete(ctx, id)
 }
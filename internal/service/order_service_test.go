@@ -0,0 +1,296 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestOrders returns an order service over db with the capacity limits,
+// and the client and product of newTestSales.
+func newTestOrders(t *testing.T, db *sql.DB, capacity domain.OrderCapacity) *OrderService {
+	t.Helper()
+	sales := newTestSales(t, db, nil)
+	return NewOrderService(
+		repository.NewOrderRepo(db), repository.NewClientRepo(db), repository.NewProductRepo(db), repository.NewStockRepo(db),
+		sales.stock, repository.NewTxManager(db), sales.audit, sales, domain.DepositPolicy{RefundBeforeHours: 48}, capacity,
+	)
+}
+
+// order books 2 kg of Entrecôte for pickup in three days, with a deposit if set.
+func order(t *testing.T, svc *OrderService, deposit domain.Money) *domain.Order {
+	t.Helper()
+	ctx := context.Background()
+	o, err := svc.Create(ctx, domain.CreateOrderRequest{
+		ClientID:   "c1",
+		Items:      []domain.CreateOrderItemRequest{{ProductID: "p1", Quantity: 2}},
+		PickupDate: time.Now().AddDate(0, 0, 3).Format("2006-01-02"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deposit > 0 {
+		if _, err := svc.AddDeposit(ctx, o.ID, domain.CreateDepositRequest{Amount: deposit}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return o
+}
+
+func TestEditItemsKeepsDepositWithinEstimate(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity float64
+		wantErr  string
+	}{
+		{name: "still covers the deposit", quantity: 1.5},
+		{name: "down to the deposit", quantity: 1},
+		{name: "below the deposit", quantity: 0.5, wantErr: "deposit exceeds estimated total"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			svc := newTestOrders(t, db, domain.OrderCapacity{})
+			ctx := context.Background()
+			o := order(t, svc, 2000)
+
+			_, err := svc.UpdateItem(ctx, o.ID, o.Items[0].ID, domain.UpdateOrderItemRequest{Quantity: tt.quantity})
+			got, gerr := svc.Get(ctx, o.ID)
+			if gerr != nil {
+				t.Fatal(gerr)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				if got.Items[0].Quantity != 2 || got.EstimatedTotal != 4000 {
+					t.Fatalf("rejected edit left %.3f kg for %s, want 2 kg for 40.00", got.Items[0].Quantity, got.EstimatedTotal)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Deposit > got.EstimatedTotal {
+				t.Fatalf("deposit %s over the estimated total %s", got.Deposit, got.EstimatedTotal)
+			}
+		})
+	}
+}
+
+func TestCutoffInLocalTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		zone    *time.Location
+		hours   int // cut-off, relative to the hours left until the pickup day
+		wantErr bool
+	}{
+		{name: "east of UTC, before the cut-off", zone: time.FixedZone("UTC+10", 10*3600), hours: -1},
+		{name: "east of UTC, past the cut-off", zone: time.FixedZone("UTC+10", 10*3600), hours: 1, wantErr: true},
+		{name: "west of UTC, before the cut-off", zone: time.FixedZone("UTC-10", -10*3600), hours: -1},
+		{name: "west of UTC, past the cut-off", zone: time.FixedZone("UTC-10", -10*3600), hours: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			local := time.Local
+			time.Local = tt.zone
+			t.Cleanup(func() { time.Local = local })
+
+			// Pickup tomorrow, local midnight being a few hours away
+			now := time.Now().In(tt.zone)
+			tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, tt.zone)
+			left := int(time.Until(tomorrow) / time.Hour)
+			db := openTestDB(t)
+			svc := newTestOrders(t, db, domain.OrderCapacity{CutoffHours: left + tt.hours})
+
+			_, err := svc.Create(context.Background(), domain.CreateOrderRequest{
+				ClientID:   "c1",
+				Items:      []domain.CreateOrderItemRequest{{ProductID: "p1", Quantity: 1}},
+				PickupDate: tomorrow.Format("2006-01-02"),
+			})
+			var capErr *CapacityError
+			if tt.wantErr {
+				if !errors.As(err, &capErr) || capErr.Reason != domain.SlotCutoff {
+					t.Fatalf("error = %v, want the cut-off", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("cut-off %dh before %s refused at %s: %v", left+tt.hours, tomorrow, now, err)
+			}
+		})
+	}
+}
+
+func TestEditItemsCategoryLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limitKg float64 // on beef, with 4 kg booked over two orders
+		edit    func(svc *OrderService, o *domain.Order) (*domain.Order, error)
+		wantErr bool
+	}{
+		{
+			name:    "line up to the limit",
+			limitKg: 5,
+			edit: func(svc *OrderService, o *domain.Order) (*domain.Order, error) {
+				return svc.UpdateItem(context.Background(), o.ID, o.Items[0].ID, domain.UpdateOrderItemRequest{Quantity: 3})
+			},
+		},
+		{
+			name:    "line over the limit",
+			limitKg: 5,
+			edit: func(svc *OrderService, o *domain.Order) (*domain.Order, error) {
+				return svc.UpdateItem(context.Background(), o.ID, o.Items[0].ID, domain.UpdateOrderItemRequest{Quantity: 3.5})
+			},
+			wantErr: true,
+		},
+		{
+			name:    "new line up to the limit",
+			limitKg: 5,
+			edit: func(svc *OrderService, o *domain.Order) (*domain.Order, error) {
+				return svc.AddItem(context.Background(), o.ID, domain.CreateOrderItemRequest{ProductID: "p1", Quantity: 1})
+			},
+		},
+		{
+			name:    "new line over the limit",
+			limitKg: 5,
+			edit: func(svc *OrderService, o *domain.Order) (*domain.Order, error) {
+				return svc.AddItem(context.Background(), o.ID, domain.CreateOrderItemRequest{ProductID: "p1", Quantity: 1.5})
+			},
+			wantErr: true,
+		},
+		{
+			name:    "shrinking a day already over the limit",
+			limitKg: 3,
+			edit: func(svc *OrderService, o *domain.Order) (*domain.Order, error) {
+				return svc.UpdateItem(context.Background(), o.ID, o.Items[0].ID, domain.UpdateOrderItemRequest{Quantity: 1.5})
+			},
+		},
+		{
+			name:    "growing a day already over the limit",
+			limitKg: 3,
+			edit: func(svc *OrderService, o *domain.Order) (*domain.Order, error) {
+				return svc.UpdateItem(context.Background(), o.ID, o.Items[0].ID, domain.UpdateOrderItemRequest{Quantity: 2.5})
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			svc := newTestOrders(t, db, domain.OrderCapacity{})
+			order(t, svc, 0)
+			o := order(t, svc, 0)
+			// The limit may be lowered once orders are booked
+			svc.capacity.MaxKgPerCategory = map[domain.MeatCategory]float64{domain.CategoryBoeuf: tt.limitKg}
+
+			_, err := tt.edit(svc, o)
+			var capErr *CapacityError
+			if tt.wantErr {
+				if !errors.As(err, &capErr) || capErr.Reason != domain.SlotCategoryFull || capErr.Category != domain.CategoryBoeuf {
+					t.Fatalf("error = %v, want beef over its limit", err)
+				}
+				got, err := svc.Get(context.Background(), o.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(got.Items) != 1 || got.Items[0].Quantity != 2 {
+					t.Fatalf("refused edit left %+v, want the 2 kg line", got.Items)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestCheckoutWithDeposit(t *testing.T) {
+	tests := []struct {
+		name        string
+		weighed     float64 // kg of the 2 kg ordered, at 20,00 €/kg
+		paid        domain.Money
+		wantDeposit domain.Money // deducted from the sale
+		wantRepaid  domain.Money
+		wantCredit  domain.Money
+		wantErr     string
+	}{
+		{name: "rest paid", weighed: 2, paid: 1000, wantDeposit: 3000},
+		{name: "rest on credit", weighed: 2, wantDeposit: 3000, wantCredit: 1000},
+		{name: "lighter than the deposit", weighed: 1, wantDeposit: 2000, wantRepaid: 1000},
+		{name: "paid over what is due", weighed: 2, paid: 1500, wantErr: "paid amount exceeds total less deposit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			svc := newTestOrders(t, db, domain.OrderCapacity{})
+			ctx := context.Background()
+			o := order(t, svc, 3000)
+			for _, to := range []domain.OrderStatus{domain.OrderStatusConfirmee, domain.OrderStatusPrete} {
+				if _, err := svc.Transition(ctx, o.ID, to); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			res, err := svc.Checkout(ctx, o.ID, domain.CheckoutOrderRequest{
+				Items:      []domain.CheckoutItemRequest{{OrderItemID: o.Items[0].ID, Quantity: tt.weighed}},
+				PaidAmount: tt.paid,
+				Method:     domain.PaymentCash,
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				got, err := svc.Get(ctx, o.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Status != domain.OrderStatusPrete || got.Deposit != 3000 || count(t, db, "sales") != 0 {
+					t.Fatalf("refused checkout left the order %s with a %s deposit and %d sales", got.Status, got.Deposit, count(t, db, "sales"))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.Sale.DepositAmount != tt.wantDeposit || res.Sale.CreditAmount != tt.wantCredit {
+				t.Fatalf("sale took %s of the deposit and %s on credit, want %s and %s",
+					res.Sale.DepositAmount, res.Sale.CreditAmount, tt.wantDeposit, tt.wantCredit)
+			}
+			if res.Sale.DepositAmount+res.Sale.PaidAmount+res.Sale.CreditAmount != res.Sale.Total {
+				t.Fatalf("sale of %s settled by %s deposit, %s paid and %s credit",
+					res.Sale.Total, res.Sale.DepositAmount, res.Sale.PaidAmount, res.Sale.CreditAmount)
+			}
+			var repaid domain.Money
+			if err := db.QueryRow(`SELECT COALESCE(SUM(amount), 0) FROM order_deposits WHERE order_id = ? AND kind = ?`,
+				o.ID, domain.DepositKindRemboursement).Scan(&repaid); err != nil {
+				t.Fatal(err)
+			}
+			if repaid != tt.wantRepaid {
+				t.Fatalf("%s of the deposit repaid, want %s", repaid, tt.wantRepaid)
+			}
+			got, err := svc.Get(ctx, o.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != domain.OrderStatusLivree || got.Deposit != tt.wantDeposit || got.DepositOutcome != domain.DepositDeduit {
+				t.Fatalf("order %s with a %s deposit %s, want %s with %s %s",
+					got.Status, got.Deposit, got.DepositOutcome, domain.OrderStatusLivree, tt.wantDeposit, domain.DepositDeduit)
+			}
+			if b := balance(t, db, "c1"); b != tt.wantCredit {
+				t.Fatalf("total_credit = %s, want %s", b, tt.wantCredit)
+			}
+		})
+	}
+}
@@ -5,26 +5,45 @@ import (
 	"boucherie-api/internal/port"
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 )
 
 // ProductService handles product business logic.
 type ProductService struct {
-	repo port.ProductRepository
+	repo  port.ProductRepository
+	stock port.StockRepository
+	tx    port.Transactor
+	audit *AuditService
 }
 
 // NewProductService creates a new product service.
-func NewProductService(repo port.ProductRepository) *ProductService {
-	return &ProductService{repo: repo}
+func NewProductService(repo port.ProductRepository, stock port.StockRepository, tx port.Transactor, audit *AuditService) *ProductService {
+	return &ProductService{repo: repo, stock: stock, tx: tx, audit: audit}
 }
 
-// List returns all products, optionally filtered by category.
+// List returns all products with their stock, optionally filtered by category.
 func (s *ProductService) List(ctx context.Context, category *domain.MeatCategory) ([]domain.Product, error) {
-	return s.repo.FindAll(ctx, category)
+	products, err := s.repo.FindAll(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+	levels, err := s.stock.Levels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*domain.StockSummary, len(levels))
+	for i := range levels {
+		byID[levels[i].ProductID] = &levels[i].StockSummary
+	}
+	for i := range products {
+		products[i].Stock = byID[products[i].ID]
+	}
+	return products, nil
 }
 
-// Get returns a single product by ID.
+// Get returns a single product by ID, with its stock.
 func (s *ProductService) Get(ctx context.Context, id string) (*domain.Product, error) {
 	p, err := s.repo.FindByID(ctx, id)
 	if err != nil {
@@ -33,11 +52,22 @@ func (s *ProductService) Get(ctx context.Context, id string) (*domain.Product, e
 	if p == nil {
 		return nil, errors.New("product not found")
 	}
+	level, err := s.stock.Level(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if level != nil {
+		p.Stock = &level.StockSummary
+	}
 	return p, nil
 }
 
-// Create validates and creates a new product.
+// Create validates and creates a new product. Only the owner sets prices, so
+// only the owner may create products.
 func (s *ProductService) Create(ctx context.Context, req domain.CreateProductRequest) (*domain.Product, error) {
+	if !hasRole(ctx, domain.RoleOwner) {
+		return nil, fmt.Errorf("%w: only the owner can set prices", ErrForbidden)
+	}
 	product := &domain.Product{
 		ID:         uuid.New().String(),
 		Name:       req.Name,
@@ -46,7 +76,13 @@ func (s *ProductService) Create(ctx context.Context, req domain.CreateProductReq
 		Image:      req.Image,
 		InStock:    true,
 	}
-	if err := s.repo.Create(ctx, product); err != nil {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, product); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditProduct, product.ID, domain.AuditCreate, nil, product)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return product, nil
@@ -54,31 +90,43 @@ func (s *ProductService) Create(ctx context.Context, req domain.CreateProductReq
 
 // Update modifies an existing product's fields.
 func (s *ProductService) Update(ctx context.Context, id string, req domain.UpdateProductRequest) (*domain.Product, error) {
-	product, err := s.repo.FindByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	if product == nil {
-		return nil, errors.New("product not found")
-	}
+	var product *domain.Product
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		product, err = s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if product == nil {
+			return errors.New("product not found")
+		}
+		before := *product
 
-	if req.Name != nil {
-		product.Name = *req.Name
-	}
-	if req.Category != nil {
-		product.Category = *req.Category
-	}
-	if req.PricePerKg != nil {
-		product.PricePerKg = *req.PricePerKg
-	}
-	if req.Image != nil {
-		product.Image = *req.Image
-	}
-	if req.InStock != nil {
-		product.InStock = *req.InStock
-	}
+		if req.Name != nil {
+			product.Name = *req.Name
+		}
+		if req.Category != nil {
+			product.Category = *req.Category
+		}
+		if req.PricePerKg != nil && *req.PricePerKg != product.PricePerKg {
+			if !hasRole(ctx, domain.RoleOwner) {
+				return fmt.Errorf("%w: only the owner can change prices", ErrForbidden)
+			}
+			product.PricePerKg = *req.PricePerKg
+		}
+		if req.Image != nil {
+			product.Image = *req.Image
+		}
+		if req.InStock != nil {
+			product.InStock = *req.InStock
+		}
 
-	if err := s.repo.Update(ctx, product); err != nil {
+		if err := s.repo.Update(ctx, product); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditProduct, product.ID, domain.AuditUpdate, before, product)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return product, nil
@@ -86,12 +134,17 @@ func (s *ProductService) Update(ctx context.Context, id string, req domain.Updat
 
 // Delete removes a product by ID.
 func (s *ProductService) Delete(ctx context.Context, id string) error {
-	p, err := s.repo.FindByID(ctx, id)
-	if err != nil {
-		return err
-	}
-	if p == nil {
-		return errors.New("product not found")
-	}
-	return s.repo.Delete(ctx, id)
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return errors.New("product not found")
+		}
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditProduct, id, domain.AuditDelete, p, nil)
+	})
 }
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/port"
+	"context"
+	"time"
+)
+
+// ReconciliationService checks the denormalised clients.total_credit against
+// the credits it summarises.
+type ReconciliationService struct {
+	clientRepo port.ClientRepository
+	creditRepo port.CreditRepository
+	tx         port.Transactor
+	audit      *AuditService
+}
+
+// NewReconciliationService creates a new reconciliation service.
+func NewReconciliationService(
+	clientRepo port.ClientRepository,
+	creditRepo port.CreditRepository,
+	tx port.Transactor,
+	audit *AuditService,
+) *ReconciliationService {
+	return &ReconciliationService{clientRepo: clientRepo, creditRepo: creditRepo, tx: tx, audit: audit}
+}
+
+// Reconcile recomputes every client's balance from the remaining amounts of
+// their credits and reports those that drifted. Unless dryRun is set, drifted
+// balances are overwritten with the computed value and each repair audited.
+func (s *ReconciliationService) Reconcile(ctx context.Context, dryRun bool) (*domain.ReconciliationReport, error) {
+	report := &domain.ReconciliationReport{
+		CheckedAt:     time.Now(),
+		DryRun:        dryRun,
+		Discrepancies: []domain.BalanceDiscrepancy{},
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		clients, err := s.clientRepo.FindAll(ctx)
+		if err != nil {
+			return err
+		}
+		remaining, err := s.creditRepo.RemainingByClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		report.ClientsChecked = len(clients)
+		for i := range clients {
+			client := &clients[i]
+			computed := remaining[client.ID]
+			if client.TotalCredit == computed {
+				continue
+			}
+			report.Discrepancies = append(report.Discrepancies, domain.BalanceDiscrepancy{
+				ClientID:   client.ID,
+				ClientName: client.Name,
+				Recorded:   client.TotalCredit,
+				Computed:   computed,
+				Difference: client.TotalCredit - computed,
+			})
+			if dryRun {
+				continue
+			}
+
+			before := *client
+			client.TotalCredit = computed
+			if err := s.clientRepo.SetTotalCredit(ctx, client.ID, computed); err != nil {
+				return err
+			}
+			if err := s.audit.record(ctx, domain.AuditClient, client.ID, domain.AuditUpdate, before, client); err != nil {
+				return err
+			}
+			report.Repaired++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/repository"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name   string
+		dryRun bool
+	}{
+		{name: "dry run", dryRun: true},
+		{name: "repair"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			sales := newTestSales(t, db, nil)
+			svc := NewReconciliationService(repository.NewClientRepo(db), repository.NewCreditRepo(db), sales.tx, sales.audit)
+			ctx := context.Background()
+
+			clientRepo := repository.NewClientRepo(db)
+			for _, c := range []domain.Client{
+				{ID: "c2", Name: "Nadia", Phone: "0600000002"},
+				{ID: "c3", Name: "Louis", Phone: "0600000003"},
+			} {
+				c.CreatedAt = time.Now()
+				if err := clientRepo.Create(ctx, &c); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// c1 owes 30,00 € but is recorded at 35,00 €, c2 owes nothing but
+			// is recorded at 12,00 €, c3 owes 40,00 € as recorded.
+			sell(t, sales, 1000)
+			if _, err := sales.Create(ctx, domain.CreateSaleRequest{
+				ClientID: "c3",
+				Items:    []domain.CreateSaleItemRequest{{ProductID: "p1", Quantity: 2}},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := db.Exec(`UPDATE clients SET total_credit = CASE id WHEN 'c1' THEN 3500 WHEN 'c2' THEN 1200 ELSE total_credit END`); err != nil {
+				t.Fatal(err)
+			}
+			audited := count(t, db, "audit_log")
+
+			report, err := svc.Reconcile(ctx, tt.dryRun)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.ClientsChecked != 3 || report.DryRun != tt.dryRun {
+				t.Fatalf("checked %d clients, dry run %v, want 3 and %v", report.ClientsChecked, report.DryRun, tt.dryRun)
+			}
+			want := map[string]domain.BalanceDiscrepancy{
+				"c1": {Recorded: 3500, Computed: 3000, Difference: 500},
+				"c2": {Recorded: 1200, Computed: 0, Difference: 1200},
+			}
+			if len(report.Discrepancies) != len(want) {
+				t.Fatalf("discrepancies %+v, want c1 and c2", report.Discrepancies)
+			}
+			for _, d := range report.Discrepancies {
+				w, ok := want[d.ClientID]
+				if !ok || d.Recorded != w.Recorded || d.Computed != w.Computed || d.Difference != w.Difference {
+					t.Errorf("discrepancy %+v, want %+v", d, w)
+				}
+			}
+
+			balances := map[string]domain.Money{"c1": 3000, "c2": 0, "c3": 4000}
+			repaired, newRows := 2, 2
+			if tt.dryRun {
+				balances = map[string]domain.Money{"c1": 3500, "c2": 1200, "c3": 4000}
+				repaired, newRows = 0, 0
+			}
+			for id, want := range balances {
+				if b := balance(t, db, id); b != want {
+					t.Errorf("%s total_credit = %s, want %s", id, b, want)
+				}
+			}
+			if report.Repaired != repaired {
+				t.Errorf("repaired %d, want %d", report.Repaired, repaired)
+			}
+			if n := count(t, db, "audit_log") - audited; n != newRows {
+				t.Fatalf("%d audit rows written, want %d", n, newRows)
+			}
+			if !tt.dryRun {
+				rows, err := db.Query(`SELECT entity, entity_id, action FROM audit_log ORDER BY rowid DESC LIMIT 2`)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer rows.Close()
+				fixed := map[string]bool{}
+				for rows.Next() {
+					var entity domain.AuditEntity
+					var id string
+					var action domain.AuditAction
+					if err := rows.Scan(&entity, &id, &action); err != nil {
+						t.Fatal(err)
+					}
+					if entity != domain.AuditClient || action != domain.AuditUpdate {
+						t.Fatalf("repair audited a %s %s, want a client update", entity, action)
+					}
+					fixed[id] = true
+				}
+				if !fixed["c1"] || !fixed["c2"] {
+					t.Fatalf("repairs audited for %v, want c1 and c2", fixed)
+				}
+			}
+		})
+	}
+}
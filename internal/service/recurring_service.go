@@ -0,0 +1,350 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/port"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecurringOrderService handles recurring order templates and creates their
+// orders ahead of each occurrence.
+type RecurringOrderService struct {
+	repo        port.RecurringOrderRepository
+	orderRepo   port.OrderRepository
+	clientRepo  port.ClientRepository
+	productRepo port.ProductRepository
+	orders      *OrderService
+	tx          port.Transactor
+	audit       *AuditService
+	daysAhead   int
+}
+
+// NewRecurringOrderService creates a new recurring order service. Orders are
+// created daysAhead days before pickup.
+func NewRecurringOrderService(
+	repo port.RecurringOrderRepository,
+	orderRepo port.OrderRepository,
+	clientRepo port.ClientRepository,
+	productRepo port.ProductRepository,
+	orders *OrderService,
+	tx port.Transactor,
+	audit *AuditService,
+	daysAhead int,
+) *RecurringOrderService {
+	return &RecurringOrderService{
+		repo:        repo,
+		orderRepo:   orderRepo,
+		clientRepo:  clientRepo,
+		productRepo: productRepo,
+		orders:      orders,
+		tx:          tx,
+		audit:       audit,
+		daysAhead:   daysAhead,
+	}
+}
+
+// List returns every template.
+func (s *RecurringOrderService) List(ctx context.Context) ([]domain.RecurringOrder, error) {
+	return s.repo.FindAll(ctx, false)
+}
+
+// Get returns a single template by ID.
+func (s *RecurringOrderService) Get(ctx context.Context, id string) (*domain.RecurringOrder, error) {
+	ro, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ro == nil {
+		return nil, errors.New("recurring order not found")
+	}
+	return ro, nil
+}
+
+// Create validates and creates a new template.
+func (s *RecurringOrderService) Create(ctx context.Context, req domain.CreateRecurringOrderRequest) (*domain.RecurringOrder, error) {
+	client, err := s.clientRepo.FindByID(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, errors.New("client not found")
+	}
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.New("invalid start date format, expected YYYY-MM-DD")
+	}
+
+	ro := &domain.RecurringOrder{
+		ID:         uuid.New().String(),
+		ClientID:   client.ID,
+		ClientName: client.Name,
+		Weekdays:   weekdays(req.Weekdays),
+		StartDate:  start,
+		Notes:      req.Notes,
+		Active:     true,
+		Skips:      []string{},
+		CreatedAt:  time.Now(),
+	}
+	if err := s.setEndDate(ro, req.EndDate); err != nil {
+		return nil, err
+	}
+	if ro.Items, err = s.items(ctx, ro.ID, req.Items); err != nil {
+		return nil, err
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, ro); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditRecurringOrder, ro.ID, domain.AuditCreate, nil, ro)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ro, nil
+}
+
+// Update changes a template. Orders already created from it are left as they are.
+func (s *RecurringOrderService) Update(ctx context.Context, id string, req domain.UpdateRecurringOrderRequest) (*domain.RecurringOrder, error) {
+	var ro *domain.RecurringOrder
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if ro, err = s.Get(ctx, id); err != nil {
+			return err
+		}
+		before := *ro
+
+		if req.Items != nil {
+			if ro.Items, err = s.items(ctx, ro.ID, req.Items); err != nil {
+				return err
+			}
+		}
+		if req.Weekdays != nil {
+			ro.Weekdays = weekdays(req.Weekdays)
+		}
+		if req.EndDate != nil {
+			if err := s.setEndDate(ro, *req.EndDate); err != nil {
+				return err
+			}
+		}
+		if req.Notes != nil {
+			ro.Notes = *req.Notes
+		}
+		if req.Active != nil {
+			ro.Active = *req.Active
+		}
+
+		if err := s.repo.Update(ctx, ro); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditRecurringOrder, ro.ID, domain.AuditUpdate, before, ro)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ro, nil
+}
+
+// Delete removes a template. Orders already created from it are kept.
+func (s *RecurringOrderService) Delete(ctx context.Context, id string) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ro, err := s.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditRecurringOrder, id, domain.AuditDelete, ro, nil)
+	})
+}
+
+// Skip leaves out the occurrence on date (YYYY-MM-DD). If its order was
+// already created, it is cancelled.
+func (s *RecurringOrderService) Skip(ctx context.Context, id, date string) (*domain.RecurringOrder, error) {
+	var ro *domain.RecurringOrder
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if ro, err = s.occurrence(ctx, id, date); err != nil {
+			return err
+		}
+		if ro.Skipped(date) {
+			return nil
+		}
+		order, err := s.orderRepo.FindByOccurrence(ctx, id, date)
+		if err != nil {
+			return err
+		}
+		if order != nil && order.Status != domain.OrderStatusAnnulee {
+			if _, err := s.orders.Transition(ctx, order.ID, domain.OrderStatusAnnulee); err != nil {
+				return err
+			}
+		}
+
+		before := *ro
+		if err := s.repo.AddSkip(ctx, id, date); err != nil {
+			return err
+		}
+		ro.Skips = append(append([]string{}, ro.Skips...), date)
+		return s.audit.record(ctx, domain.AuditRecurringOrder, ro.ID, domain.AuditUpdate, before, ro)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ro, nil
+}
+
+// ModifyOccurrence orders the occurrence on date (YYYY-MM-DD) right away with
+// other lines or notes than the template's. Once an occurrence's order
+// exists, it is edited like any other order.
+func (s *RecurringOrderService) ModifyOccurrence(ctx context.Context, id, date string, req domain.OccurrenceRequest) (*domain.Order, error) {
+	var order *domain.Order
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ro, err := s.occurrence(ctx, id, date)
+		if err != nil {
+			return err
+		}
+		if ro.Skipped(date) {
+			return errors.New("occurrence on " + date + " is skipped")
+		}
+		existing, err := s.orderRepo.FindByOccurrence(ctx, id, date)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return errors.New("occurrence on " + date + " is already ordered, edit order " + existing.ID + " instead")
+		}
+
+		notes := ro.Notes
+		if req.Notes != nil {
+			notes = *req.Notes
+		}
+		order, err = s.orders.create(ctx, domain.CreateOrderRequest{
+			ClientID:   ro.ClientID,
+			Items:      req.Items,
+			PickupDate: date,
+			Notes:      notes,
+		}, ro.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Materialize creates the orders of the active templates for their
+// occurrences in the next daysAhead days, skipping those already ordered or
+// skipped. Occurrences that cannot be ordered, e.g. for lack of capacity, are
+// reported and tried again on the next run.
+func (s *RecurringOrderService) Materialize(ctx context.Context, now time.Time) (*domain.MaterializeReport, error) {
+	templates, err := s.repo.FindAll(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	today, _ := time.Parse("2006-01-02", now.Format("2006-01-02"))
+
+	report := &domain.MaterializeReport{Failures: []domain.OccurrenceFailure{}}
+	for _, ro := range templates {
+		for i := 1; i <= s.daysAhead; i++ {
+			day := today.AddDate(0, 0, i)
+			date := day.Format("2006-01-02")
+			if !ro.OccursOn(day) || ro.Skipped(date) {
+				continue
+			}
+			existing, err := s.orderRepo.FindByOccurrence(ctx, ro.ID, date)
+			if err != nil {
+				return nil, err
+			}
+			if existing != nil {
+				continue
+			}
+
+			req := domain.CreateOrderRequest{ClientID: ro.ClientID, PickupDate: date, Notes: ro.Notes}
+			for _, it := range ro.Items {
+				req.Items = append(req.Items, domain.CreateOrderItemRequest{ProductID: it.ProductID, Quantity: it.Quantity})
+			}
+			if _, err := s.orders.create(ctx, req, ro.ID); err != nil {
+				report.Failures = append(report.Failures, domain.OccurrenceFailure{
+					RecurringOrderID: ro.ID,
+					Date:             date,
+					Error:            err.Error(),
+				})
+				continue
+			}
+			report.Created++
+		}
+	}
+	return report, nil
+}
+
+// occurrence returns template id after checking it has an occurrence on date.
+func (s *RecurringOrderService) occurrence(ctx context.Context, id, date string) (*domain.RecurringOrder, error) {
+	ro, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, errors.New("invalid date format, expected YYYY-MM-DD")
+	}
+	if !ro.OccursOn(day) {
+		return nil, errors.New("recurring order has no occurrence on " + date)
+	}
+	return ro, nil
+}
+
+// items builds the lines of a template from the requested products.
+func (s *RecurringOrderService) items(ctx context.Context, id string, reqs []domain.CreateOrderItemRequest) ([]domain.RecurringOrderItem, error) {
+	var items []domain.RecurringOrderItem
+	for _, ri := range reqs {
+		product, err := s.productRepo.FindByID(ctx, ri.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil {
+			return nil, errors.New("product not found: " + ri.ProductID)
+		}
+		items = append(items, domain.RecurringOrderItem{
+			ID:               uuid.New().String(),
+			RecurringOrderID: id,
+			ProductID:        product.ID,
+			ProductName:      product.Name,
+			Quantity:         ri.Quantity,
+		})
+	}
+	return items, nil
+}
+
+// setEndDate sets the last day of a template; an empty date removes it.
+func (s *RecurringOrderService) setEndDate(ro *domain.RecurringOrder, date string) error {
+	if date == "" {
+		ro.EndDate = nil
+		return nil
+	}
+	end, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return errors.New("invalid end date format, expected YYYY-MM-DD")
+	}
+	if end.Before(ro.StartDate) {
+		return errors.New("end date is before start date")
+	}
+	ro.EndDate = &end
+	return nil
+}
+
+func weekdays(days []int) []time.Weekday {
+	var out []time.Weekday
+	seen := make(map[int]bool)
+	for _, d := range days {
+		if !seen[d] {
+			seen[d] = true
+			out = append(out, time.Weekday(d))
+		}
+	}
+	return out
+}
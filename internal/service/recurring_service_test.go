@@ -0,0 +1,131 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/repository"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMaterializeLeavesSkipsOut(t *testing.T) {
+	day := func(days int) string { return time.Now().AddDate(0, 0, days).Format("2006-01-02") }
+	tests := []struct {
+		name string
+		// skip is the occurrence skipped, before or after orders are created
+		skip         string
+		skipAfter    bool
+		wantCreated  int
+		wantOrdered  []string
+		wantSkipGone bool // no order at all for the skipped occurrence
+	}{
+		{name: "nothing skipped", wantCreated: 3, wantOrdered: []string{day(1), day(2), day(3)}},
+		{name: "skipped ahead", skip: day(2), wantCreated: 2, wantOrdered: []string{day(1), day(3)}, wantSkipGone: true},
+		{name: "skipped once ordered", skip: day(2), skipAfter: true, wantCreated: 3, wantOrdered: []string{day(1), day(3)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			orders := newTestOrders(t, db, domain.OrderCapacity{})
+			orderRepo := repository.NewOrderRepo(db)
+			svc := NewRecurringOrderService(
+				repository.NewRecurringOrderRepo(db), orderRepo, repository.NewClientRepo(db), repository.NewProductRepo(db),
+				orders, orders.tx, orders.audit, 3,
+			)
+			ctx := context.Background()
+			ro, err := svc.Create(ctx, domain.CreateRecurringOrderRequest{
+				ClientID:  "c1",
+				Items:     []domain.CreateOrderItemRequest{{ProductID: "p1", Quantity: 5}},
+				Weekdays:  []int{0, 1, 2, 3, 4, 5, 6},
+				StartDate: day(0),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.skip != "" && !tt.skipAfter {
+				if _, err := svc.Skip(ctx, ro.ID, tt.skip); err != nil {
+					t.Fatal(err)
+				}
+			}
+			report, err := svc.Materialize(ctx, time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Created != tt.wantCreated || len(report.Failures) != 0 {
+				t.Fatalf("created %d orders with failures %+v, want %d", report.Created, report.Failures, tt.wantCreated)
+			}
+			if tt.skip != "" && tt.skipAfter {
+				if _, err := svc.Skip(ctx, ro.ID, tt.skip); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Another run orders nothing new, skipped or not
+			if report, err = svc.Materialize(ctx, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			if report.Created != 0 {
+				t.Fatalf("second run created %d orders, want 0", report.Created)
+			}
+
+			var ordered []string
+			for _, d := range []string{day(1), day(2), day(3)} {
+				o, err := orderRepo.FindByOccurrence(ctx, ro.ID, d)
+				if err != nil {
+					t.Fatal(err)
+				}
+				switch {
+				case o != nil && o.Status != domain.OrderStatusAnnulee:
+					ordered = append(ordered, d)
+				case d == tt.skip && (o == nil) != tt.wantSkipGone:
+					t.Errorf("skipped occurrence has order %+v", o)
+				}
+			}
+			if len(ordered) != len(tt.wantOrdered) {
+				t.Fatalf("ordered %v, want %v", ordered, tt.wantOrdered)
+			}
+			for i := range ordered {
+				if ordered[i] != tt.wantOrdered[i] {
+					t.Fatalf("ordered %v, want %v", ordered, tt.wantOrdered)
+				}
+			}
+		})
+	}
+}
+
+func TestOneOrderPerOccurrence(t *testing.T) {
+	tests := []struct {
+		name      string
+		recurring string
+		pickup    string
+		wantErr   bool
+	}{
+		{name: "same template, same day", recurring: "r1", pickup: "2026-03-10 09:30:00", wantErr: true},
+		{name: "same template, next day", recurring: "r1", pickup: "2026-03-11 00:00:00"},
+		{name: "other template, same day", recurring: "r2", pickup: "2026-03-10 00:00:00"},
+		{name: "no template, same day", recurring: "", pickup: "2026-03-10 00:00:00"},
+	}
+
+	insert := `INSERT INTO orders (id, client_id, client_name, client_phone, pickup_date, notes, status, recurring_order_id, created_at)
+		VALUES (?, 'c1', 'Karim', '', ?, '', 'en_attente', ?, '2026-03-01 10:00:00')`
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			newTestSales(t, db, nil)
+			first := "r1"
+			if tt.recurring == "" {
+				first = ""
+			}
+			if _, err := db.Exec(insert, "o1", "2026-03-10 00:00:00", first); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := db.Exec(insert, "o2", tt.pickup, tt.recurring)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("second order: error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
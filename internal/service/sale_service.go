@@ -5,6 +5,7 @@ import (
 	"boucherie-api/internal/port"
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +17,13 @@ type SaleService struct {
 	productRepo port.ProductRepository
 	clientRepo  port.ClientRepository
 	creditRepo  port.CreditRepository
+	refundRepo  port.RefundRepository
+	tx          port.Transactor
+	audit       *AuditService
+	terms       domain.PaymentTerms
+	limits      domain.CreditLimits
+	users       *UserService
+	stock       *StockService
 }
 
 // NewSaleService creates a new sale service.
@@ -24,35 +32,122 @@ func NewSaleService(
 	productRepo port.ProductRepository,
 	clientRepo port.ClientRepository,
 	creditRepo port.CreditRepository,
+	refundRepo port.RefundRepository,
+	tx port.Transactor,
+	audit *AuditService,
+	terms domain.PaymentTerms,
+	limits domain.CreditLimits,
+	users *UserService,
+	stock *StockService,
 ) *SaleService {
 	return &SaleService{
 		saleRepo:    saleRepo,
 		productRepo: productRepo,
 		clientRepo:  clientRepo,
 		creditRepo:  creditRepo,
+		refundRepo:  refundRepo,
+		tx:          tx,
+		audit:       audit,
+		terms:       terms,
+		limits:      limits,
+		users:       users,
+		stock:       stock,
 	}
 }
 
-// List returns sales with optional filters.
+// List returns sales with optional filters. date is a YYYY-MM-DD day of the
+// shop, not a UTC one.
 func (s *SaleService) List(ctx context.Context, clientID *string, date *string) ([]domain.Sale, error) {
-	return s.saleRepo.FindAll(ctx, clientID, date)
+	var day *time.Time
+	if date != nil {
+		d, err := time.ParseInLocation("2006-01-02", *date, time.Local)
+		if err != nil {
+			return nil, errors.New("invalid date format, expected YYYY-MM-DD")
+		}
+		day = &d
+	}
+	return s.saleRepo.FindAll(ctx, clientID, day)
+}
+
+// Get returns a single sale by ID.
+func (s *SaleService) Get(ctx context.Context, id string) (*domain.Sale, error) {
+	sale, err := s.saleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sale == nil {
+		return nil, errors.New("sale not found")
+	}
+	return sale, nil
+}
+
+// Lots returns the lots the lines of a sale were taken from.
+func (s *SaleService) Lots(ctx context.Context, id string) ([]domain.SaleLot, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.stock.SaleLots(ctx, id)
+}
+
+// Receipt returns the data printed on a sale ticket.
+func (s *SaleService) Receipt(ctx context.Context, id string) (*domain.Receipt, error) {
+	sale, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.clientRepo.FindByID(ctx, sale.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	rc := &domain.Receipt{Sale: *sale}
+	switch {
+	case sale.ClientBalance != nil:
+		rc.ClientBalance = *sale.ClientBalance
+	case client != nil:
+		// Older sales did not keep the balance, today's is the best we have
+		rc.ClientBalance = client.TotalCredit
+	}
+	return rc, nil
 }
 
 // Create registers a new sale: calculates totals, creates credit if needed, updates client balance.
+// The sale, its credit and the client balance change are committed together.
 func (s *SaleService) Create(ctx context.Context, req domain.CreateSaleRequest) (*domain.Sale, error) {
+	var sale *domain.Sale
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		sale, err = s.create(ctx, req, saleOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sale, nil
+}
+
+// saleOptions carries what other services add to a sale they record.
+type saleOptions struct {
+	orderID string // order being picked up
+	// prices are the prices per kg quoted for req.Items, by index. A zero
+	// price falls back to the product's current price.
+	prices  []domain.Money
+	deposit domain.Money // held against the order, deducted from the total
+}
+
+func (s *SaleService) create(ctx context.Context, req domain.CreateSaleRequest, opts saleOptions) (*domain.Sale, error) {
 	// Verify client exists
 	var client *domain.Client
 	var err error
 
-	if req.ClientID == "anonymous" {
-		client, err = s.clientRepo.FindByID(ctx, "anonymous")
+	if req.ClientID == domain.AnonymousClientID {
+		client, err = s.clientRepo.FindByID(ctx, domain.AnonymousClientID)
 		if err != nil {
 			return nil, err
 		}
 		if client == nil {
 			// Auto-create anonymous client
 			client = &domain.Client{
-				ID:          "anonymous",
+				ID:          domain.AnonymousClientID,
 				Name:        "Client de passage",
 				Phone:       "",
 				TotalCredit: 0,
@@ -61,6 +156,9 @@ func (s *SaleService) Create(ctx context.Context, req domain.CreateSaleRequest)
 			if err := s.clientRepo.Create(ctx, client); err != nil {
 				return nil, err
 			}
+			if err := s.audit.record(ctx, domain.AuditClient, client.ID, domain.AuditCreate, nil, client); err != nil {
+				return nil, err
+			}
 		}
 	} else {
 		client, err = s.clientRepo.FindByID(ctx, req.ClientID)
@@ -74,9 +172,9 @@ func (s *SaleService) Create(ctx context.Context, req domain.CreateSaleRequest)
 
 	// Build sale items, calculate total
 	var items []domain.SaleItem
-	var total float64
+	var total domain.Money
 
-	for _, ri := range req.Items {
+	for i, ri := range req.Items {
 		product, err := s.productRepo.FindByID(ctx, ri.ProductID)
 		if err != nil {
 			return nil, err
@@ -85,42 +183,91 @@ func (s *SaleService) Create(ctx context.Context, req domain.CreateSaleRequest)
 			return nil, errors.New("product not found: " + ri.ProductID)
 		}
 
-		subtotal := product.PricePerKg * ri.Quantity
+		price := product.PricePerKg
+		if i < len(opts.prices) && opts.prices[i] > 0 {
+			price = opts.prices[i]
+		}
+		subtotal := price.MulKg(ri.Quantity)
 		items = append(items, domain.SaleItem{
 			ID:          uuid.New().String(),
 			ProductID:   product.ID,
 			ProductName: product.Name,
 			Quantity:    ri.Quantity,
+			PricePerKg:  price,
 			Subtotal:    subtotal,
 		})
 		total += subtotal
 	}
 
-	// Validate paid amount
-	if req.PaidAmount > total {
+	// Validate paid amount, what a deposit already covers is not due
+	deposit := opts.deposit.Min(total)
+	if req.PaidAmount > total-deposit {
+		if deposit > 0 {
+			return nil, errors.New("paid amount exceeds total less deposit")
+		}
 		return nil, errors.New("paid amount exceeds total")
 	}
 
-	creditAmount := total - req.PaidAmount
+	creditAmount := total - deposit - req.PaidAmount
+	overrideBy, err := s.checkCreditLimit(ctx, client, creditAmount, req.OverrideToken)
+	if err != nil {
+		return nil, err
+	}
+	userID, userName := actor(ctx)
+	balance := client.TotalCredit + creditAmount
+	method := req.Method
+	if method == "" {
+		method = domain.PaymentCash
+	}
 
 	sale := &domain.Sale{
-		ID:           uuid.New().String(),
-		ClientID:     client.ID,
-		ClientName:   client.Name,
-		Items:        items,
-		Total:        total,
-		PaidAmount:   req.PaidAmount,
-		CreditAmount: creditAmount,
-		Date:         time.Now(),
+		ID:            uuid.New().String(),
+		ClientID:      client.ID,
+		ClientName:    client.Name,
+		Items:         items,
+		Total:         total,
+		PaidAmount:    req.PaidAmount,
+		DepositAmount: deposit,
+		CreditAmount:  creditAmount,
+		PaymentMethod: method,
+		Status:        domain.SaleStatusValidee,
+		UserID:        userID,
+		UserName:      userName,
+
+		CreditOverrideBy: overrideBy,
+		OrderID:          opts.orderID,
+		ClientBalance:    &balance,
+		Date:             time.Now(),
+	}
+
+	// A pickup takes what its order reserved, so only counter sales are
+	// checked against the stock left for them.
+	if opts.orderID == "" {
+		if sale.Warnings, err = s.stock.saleWarnings(ctx, sale.Items); err != nil {
+			return nil, err
+		}
 	}
 
-	// Persist sale
+	// Persist sale, then take its lines out of stock and their lots
 	if err := s.saleRepo.Create(ctx, sale); err != nil {
 		return nil, err
 	}
+	for i := range sale.Items {
+		item := &sale.Items[i]
+		if err := s.stock.record(ctx, newStockMovement(ctx, item.ProductID, domain.StockVente, -item.Quantity, sale.ID)); err != nil {
+			return nil, err
+		}
+		if err := s.stock.takeLots(ctx, item, req.Items[i].LotID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.audit.record(ctx, domain.AuditSale, sale.ID, domain.AuditCreate, nil, sale); err != nil {
+		return nil, err
+	}
 
 	// If there's credit, create a credit record and update client balance
 	if creditAmount > 0 {
+		dueDate := s.terms.DueDate(client, sale.Date)
 		credit := &domain.Credit{
 			ID:              uuid.New().String(),
 			ClientID:        client.ID,
@@ -129,16 +276,244 @@ func (s *SaleService) Create(ctx context.Context, req domain.CreateSaleRequest)
 			Amount:          creditAmount,
 			RemainingAmount: creditAmount,
 			Status:          domain.CreditStatusEnCours,
-			CreatedAt:       time.Now(),
+			CreatedAt:       sale.Date,
+			DueDate:         &dueDate,
 			Payments:        []domain.Payment{},
 		}
 		if err := s.creditRepo.Create(ctx, credit); err != nil {
 			return nil, err
 		}
-		if err := s.clientRepo.UpdateTotalCredit(ctx, client.ID, creditAmount); err != nil {
+		if err := s.audit.record(ctx, domain.AuditCredit, credit.ID, domain.AuditCreate, nil, credit); err != nil {
+			return nil, err
+		}
+		if err := adjustBalance(ctx, s.clientRepo, s.audit, client.ID, creditAmount); err != nil {
 			return nil, err
 		}
 	}
 
 	return sale, nil
 }
+
+// checkCreditLimit rejects putting amount on the client's account when it
+// would exceed their credit limit, unless overrideToken belongs to the owner.
+// It returns the ID of the owner who allowed the overrun, if any.
+func (s *SaleService) checkCreditLimit(ctx context.Context, client *domain.Client, amount domain.Money, overrideToken string) (string, error) {
+	if amount <= 0 {
+		return "", nil
+	}
+	limit, ok := s.limits.Limit(client)
+	if !ok || client.TotalCredit+amount <= limit {
+		return "", nil
+	}
+
+	overrideAllowed := client.ID != domain.AnonymousClientID
+	if overrideAllowed && overrideToken != "" {
+		owner, err := s.users.Authenticate(ctx, overrideToken)
+		if err != nil {
+			return "", err
+		}
+		if owner.Role != domain.RoleOwner {
+			return "", fmt.Errorf("%w: only the owner can allow credit over the limit", ErrForbidden)
+		}
+		return owner.ID, nil
+	}
+
+	available := limit - client.TotalCredit
+	if available < 0 {
+		available = 0
+	}
+	return "", &CreditLimitError{
+		ClientID:        client.ID,
+		ClientName:      client.Name,
+		Limit:           limit,
+		Balance:         client.TotalCredit,
+		Requested:       amount,
+		Available:       available,
+		OverrideAllowed: overrideAllowed,
+	}
+}
+
+// ListRefunds returns the refunds recorded against a sale.
+func (s *SaleService) ListRefunds(ctx context.Context, saleID string) ([]domain.Refund, error) {
+	sale, err := s.saleRepo.FindByID(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	if sale == nil {
+		return nil, errors.New("sale not found")
+	}
+	return s.refundRepo.FindBySaleID(ctx, saleID)
+}
+
+// Cancel voids a whole sale: every quantity not yet refunded is returned,
+// the linked credit is reduced accordingly and the client balance reversed.
+func (s *SaleService) Cancel(ctx context.Context, saleID string, req domain.CancelSaleRequest) (*domain.Refund, error) {
+	var refund *domain.Refund
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		refund, err = s.refund(ctx, saleID, domain.RefundKindAnnulation, nil, req.Method, req.Reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// Refund returns part of a sale, line by line. The refunded amount first
+// reduces what is still owed on the sale's credit; the rest is paid back.
+func (s *SaleService) Refund(ctx context.Context, saleID string, req domain.CreateRefundRequest) (*domain.Refund, error) {
+	var refund *domain.Refund
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		refund, err = s.refund(ctx, saleID, domain.RefundKindRemboursement, req.Items, req.Method, req.Reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// refund records a refund document for the given lines. A nil lines slice
+// refunds everything still refundable on the sale.
+func (s *SaleService) refund(
+	ctx context.Context,
+	saleID string,
+	kind domain.RefundKind,
+	lines []domain.CreateRefundItemRequest,
+	method domain.PaymentMethod,
+	reason string,
+) (*domain.Refund, error) {
+	sale, err := s.saleRepo.FindByID(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	if sale == nil {
+		return nil, errors.New("sale not found")
+	}
+	if sale.Status == domain.SaleStatusAnnulee {
+		return nil, errors.New("sale already cancelled")
+	}
+
+	previous, err := s.refundRepo.FindBySaleID(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	refundedQty := make(map[string]float64)
+	refundedAmount := make(map[string]domain.Money)
+	for _, rf := range previous {
+		for _, item := range rf.Items {
+			refundedQty[item.SaleItemID] += item.Quantity
+			refundedAmount[item.SaleItemID] += item.Amount
+		}
+	}
+
+	if lines == nil {
+		for _, item := range sale.Items {
+			if remaining := item.Quantity - refundedQty[item.ID]; remaining > quantityEpsilon {
+				lines = append(lines, domain.CreateRefundItemRequest{SaleItemID: item.ID, Quantity: remaining})
+			}
+		}
+		if len(lines) == 0 {
+			return nil, errors.New("sale already fully refunded")
+		}
+	}
+
+	refund := &domain.Refund{
+		ID:       uuid.New().String(),
+		SaleID:   sale.ID,
+		ClientID: sale.ClientID,
+		Kind:     kind,
+		Method:   method,
+		Reason:   reason,
+		Date:     time.Now(),
+	}
+	if refund.Method == "" {
+		refund.Method = domain.PaymentCash
+	}
+
+	for _, line := range lines {
+		item := findSaleItem(sale.Items, line.SaleItemID)
+		if item == nil {
+			return nil, errors.New("sale item not found: " + line.SaleItemID)
+		}
+		remaining := item.Quantity - refundedQty[item.ID]
+		if line.Quantity > remaining+quantityEpsilon {
+			return nil, errors.New("refund quantity exceeds remaining quantity for " + item.ProductName)
+		}
+
+		// Refunding the whole remainder of a line returns exactly what is left
+		// of its subtotal, so rounding never leaves centimes behind.
+		amount := item.Subtotal.Prorate(domain.Grams(line.Quantity), domain.Grams(item.Quantity))
+		if remaining-line.Quantity <= quantityEpsilon {
+			amount = item.Subtotal - refundedAmount[item.ID]
+		}
+
+		refundedQty[item.ID] += line.Quantity
+		refundedAmount[item.ID] += amount
+		refund.Items = append(refund.Items, domain.RefundItem{
+			ID:          uuid.New().String(),
+			RefundID:    refund.ID,
+			SaleItemID:  item.ID,
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    line.Quantity,
+			Amount:      amount,
+		})
+		refund.Total += amount
+	}
+
+	// Reduce or void the linked credit before paying anything back
+	credit, err := s.creditRepo.FindBySaleID(ctx, sale.ID)
+	if err != nil {
+		return nil, err
+	}
+	if credit != nil && credit.RemainingAmount > 0 {
+		before := *credit
+		refund.CreditAmount = refund.Total.Min(credit.RemainingAmount)
+		credit.RemainingAmount -= refund.CreditAmount
+		if credit.RemainingAmount <= 0 {
+			credit.RemainingAmount = 0
+			credit.Status = domain.CreditStatusPaye
+		}
+		if err := s.creditRepo.Update(ctx, credit); err != nil {
+			return nil, err
+		}
+		if err := s.audit.record(ctx, domain.AuditCredit, credit.ID, domain.AuditUpdate, before, credit); err != nil {
+			return nil, err
+		}
+		if err := adjustBalance(ctx, s.clientRepo, s.audit, credit.ClientID, -refund.CreditAmount); err != nil {
+			return nil, err
+		}
+	}
+	refund.CashAmount = refund.Total - refund.CreditAmount
+
+	if err := s.refundRepo.Create(ctx, refund); err != nil {
+		return nil, err
+	}
+	if err := s.audit.record(ctx, domain.AuditRefund, refund.ID, domain.AuditCreate, nil, refund); err != nil {
+		return nil, err
+	}
+	for _, item := range refund.Items {
+		if err := s.stock.record(ctx, newStockMovement(ctx, item.ProductID, domain.StockRemboursement, item.Quantity, refund.ID)); err != nil {
+			return nil, err
+		}
+		if err := s.stock.returnLots(ctx, item.SaleItemID, item.Quantity); err != nil {
+			return nil, err
+		}
+	}
+	return refund, nil
+}
+
+// quantityEpsilon absorbs float noise when comparing weights in kg.
+const quantityEpsilon = 1e-6
+
+func findSaleItem(items []domain.SaleItem, id string) *domain.SaleItem {
+	for i := range items {
+		if items[i].ID == id {
+			return &items[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/migrate"
+	"boucherie-api/internal/port"
+	"boucherie-api/internal/repository"
+	"boucherie-api/migrations"
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// openTestDB returns a migrated database in a temporary file, opened the way
+// the server opens its own.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "boucherie.db") +
+		"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate&_time_format=sqlite"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// failingClients makes balance changes fail, the last step of a credit sale.
+type failingClients struct {
+	port.ClientRepository
+}
+
+func (failingClients) UpdateTotalCredit(context.Context, string, domain.Money) error {
+	return errors.New("disk full")
+}
+
+// newTestSales returns a sale service over db, with clients standing in for
+// the client repository when set, and a client and a product at 20,00 €/kg.
+func newTestSales(t *testing.T, db *sql.DB, clients port.ClientRepository) *SaleService {
+	t.Helper()
+	ctx := context.Background()
+	clientRepo := repository.NewClientRepo(db)
+	productRepo := repository.NewProductRepo(db)
+	if err := clientRepo.Create(ctx, &domain.Client{ID: "c1", Name: "Karim", Phone: "0600000000", CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := productRepo.Create(ctx, &domain.Product{ID: "p1", Name: "Entrecôte", Category: domain.CategoryBoeuf, PricePerKg: 2000, InStock: true}); err != nil {
+		t.Fatal(err)
+	}
+	if clients == nil {
+		clients = clientRepo
+	}
+
+	tx := repository.NewTxManager(db)
+	audit := NewAuditService(repository.NewAuditRepo(db))
+	stock := NewStockService(repository.NewStockRepo(db), repository.NewLotRepo(db), productRepo, tx, audit)
+	return NewSaleService(
+		repository.NewSaleRepo(db), productRepo, clients, repository.NewCreditRepo(db), repository.NewRefundRepo(db),
+		tx, audit, domain.PaymentTerms{DefaultDays: 30}, domain.CreditLimits{}, nil, stock,
+	)
+}
+
+// count returns the number of rows of table.
+func count(t *testing.T, db *sql.DB, table string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func balance(t *testing.T, db *sql.DB, clientID string) domain.Money {
+	t.Helper()
+	var m domain.Money
+	if err := db.QueryRow(`SELECT total_credit FROM clients WHERE id = ?`, clientID).Scan(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// sell records 2 kg at 20,00 €/kg, paid, the rest on credit.
+func sell(t *testing.T, svc *SaleService, paid domain.Money) *domain.Sale {
+	t.Helper()
+	sale, err := svc.Create(context.Background(), domain.CreateSaleRequest{
+		ClientID:   "c1",
+		Items:      []domain.CreateSaleItemRequest{{ProductID: "p1", Quantity: 2}},
+		PaidAmount: paid,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sale
+}
+
+func TestCreateSaleRollsBackOnFailure(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestSales(t, db, failingClients{repository.NewClientRepo(db)})
+
+	_, err := svc.Create(context.Background(), domain.CreateSaleRequest{
+		ClientID:   "c1",
+		Items:      []domain.CreateSaleItemRequest{{ProductID: "p1", Quantity: 2}},
+		PaidAmount: 1000,
+	})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("error = %v, want the balance update failure", err)
+	}
+
+	for _, table := range []string{"sales", "sale_items", "credits", "stock_movements"} {
+		if n := count(t, db, table); n != 0 {
+			t.Errorf("%s has %d rows after the rollback, want 0", table, n)
+		}
+	}
+	if b := balance(t, db, "c1"); b != 0 {
+		t.Errorf("total_credit = %s after the rollback, want 0", b)
+	}
+}
+
+func TestRefundReducesCreditFirst(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestSales(t, db, nil)
+	ctx := context.Background()
+
+	sale := sell(t, svc, 3000)
+	if sale.CreditAmount != 1000 || balance(t, db, "c1") != 1000 {
+		t.Fatalf("credit = %s, balance = %s, want 10,00 € each", sale.CreditAmount, balance(t, db, "c1"))
+	}
+
+	refund, err := svc.Refund(ctx, sale.ID, domain.CreateRefundRequest{
+		Items: []domain.CreateRefundItemRequest{{SaleItemID: sale.Items[0].ID, Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refund.Total != 2000 || refund.CreditAmount != 1000 || refund.CashAmount != 1000 {
+		t.Fatalf("refund total %s = %s credit + %s cash, want 20,00 = 10,00 + 10,00",
+			refund.Total, refund.CreditAmount, refund.CashAmount)
+	}
+
+	credit, err := repository.NewCreditRepo(db).FindBySaleID(ctx, sale.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credit.RemainingAmount != 0 || credit.Status != domain.CreditStatusPaye {
+		t.Fatalf("credit has %s left with status %s, want 0 and %s", credit.RemainingAmount, credit.Status, domain.CreditStatusPaye)
+	}
+	if b := balance(t, db, "c1"); b != 0 {
+		t.Fatalf("total_credit = %s, want 0", b)
+	}
+}
+
+func TestRefundRejectsMoreThanSold(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestSales(t, db, nil)
+	ctx := context.Background()
+
+	sale := sell(t, svc, 0)
+	line := sale.Items[0].ID
+	if _, err := svc.Refund(ctx, sale.ID, domain.CreateRefundRequest{
+		Items: []domain.CreateRefundItemRequest{{SaleItemID: line, Quantity: 1.5}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := svc.Refund(ctx, sale.ID, domain.CreateRefundRequest{
+		Items: []domain.CreateRefundItemRequest{{SaleItemID: line, Quantity: 1}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "exceeds remaining quantity") {
+		t.Fatalf("error = %v, want the refund over the sold quantity rejected", err)
+	}
+	if n := count(t, db, "refunds"); n != 1 {
+		t.Fatalf("%d refunds recorded, want 1", n)
+	}
+	// 2 kg on credit, 1,5 kg of it refunded
+	if b := balance(t, db, "c1"); b != 1000 {
+		t.Fatalf("total_credit = %s, want 10,00 €", b)
+	}
+}
+
+func TestCancelReturnsWhatIsLeft(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestSales(t, db, nil)
+	ctx := context.Background()
+
+	sale := sell(t, svc, 1000)
+	if _, err := svc.Refund(ctx, sale.ID, domain.CreateRefundRequest{
+		Items: []domain.CreateRefundItemRequest{{SaleItemID: sale.Items[0].ID, Quantity: 0.5}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	refund, err := svc.Cancel(ctx, sale.ID, domain.CancelSaleRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refund.Items) != 1 || refund.Items[0].Quantity != 1.5 {
+		t.Fatalf("cancellation returned %+v, want the 1,5 kg left", refund.Items)
+	}
+	if refund.Total != 3000 || refund.CreditAmount != 2000 || refund.CashAmount != 1000 {
+		t.Fatalf("cancellation total %s = %s credit + %s cash, want 30,00 = 20,00 + 10,00",
+			refund.Total, refund.CreditAmount, refund.CashAmount)
+	}
+	if b := balance(t, db, "c1"); b != 0 {
+		t.Fatalf("total_credit = %s, want 0", b)
+	}
+
+	got, err := svc.Get(ctx, sale.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != domain.SaleStatusAnnulee {
+		t.Fatalf("sale status = %s, want %s", got.Status, domain.SaleStatusAnnulee)
+	}
+	if _, err := svc.Cancel(ctx, sale.ID, domain.CancelSaleRequest{}); err == nil {
+		t.Fatal("cancelling twice succeeded")
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/port"
+	"boucherie-api/internal/receipt"
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// StatementService builds client account statements.
+type StatementService struct {
+	clientRepo    port.ClientRepository
+	statementRepo port.StatementRepository
+}
+
+// NewStatementService creates a new statement service.
+func NewStatementService(clientRepo port.ClientRepository, statementRepo port.StatementRepository) *StatementService {
+	return &StatementService{clientRepo: clientRepo, statementRepo: statementRepo}
+}
+
+// statementKindOrder lists movements of the same instant in the order they
+// happen: a sale's credit before anything settling it.
+var statementKindOrder = map[domain.StatementEntryKind]int{
+	domain.StatementSale:    0,
+	domain.StatementRefund:  1,
+	domain.StatementPayment: 2,
+}
+
+// Statement returns the ledger of a client's account between from and to
+// (inclusive YYYY-MM-DD dates, defaulting to the current month). Balances are
+// recomputed from sales, payments and refunds, not read from the client.
+func (s *StatementService) Statement(ctx context.Context, clientID, from, to string) (*domain.Statement, error) {
+	client, err := s.clientRepo.FindByID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, errors.New("client not found")
+	}
+
+	now := time.Now()
+	if to == "" {
+		to = now.Format("2006-01-02")
+	}
+	// Statement days are the shop's days, not UTC ones
+	toDate, err := time.ParseInLocation("2006-01-02", to, time.Local)
+	if err != nil {
+		return nil, errors.New("invalid date format, expected YYYY-MM-DD")
+	}
+	if from == "" {
+		from = toDate.AddDate(0, 0, 1-toDate.Day()).Format("2006-01-02")
+	}
+	fromDate, err := time.ParseInLocation("2006-01-02", from, time.Local)
+	if err != nil {
+		return nil, errors.New("invalid date format, expected YYYY-MM-DD")
+	}
+	if from > to {
+		return nil, errors.New("from must not be after to")
+	}
+
+	entries, err := s.statementRepo.FindEntries(ctx, clientID, toDate.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return statementKindOrder[a.Kind] < statementKindOrder[b.Kind]
+	})
+
+	st := &domain.Statement{
+		ClientID:    client.ID,
+		ClientName:  client.Name,
+		ClientPhone: client.Phone,
+		From:        from,
+		To:          to,
+		Entries:     []domain.StatementEntry{},
+		GeneratedAt: now,
+	}
+	balance := domain.Money(0)
+	for _, e := range entries {
+		balance += e.Debit - e.Credit
+		if e.Date.Before(fromDate) {
+			st.OpeningBalance = balance
+			continue
+		}
+		e.Balance = balance
+		e.Description = statementDescription(e)
+		st.TotalDebit += e.Debit
+		st.TotalCredit += e.Credit
+		st.Entries = append(st.Entries, e)
+	}
+	st.ClosingBalance = st.OpeningBalance + st.TotalDebit - st.TotalCredit
+	return st, nil
+}
+
+func statementDescription(e domain.StatementEntry) string {
+	switch e.Kind {
+	case domain.StatementSale:
+		return "Vente à crédit " + receipt.ShortID(e.SaleID)
+	case domain.StatementPayment:
+		return "Paiement (" + string(e.Method) + ")"
+	case domain.StatementRefund:
+		return "Remboursement vente " + receipt.ShortID(e.SaleID)
+	default:
+		return string(e.Kind)
+	}
+}
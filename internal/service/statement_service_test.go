@@ -0,0 +1,130 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/repository"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStatementAroundMidnight(t *testing.T) {
+	// East of UTC, the first minutes of a local day are still the day before in UTC
+	local := time.Local
+	time.Local = time.FixedZone("UTC+1", 3600)
+	t.Cleanup(func() { time.Local = local })
+	at := func(s string) time.Time {
+		d, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	db := openTestDB(t)
+	sales := newTestSales(t, db, nil)
+	credits := NewCreditService(repository.NewCreditRepo(db), repository.NewClientRepo(db), sales.tx, sales.audit, domain.PaymentTerms{DefaultDays: 30}, 0)
+	svc := NewStatementService(repository.NewClientRepo(db), repository.NewStatementRepo(db))
+	ctx := context.Background()
+
+	backdate := func(sale *domain.Sale, when string) {
+		t.Helper()
+		if _, err := db.Exec(`UPDATE sales SET date = ? WHERE id = ?`, at(when), sale.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`UPDATE credits SET created_at = ? WHERE sale_id = ?`, at(when), sale.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 40,00 € owed before the statement starts
+	before := sell(t, sales, 0)
+	backdate(before, "2026-03-08 23:59")
+	// 20,00 € on credit in the first minute of the first day
+	first := sell(t, sales, 2000)
+	backdate(first, "2026-03-09 00:01")
+	// 10,00 € paid on account
+	receipt, err := credits.PayOnAccount(ctx, "c1", domain.CreateClientPaymentRequest{Amount: 1000, Method: domain.PaymentCash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`UPDATE payments SET date = ? WHERE receipt_id = ?`, at("2026-03-10 12:00"), receipt.ReceiptID); err != nil {
+		t.Fatal(err)
+	}
+	// 0,5 kg brought back in the last minute of the last day, 10,00 € off the credit
+	refund, err := sales.Refund(ctx, first.ID, domain.CreateRefundRequest{
+		Items: []domain.CreateRefundItemRequest{{SaleItemID: first.Items[0].ID, Quantity: 0.5}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`UPDATE refunds SET date = ? WHERE id = ?`, at("2026-03-11 23:59"), refund.ID); err != nil {
+		t.Fatal(err)
+	}
+	// 30,00 € on credit in the first minute after the statement ends
+	after := sell(t, sales, 1000)
+	backdate(after, "2026-03-12 00:01")
+
+	tests := []struct {
+		name     string
+		from, to string
+		opening  domain.Money
+		balances []domain.Money // running balance after each entry
+		kinds    []domain.StatementEntryKind
+		closing  domain.Money
+	}{
+		{
+			name: "first to last day", from: "2026-03-09", to: "2026-03-11",
+			opening:  4000,
+			kinds:    []domain.StatementEntryKind{domain.StatementSale, domain.StatementPayment, domain.StatementRefund},
+			balances: []domain.Money{6000, 5000, 4000},
+			closing:  4000,
+		},
+		{
+			name: "the day after", from: "2026-03-12", to: "2026-03-12",
+			opening:  4000,
+			kinds:    []domain.StatementEntryKind{domain.StatementSale},
+			balances: []domain.Money{7000},
+			closing:  7000,
+		},
+		{
+			name: "the day before", from: "2026-03-08", to: "2026-03-08",
+			kinds:    []domain.StatementEntryKind{domain.StatementSale},
+			balances: []domain.Money{4000},
+			closing:  4000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := svc.Statement(ctx, "c1", tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if st.OpeningBalance != tt.opening || st.ClosingBalance != tt.closing {
+				t.Fatalf("balances %s to %s, want %s to %s", st.OpeningBalance, st.ClosingBalance, tt.opening, tt.closing)
+			}
+			if len(st.Entries) != len(tt.kinds) {
+				t.Fatalf("%d entries, want %d: %+v", len(st.Entries), len(tt.kinds), st.Entries)
+			}
+			for i, e := range st.Entries {
+				if e.Kind != tt.kinds[i] || e.Balance != tt.balances[i] {
+					t.Errorf("entry %d is a %s leaving %s, want a %s leaving %s", i, e.Kind, e.Balance, tt.kinds[i], tt.balances[i])
+				}
+			}
+		})
+	}
+
+	// Once every movement is in, the statement closes on what the credits still owe
+	st, err := svc.Statement(ctx, "c1", "2026-03-01", "2026-03-31")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var remaining domain.Money
+	if err := db.QueryRow(`SELECT SUM(remaining_amount) FROM credits WHERE client_id = 'c1'`).Scan(&remaining); err != nil {
+		t.Fatal(err)
+	}
+	if st.ClosingBalance != remaining || remaining != balance(t, db, "c1") {
+		t.Fatalf("closing balance %s, credits owe %s, total_credit %s, want them equal", st.ClosingBalance, remaining, balance(t, db, "c1"))
+	}
+}
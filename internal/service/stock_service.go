@@ -0,0 +1,379 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/port"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockService keeps the stock ledger and the products' InStock flag in line
+// with it, and traces the meat in stock back to its lots.
+type StockService struct {
+	repo        port.StockRepository
+	lots        port.LotRepository
+	productRepo port.ProductRepository
+	tx          port.Transactor
+	audit       *AuditService
+}
+
+// NewStockService creates a new stock service.
+func NewStockService(repo port.StockRepository, lots port.LotRepository, productRepo port.ProductRepository, tx port.Transactor, audit *AuditService) *StockService {
+	return &StockService{repo: repo, lots: lots, productRepo: productRepo, tx: tx, audit: audit}
+}
+
+// Inventory returns the current stock of every product.
+func (s *StockService) Inventory(ctx context.Context) ([]domain.StockLevel, error) {
+	return s.repo.Levels(ctx)
+}
+
+// Product returns the stock of a product with its movements, optionally
+// between from and to (YYYY-MM-DD).
+func (s *StockService) Product(ctx context.Context, id string, from, to *string) (*domain.StockLevel, error) {
+	for _, d := range []*string{from, to} {
+		if d == nil {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", *d); err != nil {
+			return nil, errors.New("invalid date format, expected YYYY-MM-DD")
+		}
+	}
+	level, err := s.repo.Level(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if level == nil {
+		return nil, errors.New("product not found")
+	}
+	if level.Movements, err = s.repo.FindByProduct(ctx, id, from, to); err != nil {
+		return nil, err
+	}
+	return level, nil
+}
+
+// Record books stock received, wasted, counted or transferred by hand.
+func (s *StockService) Record(ctx context.Context, productID string, req domain.CreateStockMovementRequest) (*domain.StockMovement, error) {
+	qty := req.Quantity
+	switch req.Kind {
+	case domain.StockReception:
+		qty = math.Abs(qty)
+	case domain.StockPerte:
+		qty = -math.Abs(qty)
+	}
+	if req.LotID != "" && qty >= 0 {
+		return nil, errors.New("lotId only applies to stock going out")
+	}
+	m := newStockMovement(ctx, productID, req.Kind, qty, "")
+	m.Note = req.Note
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		product, err := s.productRepo.FindByID(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if product == nil {
+			return errors.New("product not found")
+		}
+		if err := s.record(ctx, m); err != nil {
+			return err
+		}
+		if qty < 0 {
+			if err := s.drawLots(ctx, product.ID, product.Name, -qty, req.LotID); err != nil {
+				return err
+			}
+		}
+		return s.audit.record(ctx, domain.AuditStockMovement, m.ID, domain.AuditCreate, nil, m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// record appends m to the ledger and updates the product's InStock flag: a
+// tracked product is in stock exactly when it has some on hand, whatever
+// movement got it there.
+func (s *StockService) record(ctx context.Context, m *domain.StockMovement) error {
+	if err := s.repo.Create(ctx, m); err != nil {
+		return err
+	}
+	level, err := s.repo.Level(ctx, m.ProductID)
+	if err != nil || level == nil || !level.Tracked {
+		return err
+	}
+
+	inStock := level.OnHand > quantityEpsilon
+	if inStock == level.InStock {
+		return nil
+	}
+	product, err := s.productRepo.FindByID(ctx, m.ProductID)
+	if err != nil || product == nil {
+		return err
+	}
+	before := *product
+	product.InStock = inStock
+	if err := s.productRepo.Update(ctx, product); err != nil {
+		return err
+	}
+	return s.audit.record(ctx, domain.AuditProduct, product.ID, domain.AuditUpdate, before, product)
+}
+
+// newStockMovement builds a movement attributed to the current user.
+func newStockMovement(ctx context.Context, productID string, kind domain.StockMovementKind, qty float64, reference string) *domain.StockMovement {
+	userID, userName := actor(ctx)
+	return &domain.StockMovement{
+		ID:        uuid.New().String(),
+		ProductID: productID,
+		Kind:      kind,
+		Quantity:  qty,
+		Reference: reference,
+		UserID:    userID,
+		UserName:  userName,
+		Date:      time.Now(),
+	}
+}
+
+// Lots returns the lots matching the filter, most recently received first.
+func (s *StockService) Lots(ctx context.Context, f domain.LotFilter) ([]domain.Lot, error) {
+	return s.lots.FindAll(ctx, f)
+}
+
+// Lot returns a single lot.
+func (s *StockService) Lot(ctx context.Context, id string) (*domain.Lot, error) {
+	lot, err := s.lots.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if lot == nil {
+		return nil, errors.New("lot not found")
+	}
+	return lot, nil
+}
+
+// Recall lists every sale, and every client, that received meat from a lot
+// or from the cuts of a carcass lot.
+func (s *StockService) Recall(ctx context.Context, id string) (*domain.LotRecall, error) {
+	lot, err := s.Lot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	derived, err := s.lots.FindDerived(ctx, lot.ID)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{lot.ID}
+	for _, l := range derived {
+		ids = append(ids, l.ID)
+	}
+	sales, err := s.lots.FindSales(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	recall := &domain.LotRecall{Lot: *lot, Derived: derived, Sales: sales, Clients: []domain.RecallClient{}}
+	byClient := make(map[string]int)
+	counted := make(map[string]bool)
+	for _, sale := range sales {
+		recall.Sold += sale.Quantity
+		i, ok := byClient[sale.ClientID]
+		if !ok {
+			i = len(recall.Clients)
+			byClient[sale.ClientID] = i
+			recall.Clients = append(recall.Clients, domain.RecallClient{
+				ClientID:    sale.ClientID,
+				ClientName:  sale.ClientName,
+				ClientPhone: sale.ClientPhone,
+			})
+		}
+		recall.Clients[i].Quantity += sale.Quantity
+		if !counted[sale.SaleID] {
+			counted[sale.SaleID] = true
+			recall.Clients[i].Sales++
+		}
+	}
+	recall.Sold = domain.RoundKg(recall.Sold)
+	for i := range recall.Clients {
+		recall.Clients[i].Quantity = domain.RoundKg(recall.Clients[i].Quantity)
+	}
+	return recall, nil
+}
+
+// SaleLots returns the lots the lines of a sale were taken from.
+func (s *StockService) SaleLots(ctx context.Context, saleID string) ([]domain.SaleLot, error) {
+	return s.lots.FindBySale(ctx, saleID)
+}
+
+// receiveLot records a lot of meat received or cut.
+func (s *StockService) receiveLot(ctx context.Context, lot *domain.Lot) error {
+	if err := s.lots.Create(ctx, lot); err != nil {
+		return err
+	}
+	return s.audit.record(ctx, domain.AuditLot, lot.ID, domain.AuditCreate, nil, lot)
+}
+
+// carcassLot returns the lot a carcass was received with, nil if none.
+func (s *StockService) carcassLot(ctx context.Context, carcassID string) (*domain.Lot, error) {
+	return s.lots.FindByCarcass(ctx, carcassID)
+}
+
+// saleWarnings lists the lines of a sale taking more of a tracked product
+// than is available, i.e. on hand less what confirmed orders reserve. The
+// sale goes through: the meat is on the counter, the ledger may be behind.
+func (s *StockService) saleWarnings(ctx context.Context, items []domain.SaleItem) ([]domain.StockWarning, error) {
+	requested := make(map[string]float64)
+	for _, it := range items {
+		requested[it.ProductID] += it.Quantity
+	}
+	var warnings []domain.StockWarning
+	for _, it := range items {
+		want, ok := requested[it.ProductID]
+		if !ok {
+			continue // product already checked
+		}
+		delete(requested, it.ProductID)
+
+		level, err := s.repo.Level(ctx, it.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if level == nil || !level.Tracked {
+			continue
+		}
+		if want > level.Available+quantityEpsilon {
+			warnings = append(warnings, domain.StockWarning{
+				ProductID:   it.ProductID,
+				ProductName: it.ProductName,
+				Requested:   want,
+				Available:   math.Max(level.Available, 0),
+			})
+		}
+	}
+	return warnings, nil
+}
+
+// deliver takes the lines of an order handed over without a sale out of
+// stock and its lots, at their ordered weights. Orders picked up through
+// checkout leave stock with their sale instead.
+func (s *StockService) deliver(ctx context.Context, order *domain.Order) error {
+	for _, it := range order.Items {
+		if err := s.record(ctx, newStockMovement(ctx, it.ProductID, domain.StockVente, -it.Quantity, order.ID)); err != nil {
+			return err
+		}
+		if err := s.drawLots(ctx, it.ProductID, it.ProductName, it.Quantity, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// takeLots assigns a sale line to the lots it is taken from: lotID when
+// given, otherwise the oldest lots of the product first (FIFO). What no lot
+// covers, e.g. stock received before lots were tracked, stays untraced.
+func (s *StockService) takeLots(ctx context.Context, item *domain.SaleItem, lotID string) error {
+	lots, err := s.openLots(ctx, item.ProductID, item.ProductName, item.Quantity, lotID)
+	if err != nil {
+		return err
+	}
+	return spread(lots, item.Quantity, func(lot domain.Lot, qty float64) error {
+		if err := s.lots.Take(ctx, item.ID, lot.ID, qty); err != nil {
+			return err
+		}
+		item.Lots = append(item.Lots, domain.SaleItemLot{LotID: lot.ID, LotNumber: lot.Number, Quantity: qty})
+		return nil
+	})
+}
+
+// drawLots takes qty kg of a product leaving stock without a sale out of its
+// lots, lotID or the oldest first, like takeLots.
+func (s *StockService) drawLots(ctx context.Context, productID, productName string, qty float64, lotID string) error {
+	lots, err := s.openLots(ctx, productID, productName, qty, lotID)
+	if err != nil {
+		return err
+	}
+	return spread(lots, qty, func(lot domain.Lot, qty float64) error {
+		return s.lots.Draw(ctx, lot.ID, qty)
+	})
+}
+
+// returnLots gives qty kg refunded from a sale line back to the lots it was
+// taken from, the last one taken first. What the line took from no lot is
+// not returned to any.
+func (s *StockService) returnLots(ctx context.Context, saleItemID string, qty float64) error {
+	taken, err := s.lots.FindByItem(ctx, saleItemID)
+	if err != nil {
+		return err
+	}
+	left := qty
+	for i := len(taken) - 1; i >= 0 && left > quantityEpsilon; i-- {
+		back := domain.RoundKg(math.Min(left, taken[i].Quantity))
+		if back <= 0 {
+			continue
+		}
+		if err := s.lots.Return(ctx, saleItemID, taken[i].LotID, back); err != nil {
+			return err
+		}
+		left -= back
+	}
+	return nil
+}
+
+// openLots returns the lots qty kg of a product is taken from: lotID, which
+// must belong to the product and hold enough, or else its open lots oldest
+// first.
+func (s *StockService) openLots(ctx context.Context, productID, productName string, qty float64, lotID string) ([]domain.Lot, error) {
+	if lotID == "" {
+		return s.lots.FindOpen(ctx, productID)
+	}
+	lot, err := s.lots.FindByID(ctx, lotID)
+	if err != nil {
+		return nil, err
+	}
+	if lot == nil || lot.ProductID != productID {
+		return nil, fmt.Errorf("lot %s not found for %s", lotID, productName)
+	}
+	if lot.Remaining < qty-quantityEpsilon {
+		return nil, fmt.Errorf("lot %s has only %.3f kg of %s left", lotID, lot.Remaining, productName)
+	}
+	// Weighing differences below the gram are taken from the lot too
+	lot.Remaining = math.Max(lot.Remaining, qty)
+	return []domain.Lot{*lot}, nil
+}
+
+// spread takes qty kg from lots in order, passing take the part of each lot
+// used. What the lots do not cover is left.
+func spread(lots []domain.Lot, qty float64, take func(lot domain.Lot, qty float64) error) error {
+	left := qty
+	for _, lot := range lots {
+		if left <= quantityEpsilon {
+			break
+		}
+		part := domain.RoundKg(math.Min(left, lot.Remaining))
+		if part <= 0 {
+			continue
+		}
+		if err := take(lot, part); err != nil {
+			return err
+		}
+		left -= part
+	}
+	return nil
+}
+
+// newLot builds a lot of qty kg from the traceability details of req, which
+// may be nil.
+func newLot(req *domain.LotRequest, qty float64, at time.Time) *domain.Lot {
+	lot := &domain.Lot{ID: uuid.New().String(), Quantity: qty, Remaining: qty, ReceivedAt: at}
+	if req != nil {
+		lot.Number = req.Number
+		lot.Origin = req.Origin
+		lot.SlaughterDate = req.SlaughterDate
+		lot.ApprovalNumber = req.ApprovalNumber
+		lot.AnimalID = req.AnimalID
+	}
+	return lot
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/repository"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// newTestReceptions returns a supplier service receiving into the stock of
+// sales, with a carcass service sharing that stock, and a supplier s1.
+func newTestReceptions(t *testing.T, db *sql.DB, sales *SaleService) (*SupplierService, *CarcassService) {
+	t.Helper()
+	supplierRepo := repository.NewSupplierRepo(db)
+	if err := supplierRepo.Create(context.Background(), &domain.Supplier{ID: "s1", Name: "Abattoir du Nord", CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	carcasses := NewCarcassService(repository.NewCarcassRepo(db), sales.productRepo, sales.stock, sales.tx, sales.audit)
+	return NewSupplierService(supplierRepo, repository.NewReceptionRepo(db), sales.productRepo, carcasses, sales.stock, sales.tx, sales.audit), carcasses
+}
+
+func remaining(t *testing.T, stock *StockService, lotID string) float64 {
+	t.Helper()
+	lot, err := stock.Lot(context.Background(), lotID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lot.Remaining
+}
+
+func TestLotsFollowSalesAndRefunds(t *testing.T) {
+	db := openTestDB(t)
+	sales := newTestSales(t, db, nil)
+	suppliers, _ := newTestReceptions(t, db, sales)
+	ctx := context.Background()
+
+	var lots []string
+	for _, r := range []struct {
+		date string
+		kg   float64
+	}{
+		{time.Now().AddDate(0, 0, -1).Format("2006-01-02"), 3},
+		{"", 5},
+	} {
+		rc, err := suppliers.Receive(ctx, "s1", domain.CreateReceptionRequest{
+			Date:  r.date,
+			Items: []domain.ReceptionItemRequest{{ProductID: "p1", Quantity: r.kg, PricePerKg: 1200}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		lots = append(lots, rc.Items[0].LotID)
+	}
+
+	// 4 kg: all 3 kg of the older lot, then 1 kg of the newer one
+	sale, err := sales.Create(ctx, domain.CreateSaleRequest{
+		ClientID:   "c1",
+		Items:      []domain.CreateSaleItemRequest{{ProductID: "p1", Quantity: 4}},
+		PaidAmount: 8000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	taken := sale.Items[0].Lots
+	if len(taken) != 2 || taken[0].LotID != lots[0] || taken[0].Quantity != 3 || taken[1].LotID != lots[1] || taken[1].Quantity != 1 {
+		t.Fatalf("sale took %+v, want 3 kg of %s then 1 kg of %s", taken, lots[0], lots[1])
+	}
+	if a, b := remaining(t, sales.stock, lots[0]), remaining(t, sales.stock, lots[1]); a != 0 || b != 4 {
+		t.Fatalf("lots have %.3f and %.3f kg left after the sale, want 0 and 4", a, b)
+	}
+
+	// 1,5 kg back: the 1 kg of the lot taken last, then 0,5 kg of the other
+	if _, err := sales.Refund(ctx, sale.ID, domain.CreateRefundRequest{
+		Items: []domain.CreateRefundItemRequest{{SaleItemID: sale.Items[0].ID, Quantity: 1.5}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if a, b := remaining(t, sales.stock, lots[0]), remaining(t, sales.stock, lots[1]); a != 0.5 || b != 5 {
+		t.Fatalf("lots have %.3f and %.3f kg left after the refund, want 0.5 and 5", a, b)
+	}
+}
+
+func TestRecallFollowsCarcassCuts(t *testing.T) {
+	db := openTestDB(t)
+	sales := newTestSales(t, db, nil)
+	suppliers, carcasses := newTestReceptions(t, db, sales)
+	ctx := context.Background()
+
+	rc, err := suppliers.Receive(ctx, "s1", domain.CreateReceptionRequest{
+		Items: []domain.ReceptionItemRequest{{
+			Category: domain.CategoryBoeuf, Quantity: 100, PricePerKg: 500,
+			Lot: &domain.LotRequest{Number: "L-42", Origin: "France"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := rc.Items[0].LotID
+	c, err := carcasses.Breakdown(ctx, rc.Items[0].CarcassID, domain.BreakdownRequest{
+		Cuts:    []domain.CarcassCutRequest{{ProductID: "p1", Quantity: 30}},
+		WasteKg: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cut := c.Cuts[0].LotID
+
+	sale := sell(t, sales, 4000)
+	if got := sale.Items[0].Lots; len(got) != 1 || got[0].LotID != cut || got[0].LotNumber != "L-42" {
+		t.Fatalf("sale took %+v, want 2 kg of the cut lot %s numbered L-42", got, cut)
+	}
+
+	recall, err := sales.stock.Recall(ctx, parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recall.Derived) != 1 || recall.Derived[0].ID != cut || recall.Derived[0].ParentID != parent {
+		t.Fatalf("recall derived %+v, want the cut lot %s", recall.Derived, cut)
+	}
+	if len(recall.Sales) != 1 || recall.Sales[0].SaleID != sale.ID || recall.Sales[0].LotID != cut || recall.Sales[0].Quantity != 2 {
+		t.Fatalf("recall sales %+v, want 2 kg of sale %s from %s", recall.Sales, sale.ID, cut)
+	}
+	if recall.Sold != 2 || len(recall.Clients) != 1 || recall.Clients[0].ClientID != "c1" || recall.Clients[0].Sales != 1 {
+		t.Fatalf("recall sold %.3f kg to %+v, want 2 kg to c1 in one sale", recall.Sold, recall.Clients)
+	}
+}
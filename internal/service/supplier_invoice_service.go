@@ -0,0 +1,368 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/port"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SupplierInvoiceService handles what the shop owes its suppliers: invoices
+// and the payments settling them. It mirrors CreditService.
+type SupplierInvoiceService struct {
+	repo         port.SupplierInvoiceRepository
+	supplierRepo port.SupplierRepository
+	receptions   port.ReceptionRepository
+	tx           port.Transactor
+	audit        *AuditService
+	terms        domain.SupplierTerms
+
+	// ownerThreshold is the payment amount above which only the owner may
+	// record a payment. Zero disables the check.
+	ownerThreshold domain.Money
+}
+
+// NewSupplierInvoiceService creates a new supplier invoice service.
+func NewSupplierInvoiceService(
+	repo port.SupplierInvoiceRepository,
+	supplierRepo port.SupplierRepository,
+	receptions port.ReceptionRepository,
+	tx port.Transactor,
+	audit *AuditService,
+	terms domain.SupplierTerms,
+	ownerThreshold domain.Money,
+) *SupplierInvoiceService {
+	return &SupplierInvoiceService{
+		repo:           repo,
+		supplierRepo:   supplierRepo,
+		receptions:     receptions,
+		tx:             tx,
+		audit:          audit,
+		terms:          terms,
+		ownerThreshold: ownerThreshold,
+	}
+}
+
+// List returns all supplier invoices, optionally filtered by status.
+func (s *SupplierInvoiceService) List(ctx context.Context, status *domain.CreditStatus) ([]domain.SupplierInvoice, error) {
+	return s.repo.FindAll(ctx, status)
+}
+
+// ListBySupplier returns the invoices of a supplier.
+func (s *SupplierInvoiceService) ListBySupplier(ctx context.Context, supplierID string) ([]domain.SupplierInvoice, error) {
+	return s.repo.FindBySupplierID(ctx, supplierID)
+}
+
+// Get returns a single invoice with its payments.
+func (s *SupplierInvoiceService) Get(ctx context.Context, id string) (*domain.SupplierInvoice, error) {
+	inv, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inv == nil {
+		return nil, errors.New("invoice not found")
+	}
+	return inv, nil
+}
+
+// Create records an invoice received from a supplier, covering receptions
+// not invoiced yet, and adds it to what the shop owes the supplier.
+func (s *SupplierInvoiceService) Create(ctx context.Context, supplierID string, req domain.CreateSupplierInvoiceRequest) (*domain.SupplierInvoice, error) {
+	now := time.Now()
+	date, err := parseDay(req.Date, now)
+	if err != nil {
+		return nil, errors.New("invalid date, expected YYYY-MM-DD")
+	}
+	var due *time.Time
+	if req.DueDate != "" {
+		d, err := parseDay(req.DueDate, now)
+		if err != nil {
+			return nil, errors.New("invalid dueDate, expected YYYY-MM-DD")
+		}
+		if d.Before(date) {
+			return nil, errors.New("dueDate cannot be before the invoice date")
+		}
+		due = &d
+	}
+
+	var inv *domain.SupplierInvoice
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		supplier, err := s.supplierRepo.FindByID(ctx, supplierID)
+		if err != nil {
+			return err
+		}
+		if supplier == nil {
+			return errors.New("supplier not found")
+		}
+
+		var received domain.Money
+		var receptionIDs []string
+		seen := make(map[string]bool, len(req.ReceptionIDs))
+		for _, id := range req.ReceptionIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			receptionIDs = append(receptionIDs, id)
+			rc, err := s.receptions.FindByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if rc == nil || rc.SupplierID != supplier.ID {
+				return fmt.Errorf("reception %s not found for this supplier", id)
+			}
+			if rc.InvoiceID != nil {
+				return fmt.Errorf("reception %s is already invoiced", id)
+			}
+			received += rc.Total
+		}
+
+		amount := received
+		if req.Amount != nil {
+			amount = *req.Amount
+		}
+		if amount <= 0 {
+			return errors.New("amount is required when the invoice covers no reception")
+		}
+		if due == nil {
+			d := s.terms.DueDate(supplier, date)
+			due = &d
+		}
+
+		inv = &domain.SupplierInvoice{
+			ID:              uuid.New().String(),
+			SupplierID:      supplier.ID,
+			SupplierName:    supplier.Name,
+			Number:          req.Number,
+			Amount:          amount,
+			RemainingAmount: amount,
+			Date:            date,
+			DueDate:         due,
+			ReceptionIDs:    []string{},
+			Payments:        []domain.SupplierPayment{},
+			CreatedAt:       now,
+		}
+		inv.Status = inv.StatusAt(now)
+		if err := s.repo.Create(ctx, inv); err != nil {
+			return err
+		}
+		for _, id := range receptionIDs {
+			if err := s.receptions.SetInvoice(ctx, id, inv.ID); err != nil {
+				return err
+			}
+		}
+		if err := s.supplierRepo.UpdateBalance(ctx, supplier.ID, amount); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditSupplierInvoice, inv.ID, domain.AuditCreate, nil, inv)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, inv.ID)
+}
+
+// AddPayment records a payment on an invoice and lowers what the shop owes
+// the supplier.
+func (s *SupplierInvoiceService) AddPayment(ctx context.Context, invoiceID string, req domain.CreatePaymentRequest) (*domain.SupplierInvoice, error) {
+	if err := checkOwnerThreshold(ctx, s.ownerThreshold, req.Amount); err != nil {
+		return nil, err
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		inv, err := s.repo.FindByID(ctx, invoiceID)
+		if err != nil {
+			return err
+		}
+		if inv == nil {
+			return errors.New("invoice not found")
+		}
+		if inv.Status == domain.CreditStatusPaye {
+			return errors.New("invoice already fully paid")
+		}
+		if req.Amount > inv.RemainingAmount {
+			return errors.New("payment exceeds remaining amount")
+		}
+
+		payment := s.newPayment(ctx, uuid.New().String(), req.Amount, req.Method, time.Now())
+		if err := s.applyPayment(ctx, inv, payment); err != nil {
+			return err
+		}
+		return s.supplierRepo.UpdateBalance(ctx, inv.SupplierID, -req.Amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, invoiceID)
+}
+
+// Pay spreads a payment to a supplier over their open invoices, oldest
+// first or overdue first, under a single receipt.
+func (s *SupplierInvoiceService) Pay(ctx context.Context, supplierID string, req domain.CreateSupplierPaymentRequest) (*domain.SupplierPaymentReceipt, error) {
+	if err := checkOwnerThreshold(ctx, s.ownerThreshold, req.Amount); err != nil {
+		return nil, err
+	}
+
+	var receipt *domain.SupplierPaymentReceipt
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		supplier, err := s.supplierRepo.FindByID(ctx, supplierID)
+		if err != nil {
+			return err
+		}
+		if supplier == nil {
+			return errors.New("supplier not found")
+		}
+
+		invoices, err := s.repo.FindBySupplierID(ctx, supplierID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		var open []domain.SupplierInvoice
+		var owed domain.Money
+		for _, inv := range invoices {
+			if inv.Status != domain.CreditStatusPaye && inv.RemainingAmount > 0 {
+				open = append(open, inv)
+				owed += inv.RemainingAmount
+			}
+		}
+		if len(open) == 0 {
+			return errors.New("supplier has no open invoice")
+		}
+		if req.Amount > owed {
+			return errors.New("payment exceeds supplier balance")
+		}
+		sortInvoicesForAllocation(open, req.Strategy, now)
+
+		receipt = &domain.SupplierPaymentReceipt{
+			ReceiptID:    uuid.New().String(),
+			SupplierID:   supplier.ID,
+			SupplierName: supplier.Name,
+			Amount:       req.Amount,
+			Method:       req.Method,
+			Date:         now,
+		}
+		left := req.Amount
+		for i := range open {
+			if left == 0 {
+				break
+			}
+			payment := s.newPayment(ctx, receipt.ReceiptID, left.Min(open[i].RemainingAmount), req.Method, now)
+			if err := s.applyPayment(ctx, &open[i], payment); err != nil {
+				return err
+			}
+			receipt.Payments = append(receipt.Payments, *payment)
+			left -= payment.Amount
+		}
+
+		if err := s.supplierRepo.UpdateBalance(ctx, supplier.ID, -req.Amount); err != nil {
+			return err
+		}
+		receipt.Balance = supplier.Balance - req.Amount
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// newPayment builds a payment attributed to the user performing the request.
+func (s *SupplierInvoiceService) newPayment(ctx context.Context, receiptID string, amount domain.Money, method domain.PaymentMethod, date time.Time) *domain.SupplierPayment {
+	userID, userName := actor(ctx)
+	return &domain.SupplierPayment{
+		ID:        uuid.New().String(),
+		ReceiptID: receiptID,
+		Amount:    amount,
+		Date:      date,
+		Method:    method,
+		UserID:    userID,
+		UserName:  userName,
+	}
+}
+
+// applyPayment records payment against inv and updates the invoice's
+// remaining amount and status. The supplier balance is left to the caller.
+func (s *SupplierInvoiceService) applyPayment(ctx context.Context, inv *domain.SupplierInvoice, payment *domain.SupplierPayment) error {
+	payment.InvoiceID = inv.ID
+	if err := s.repo.AddPayment(ctx, payment); err != nil {
+		return err
+	}
+	if err := s.audit.record(ctx, domain.AuditSupplierPayment, payment.ID, domain.AuditCreate, nil, payment); err != nil {
+		return err
+	}
+
+	before := *inv
+	inv.RemainingAmount -= payment.Amount
+	if inv.RemainingAmount < 0 {
+		inv.RemainingAmount = 0
+	}
+	inv.Status = inv.StatusAt(payment.Date)
+	if err := s.repo.Update(ctx, inv); err != nil {
+		return err
+	}
+	return s.audit.record(ctx, domain.AuditSupplierInvoice, inv.ID, domain.AuditUpdate, before, inv)
+}
+
+// sortInvoicesForAllocation orders open invoices the way sortForAllocation
+// orders client credits.
+func sortInvoicesForAllocation(invoices []domain.SupplierInvoice, strategy domain.AllocationStrategy, now time.Time) {
+	sort.SliceStable(invoices, func(i, j int) bool {
+		a, b := &invoices[i], &invoices[j]
+		if strategy == domain.AllocateOverdueFirst {
+			if ao, bo := a.IsOverdue(now), b.IsOverdue(now); ao != bo {
+				return ao
+			}
+			if a.DueDate != nil && b.DueDate != nil && !a.DueDate.Equal(*b.DueDate) {
+				return a.DueDate.Before(*b.DueDate)
+			}
+		}
+		return a.Date.Before(b.Date)
+	})
+}
+
+// RefreshOverdue flags open invoices past their due date as en_retard and
+// returns the others to en_cours. It returns the number of invoices changed.
+func (s *SupplierInvoiceService) RefreshOverdue(ctx context.Context, now time.Time) (int, error) {
+	changed := 0
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		for _, status := range []domain.CreditStatus{domain.CreditStatusEnCours, domain.CreditStatusEnRetard} {
+			invoices, err := s.repo.FindAll(ctx, &status)
+			if err != nil {
+				return err
+			}
+			for i := range invoices {
+				inv := &invoices[i]
+				before := *inv
+				if inv.Status = inv.StatusAt(now); inv.Status == before.Status {
+					continue
+				}
+				if err := s.repo.Update(ctx, inv); err != nil {
+					return err
+				}
+				if err := s.audit.record(ctx, domain.AuditSupplierInvoice, inv.ID, domain.AuditUpdate, before, inv); err != nil {
+					return err
+				}
+				changed++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// parseDay parses a YYYY-MM-DD date in local time, returning now when empty.
+func parseDay(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return now, nil
+	}
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/repository"
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestInvoices returns a supplier invoice service over db and gives
+// supplier s1 three open invoices, by name: "old" (300,00 €, dated 40 days
+// ago, due in 5 days), "late" (200,00 €, dated 20 days ago, due 2 days ago)
+// and "new" (400,00 €, dated 5 days ago, due in 25 days).
+func newTestInvoices(t *testing.T, db *sql.DB) (*SupplierInvoiceService, map[string]string) {
+	t.Helper()
+	ctx := context.Background()
+	supplierRepo := repository.NewSupplierRepo(db)
+	if err := supplierRepo.Create(ctx, &domain.Supplier{ID: "s1", Name: "Abattoir du Nord", CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewSupplierInvoiceService(
+		repository.NewSupplierInvoiceRepo(db), supplierRepo, repository.NewReceptionRepo(db),
+		repository.NewTxManager(db), NewAuditService(repository.NewAuditRepo(db)), domain.SupplierTerms{DefaultDays: 30}, 0,
+	)
+
+	day := func(days int) string { return time.Now().AddDate(0, 0, days).Format("2006-01-02") }
+	ids := map[string]string{}
+	for _, inv := range []struct {
+		name         string
+		amount       domain.Money
+		dated, dueIn int
+	}{
+		{"old", 30000, -40, 5},
+		{"late", 20000, -20, -2},
+		{"new", 40000, -5, 25},
+	} {
+		created, err := svc.Create(ctx, "s1", domain.CreateSupplierInvoiceRequest{
+			Number: "F-" + inv.name, Amount: &inv.amount, Date: day(inv.dated), DueDate: day(inv.dueIn),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[inv.name] = created.ID
+	}
+	return svc, ids
+}
+
+func supplierBalance(t *testing.T, db *sql.DB, supplierID string) domain.Money {
+	t.Helper()
+	var m domain.Money
+	if err := db.QueryRow(`SELECT balance FROM suppliers WHERE id = ?`, supplierID).Scan(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestPaySupplier(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   domain.Money
+		strategy domain.AllocationStrategy
+		want     []string                // invoices paid, by name, in order
+		left     map[string]domain.Money // what is left on each invoice
+		wantErr  string
+	}{
+		{
+			name:   "oldest first, partial on the last",
+			amount: 40000,
+			want:   []string{"old", "late"},
+			left:   map[string]domain.Money{"old": 0, "late": 10000, "new": 40000},
+		},
+		{
+			name:     "overdue first, partial on the last",
+			amount:   40000,
+			strategy: domain.AllocateOverdueFirst,
+			want:     []string{"late", "old"},
+			left:     map[string]domain.Money{"old": 10000, "late": 0, "new": 40000},
+		},
+		{
+			name:   "whole balance",
+			amount: 90000,
+			want:   []string{"old", "late", "new"},
+			left:   map[string]domain.Money{"old": 0, "late": 0, "new": 0},
+		},
+		{
+			name:    "more than owed",
+			amount:  90001,
+			left:    map[string]domain.Money{"old": 30000, "late": 20000, "new": 40000},
+			wantErr: "payment exceeds supplier balance",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			svc, ids := newTestInvoices(t, db)
+			ctx := context.Background()
+			if b := supplierBalance(t, db, "s1"); b != 90000 {
+				t.Fatalf("balance = %s before paying, want 900.00", b)
+			}
+
+			receipt, err := svc.Pay(ctx, "s1", domain.CreateSupplierPaymentRequest{
+				Amount: tt.amount, Method: domain.PaymentVirement, Strategy: tt.strategy,
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				if n := count(t, db, "supplier_payments"); n != 0 {
+					t.Fatalf("%d payments recorded, want none", n)
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(receipt.Payments) != len(tt.want) {
+					t.Fatalf("%d payments, want %d", len(receipt.Payments), len(tt.want))
+				}
+				var paid domain.Money
+				for i, p := range receipt.Payments {
+					if p.InvoiceID != ids[tt.want[i]] {
+						t.Errorf("payment %d went to %s, want the %q invoice", i, p.InvoiceID, tt.want[i])
+					}
+					if p.ReceiptID != receipt.ReceiptID {
+						t.Errorf("payment %d has receipt %s, want %s", i, p.ReceiptID, receipt.ReceiptID)
+					}
+					paid += p.Amount
+				}
+				if paid != tt.amount {
+					t.Fatalf("payments add up to %s, want %s", paid, tt.amount)
+				}
+				var receipts int
+				if err := db.QueryRow(`SELECT COUNT(DISTINCT receipt_id) FROM supplier_payments`).Scan(&receipts); err != nil {
+					t.Fatal(err)
+				}
+				if receipts != 1 || count(t, db, "supplier_payments") != len(tt.want) {
+					t.Fatalf("%d payments stored under %d receipts, want %d under one", count(t, db, "supplier_payments"), receipts, len(tt.want))
+				}
+				if receipt.Balance != 90000-tt.amount {
+					t.Errorf("receipt balance = %s, want %s", receipt.Balance, 90000-tt.amount)
+				}
+			}
+
+			for name, want := range tt.left {
+				inv, err := svc.repo.FindByID(ctx, ids[name])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if inv.RemainingAmount != want {
+					t.Errorf("%q invoice has %s left, want %s", name, inv.RemainingAmount, want)
+				}
+				if (want == 0) != (inv.Status == domain.CreditStatusPaye) {
+					t.Errorf("%q invoice status = %s with %s left", name, inv.Status, inv.RemainingAmount)
+				}
+			}
+			wantBalance := domain.Money(90000)
+			if tt.wantErr == "" {
+				wantBalance -= tt.amount
+			}
+			if b := supplierBalance(t, db, "s1"); b != wantBalance {
+				t.Fatalf("balance = %s, want %s", b, wantBalance)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/port"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SupplierService handles suppliers and the goods received from them.
+type SupplierService struct {
+	repo        port.SupplierRepository
+	receptions  port.ReceptionRepository
+	productRepo port.ProductRepository
+	carcasses   *CarcassService
+	stock       *StockService
+	tx          port.Transactor
+	audit       *AuditService
+}
+
+// NewSupplierService creates a new supplier service.
+func NewSupplierService(
+	repo port.SupplierRepository,
+	receptions port.ReceptionRepository,
+	productRepo port.ProductRepository,
+	carcasses *CarcassService,
+	stock *StockService,
+	tx port.Transactor,
+	audit *AuditService,
+) *SupplierService {
+	return &SupplierService{
+		repo:        repo,
+		receptions:  receptions,
+		productRepo: productRepo,
+		carcasses:   carcasses,
+		stock:       stock,
+		tx:          tx,
+		audit:       audit,
+	}
+}
+
+// List returns all suppliers.
+func (s *SupplierService) List(ctx context.Context) ([]domain.Supplier, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// Get returns a single supplier by ID.
+func (s *SupplierService) Get(ctx context.Context, id string) (*domain.Supplier, error) {
+	supplier, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, errors.New("supplier not found")
+	}
+	return supplier, nil
+}
+
+// Create validates and creates a new supplier.
+func (s *SupplierService) Create(ctx context.Context, req domain.CreateSupplierRequest) (*domain.Supplier, error) {
+	supplier := &domain.Supplier{
+		ID:              uuid.New().String(),
+		Name:            req.Name,
+		Phone:           req.Phone,
+		Email:           req.Email,
+		Address:         req.Address,
+		PaymentTermDays: req.PaymentTermDays,
+		CreatedAt:       time.Now(),
+	}
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, supplier); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditSupplier, supplier.ID, domain.AuditCreate, nil, supplier)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+// Update modifies an existing supplier's fields.
+func (s *SupplierService) Update(ctx context.Context, id string, req domain.UpdateSupplierRequest) (*domain.Supplier, error) {
+	var supplier *domain.Supplier
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		supplier, err = s.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		before := *supplier
+
+		if req.Name != nil {
+			supplier.Name = *req.Name
+		}
+		if req.Phone != nil {
+			supplier.Phone = *req.Phone
+		}
+		if req.Email != nil {
+			supplier.Email = *req.Email
+		}
+		if req.Address != nil {
+			supplier.Address = *req.Address
+		}
+		if req.PaymentTermDays != nil {
+			if *req.PaymentTermDays < 0 {
+				supplier.PaymentTermDays = nil
+			} else {
+				days := *req.PaymentTermDays
+				supplier.PaymentTermDays = &days
+			}
+		}
+
+		if err := s.repo.Update(ctx, supplier); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, domain.AuditSupplier, supplier.ID, domain.AuditUpdate, before, supplier)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+// Receptions returns the goods received from a supplier, optionally only
+// those not invoiced yet.
+func (s *SupplierService) Receptions(ctx context.Context, supplierID string, uninvoicedOnly bool) ([]domain.Reception, error) {
+	if _, err := s.Get(ctx, supplierID); err != nil {
+		return nil, err
+	}
+	return s.receptions.FindBySupplier(ctx, supplierID, uninvoicedOnly)
+}
+
+// Receive records goods delivered by a supplier. Each line gets a lot with
+// its traceability details. Products go into stock; carcasses are
+// registered to be cut up, at their share of the cost. What is received is
+// owed once invoiced.
+func (s *SupplierService) Receive(ctx context.Context, supplierID string, req domain.CreateReceptionRequest) (*domain.Reception, error) {
+	date := time.Now()
+	if req.Date != "" {
+		day, err := time.ParseInLocation("2006-01-02", req.Date, time.Local)
+		if err != nil {
+			return nil, errors.New("invalid date, expected YYYY-MM-DD")
+		}
+		if day.After(date) {
+			return nil, errors.New("date cannot be in the future")
+		}
+		date = day
+	}
+
+	var rc *domain.Reception
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		supplier, err := s.Get(ctx, supplierID)
+		if err != nil {
+			return err
+		}
+
+		userID, userName := actor(ctx)
+		rc = &domain.Reception{
+			ID:           uuid.New().String(),
+			SupplierID:   supplier.ID,
+			SupplierName: supplier.Name,
+			Note:         req.Note,
+			UserID:       userID,
+			UserName:     userName,
+			Date:         date,
+		}
+		var lots []*domain.Lot
+		for _, r := range req.Items {
+			lot := newLot(r.Lot, r.Quantity, date)
+			lot.ReceptionID = rc.ID
+			lot.SupplierID, lot.SupplierName = supplier.ID, supplier.Name
+			item := domain.ReceptionItem{
+				LotID:      lot.ID,
+				ID:         uuid.New().String(),
+				Quantity:   r.Quantity,
+				PricePerKg: r.PricePerKg,
+				Subtotal:   r.PricePerKg.MulKg(r.Quantity),
+			}
+			if r.ProductID != "" {
+				product, err := s.productRepo.FindByID(ctx, r.ProductID)
+				if err != nil {
+					return err
+				}
+				if product == nil {
+					return fmt.Errorf("product %s not found", r.ProductID)
+				}
+				item.ProductID, item.Name = product.ID, product.Name
+				lot.ProductID = product.ID
+			} else {
+				carcass, err := s.carcasses.Create(ctx, domain.CreateCarcassRequest{
+					Category:    r.Category,
+					Description: r.Description,
+					Supplier:    supplier.Name,
+					SupplierID:  supplier.ID,
+					Weight:      r.Quantity,
+					Cost:        item.Subtotal,
+					ReceivedAt:  req.Date,
+				})
+				if err != nil {
+					return err
+				}
+				item.CarcassID = carcass.ID
+				// The carcass is not sold whole, its cuts get lots of their own
+				lot.CarcassID, lot.Remaining = carcass.ID, 0
+				item.Name = fmt.Sprintf("Carcasse %s", r.Category)
+				if r.Description != "" {
+					item.Name += " " + r.Description
+				}
+			}
+			rc.Items = append(rc.Items, item)
+			rc.Total += item.Subtotal
+			lots = append(lots, lot)
+		}
+
+		if err := s.receptions.Create(ctx, rc); err != nil {
+			return err
+		}
+		for _, lot := range lots {
+			if err := s.stock.receiveLot(ctx, lot); err != nil {
+				return err
+			}
+		}
+		for _, item := range rc.Items {
+			if item.ProductID == "" {
+				continue
+			}
+			m := newStockMovement(ctx, item.ProductID, domain.StockReception, item.Quantity, rc.ID)
+			m.Note = supplier.Name
+			if err := s.stock.record(ctx, m); err != nil {
+				return err
+			}
+		}
+		return s.audit.record(ctx, domain.AuditReception, rc.ID, domain.AuditCreate, nil, rc)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rc, nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"boucherie-api/internal/auth"
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/port"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserService handles staff accounts and authentication.
+type UserService struct {
+	repo   port.UserRepository
+	tokens *auth.Tokens
+}
+
+// NewUserService creates a new user service.
+func NewUserService(repo port.UserRepository, tokens *auth.Tokens) *UserService {
+	return &UserService{repo: repo, tokens: tokens}
+}
+
+// Login checks credentials and issues an access token.
+func (s *UserService) Login(ctx context.Context, req domain.LoginRequest) (*domain.LoginResponse, error) {
+	user, err := s.repo.FindByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.Active || !auth.CheckPassword(user.PasswordHash, req.Password) {
+		return nil, fmt.Errorf("%w: invalid username or password", ErrUnauthorized)
+	}
+
+	token, exp, err := s.tokens.Issue(user.ID, string(user.Role))
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LoginResponse{Token: token, ExpiresAt: exp, User: *user}, nil
+}
+
+// Authenticate returns the active user behind an access token.
+func (s *UserService) Authenticate(ctx context.Context, token string) (*domain.User, error) {
+	claims, err := s.tokens.Verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnauthorized, err)
+	}
+	user, err := s.repo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.Active {
+		return nil, fmt.Errorf("%w: account disabled", ErrUnauthorized)
+	}
+	return user, nil
+}
+
+// List returns all staff accounts.
+func (s *UserService) List(ctx context.Context) ([]domain.User, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// Create validates and creates a new staff account.
+func (s *UserService) Create(ctx context.Context, req domain.CreateUserRequest) (*domain.User, error) {
+	existing, err := s.repo.FindByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("username already taken")
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+	user := &domain.User{
+		ID:           uuid.New().String(),
+		Username:     req.Username,
+		Name:         req.Name,
+		Role:         req.Role,
+		PasswordHash: hash,
+		Active:       true,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.repo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Update modifies an existing staff account.
+func (s *UserService) Update(ctx context.Context, id string, req domain.UpdateUserRequest) (*domain.User, error) {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	wasOwner := user.Role == domain.RoleOwner && user.Active
+
+	if req.Name != nil {
+		user.Name = *req.Name
+	}
+	if req.Role != nil {
+		user.Role = *req.Role
+	}
+	if req.Active != nil {
+		user.Active = *req.Active
+	}
+	if req.Password != nil {
+		hash, err := auth.HashPassword(*req.Password)
+		if err != nil {
+			return nil, err
+		}
+		user.PasswordHash = hash
+	}
+
+	// The shop must keep at least one owner able to manage accounts.
+	if wasOwner && (user.Role != domain.RoleOwner || !user.Active) {
+		owners, err := s.repo.CountActiveOwners(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if owners <= 1 {
+			return nil, fmt.Errorf("%w: %s is the last active owner", ErrConflict, user.Username)
+		}
+	}
+
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// EnsureOwner creates the first owner account when no user exists yet.
+// When password is empty a random one is generated and returned.
+func (s *UserService) EnsureOwner(ctx context.Context, username, password string) (string, error) {
+	n, err := s.repo.Count(ctx)
+	if err != nil || n > 0 {
+		return "", err
+	}
+
+	if password == "" {
+		buf := make([]byte, 12)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		password = base64.RawURLEncoding.EncodeToString(buf)
+	}
+	_, err = s.Create(ctx, domain.CreateUserRequest{
+		Username: username,
+		Name:     username,
+		Password: password,
+		Role:     domain.RoleOwner,
+	})
+	if err != nil {
+		return "", err
+	}
+	return password, nil
+}
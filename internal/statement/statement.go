@@ -0,0 +1,124 @@
+// Package statement renders client account statements as CSV and PDF.
+package statement
+
+import (
+	"boucherie-api/internal/domain"
+	"boucherie-api/internal/pdf"
+	"boucherie-api/internal/receipt"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// CSV writes the statement as semicolon-separated values, the format
+// spreadsheet software expects in French locales.
+func CSV(w io.Writer, st *domain.Statement) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = ';'
+
+	records := [][]string{
+		{"date", "type", "reference", "libelle", "debit", "credit", "solde"},
+		{st.From, "", "", "Solde initial", "", "", st.OpeningBalance.String()},
+	}
+	for _, e := range st.Entries {
+		records = append(records, []string{
+			e.Date.Format("2006-01-02 15:04"),
+			string(e.Kind),
+			e.Reference,
+			e.Description,
+			optional(e.Debit),
+			optional(e.Credit),
+			e.Balance.String(),
+		})
+	}
+	records = append(records, []string{
+		st.To, "", "", "Solde final", st.TotalDebit.String(), st.TotalCredit.String(), st.ClosingBalance.String(),
+	})
+
+	if err := cw.WriteAll(records); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// PDF renders the statement on A4 pages.
+func PDF(shop receipt.Shop, st *domain.Statement) []byte {
+	doc := pdf.New(595.28, 841.89, 40, 9)
+	doc.AddLines(lines(shop, st, doc.Columns()))
+	return doc.Bytes()
+}
+
+// Column widths of the ledger table, the description taking what is left.
+const (
+	dateWidth   = 16
+	amountWidth = 13
+)
+
+func lines(shop receipt.Shop, st *domain.Statement, width int) []pdf.Line {
+	descWidth := width - dateWidth - 3*amountWidth - 4
+	sep := pdf.Line{Text: strings.Repeat("-", width)}
+	var out []pdf.Line
+	add := func(text string, bold bool) {
+		out = append(out, pdf.Line{Text: text, Bold: bold})
+	}
+	row := func(date, desc, debit, credit, balance string) string {
+		return fmt.Sprintf("%-*s %s %*s %*s %*s",
+			dateWidth, date, pad(desc, descWidth),
+			amountWidth, debit, amountWidth, credit, amountWidth, balance)
+	}
+
+	add(shop.Name, true)
+	for _, h := range []string{shop.Address, shop.Phone} {
+		if h != "" {
+			add(h, false)
+		}
+	}
+	add("", false)
+	add("RELEVÉ DE COMPTE", true)
+	add("Client  : "+st.ClientName, false)
+	if st.ClientPhone != "" {
+		add("Tél.    : "+st.ClientPhone, false)
+	}
+	add("Période : du "+frenchDate(st.From)+" au "+frenchDate(st.To), false)
+	add("Édité le "+st.GeneratedAt.Format("02/01/2006 15:04"), false)
+	add("", false)
+
+	add(row("Date", "Libellé", "Débit", "Crédit", "Solde"), true)
+	out = append(out, sep)
+	add(row(frenchDate(st.From), "Solde initial", "", "", st.OpeningBalance.String()), false)
+	for _, e := range st.Entries {
+		add(row(e.Date.Format("02/01/2006 15:04"), e.Description, optional(e.Debit), optional(e.Credit), e.Balance.String()), false)
+	}
+	out = append(out, sep)
+	add(row("", "Totaux de la période", st.TotalDebit.String(), st.TotalCredit.String(), ""), false)
+	add(receipt.Columns("SOLDE DÛ AU "+frenchDate(st.To), st.ClosingBalance.String(), width), true)
+	return out
+}
+
+// optional leaves zero amounts blank so debit and credit columns stay readable.
+func optional(m domain.Money) string {
+	if m == 0 {
+		return ""
+	}
+	return m.String()
+}
+
+// frenchDate turns YYYY-MM-DD into DD/MM/YYYY.
+func frenchDate(d string) string {
+	if len(d) != 10 {
+		return d
+	}
+	return d[8:10] + "/" + d[5:7] + "/" + d[0:4]
+}
+
+// pad truncates or right-pads s to exactly width characters.
+func pad(s string, width int) string {
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		return string([]rune(s)[:width])
+	}
+	return s + strings.Repeat(" ", width-n)
+}
@@ -1,4 +1,4 @@
--- Boucherie API — Full schema
+-- Initial schema: clients, products, sales, credits, payments and orders.
 
 CREATE TABLE IF NOT EXISTS clients (
     id         TEXT PRIMARY KEY,
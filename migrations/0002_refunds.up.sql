@@ -0,0 +1,27 @@
+-- Refund documents for sale cancellations and partial refunds.
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id            TEXT PRIMARY KEY,
+    sale_id       TEXT NOT NULL REFERENCES sales(id),
+    client_id     TEXT NOT NULL REFERENCES clients(id),
+    kind          TEXT NOT NULL CHECK(kind IN ('annulation','remboursement')),
+    total         REAL NOT NULL CHECK(total >= 0),
+    credit_amount REAL NOT NULL DEFAULT 0,
+    cash_amount   REAL NOT NULL DEFAULT 0,
+    method        TEXT NOT NULL DEFAULT 'cash' CHECK(method IN ('cash','carte','virement')),
+    reason        TEXT DEFAULT '',
+    date          DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS refund_items (
+    id           TEXT PRIMARY KEY,
+    refund_id    TEXT NOT NULL REFERENCES refunds(id) ON DELETE CASCADE,
+    sale_item_id TEXT NOT NULL REFERENCES sale_items(id),
+    product_id   TEXT NOT NULL,
+    product_name TEXT NOT NULL,
+    quantity     REAL NOT NULL CHECK(quantity > 0),
+    amount       REAL NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_sale ON refunds(sale_id);
+CREATE INDEX IF NOT EXISTS idx_refunds_date ON refunds(date);
@@ -0,0 +1,143 @@
+-- Store every amount as an integer number of centimes instead of REAL.
+-- Tables are rebuilt because SQLite cannot change a column type in place;
+-- foreign keys are verified by the migration runner before committing.
+
+-- clients
+CREATE TABLE clients_new (
+    id           TEXT PRIMARY KEY,
+    name         TEXT    NOT NULL,
+    phone        TEXT    NOT NULL,
+    email        TEXT    DEFAULT '',
+    avatar       TEXT    DEFAULT '',
+    total_credit INTEGER NOT NULL DEFAULT 0,
+    created_at   DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO clients_new (id, name, phone, email, avatar, total_credit, created_at)
+    SELECT id, name, phone, email, avatar, CAST(ROUND(COALESCE(total_credit, 0) * 100) AS INTEGER), created_at FROM clients;
+DROP TABLE clients;
+ALTER TABLE clients_new RENAME TO clients;
+
+-- products
+CREATE TABLE products_new (
+    id           TEXT PRIMARY KEY,
+    name         TEXT NOT NULL,
+    category     TEXT NOT NULL CHECK(category IN ('boeuf','agneau','poulet','veau','charcuterie')),
+    price_per_kg INTEGER NOT NULL CHECK(price_per_kg > 0),
+    image        TEXT DEFAULT '',
+    in_stock     INTEGER DEFAULT 1
+);
+INSERT INTO products_new (id, name, category, price_per_kg, image, in_stock)
+    SELECT id, name, category, CAST(ROUND(price_per_kg * 100) AS INTEGER), image, in_stock FROM products;
+DROP TABLE products;
+ALTER TABLE products_new RENAME TO products;
+
+-- sales
+CREATE TABLE sales_new (
+    id            TEXT PRIMARY KEY,
+    client_id     TEXT NOT NULL REFERENCES clients(id),
+    client_name   TEXT NOT NULL,
+    total         INTEGER NOT NULL,
+    paid_amount   INTEGER NOT NULL DEFAULT 0,
+    credit_amount INTEGER NOT NULL DEFAULT 0,
+    date          DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO sales_new (id, client_id, client_name, total, paid_amount, credit_amount, date)
+    SELECT id, client_id, client_name, CAST(ROUND(total * 100) AS INTEGER),
+           CAST(ROUND(paid_amount * 100) AS INTEGER), CAST(ROUND(credit_amount * 100) AS INTEGER), date
+    FROM sales;
+DROP TABLE sales;
+ALTER TABLE sales_new RENAME TO sales;
+
+-- sale_items (gains the price per kg applied at sale time)
+CREATE TABLE sale_items_new (
+    id           TEXT PRIMARY KEY,
+    sale_id      TEXT NOT NULL REFERENCES sales(id) ON DELETE CASCADE,
+    product_id   TEXT NOT NULL,
+    product_name TEXT NOT NULL,
+    quantity     REAL NOT NULL CHECK(quantity > 0),
+    price_per_kg INTEGER NOT NULL DEFAULT 0,
+    subtotal     INTEGER NOT NULL
+);
+INSERT INTO sale_items_new (id, sale_id, product_id, product_name, quantity, price_per_kg, subtotal)
+    SELECT id, sale_id, product_id, product_name, quantity,
+           CAST(ROUND(subtotal * 100 / quantity) AS INTEGER), CAST(ROUND(subtotal * 100) AS INTEGER)
+    FROM sale_items;
+DROP TABLE sale_items;
+ALTER TABLE sale_items_new RENAME TO sale_items;
+
+-- credits
+CREATE TABLE credits_new (
+    id               TEXT PRIMARY KEY,
+    client_id        TEXT NOT NULL REFERENCES clients(id),
+    client_name      TEXT NOT NULL,
+    sale_id          TEXT NOT NULL REFERENCES sales(id),
+    amount           INTEGER NOT NULL,
+    remaining_amount INTEGER NOT NULL,
+    status           TEXT NOT NULL DEFAULT 'en_cours' CHECK(status IN ('en_cours','en_retard','paye')),
+    created_at       DATETIME DEFAULT CURRENT_TIMESTAMP,
+    due_date         DATETIME
+);
+INSERT INTO credits_new (id, client_id, client_name, sale_id, amount, remaining_amount, status, created_at, due_date)
+    SELECT id, client_id, client_name, sale_id, CAST(ROUND(amount * 100) AS INTEGER),
+           CAST(ROUND(remaining_amount * 100) AS INTEGER), status, created_at, due_date
+    FROM credits;
+DROP TABLE credits;
+ALTER TABLE credits_new RENAME TO credits;
+
+-- payments
+CREATE TABLE payments_new (
+    id        TEXT PRIMARY KEY,
+    credit_id TEXT NOT NULL REFERENCES credits(id) ON DELETE CASCADE,
+    amount    INTEGER NOT NULL CHECK(amount > 0),
+    date      DATETIME DEFAULT CURRENT_TIMESTAMP,
+    method    TEXT NOT NULL DEFAULT 'cash' CHECK(method IN ('cash','carte','virement'))
+);
+INSERT INTO payments_new (id, credit_id, amount, date, method)
+    SELECT id, credit_id, CAST(ROUND(amount * 100) AS INTEGER), date, method FROM payments;
+DROP TABLE payments;
+ALTER TABLE payments_new RENAME TO payments;
+
+-- refunds
+CREATE TABLE refunds_new (
+    id            TEXT PRIMARY KEY,
+    sale_id       TEXT NOT NULL REFERENCES sales(id),
+    client_id     TEXT NOT NULL REFERENCES clients(id),
+    kind          TEXT NOT NULL CHECK(kind IN ('annulation','remboursement')),
+    total         INTEGER NOT NULL CHECK(total >= 0),
+    credit_amount INTEGER NOT NULL DEFAULT 0,
+    cash_amount   INTEGER NOT NULL DEFAULT 0,
+    method        TEXT NOT NULL DEFAULT 'cash' CHECK(method IN ('cash','carte','virement')),
+    reason        TEXT DEFAULT '',
+    date          DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO refunds_new (id, sale_id, client_id, kind, total, credit_amount, cash_amount, method, reason, date)
+    SELECT id, sale_id, client_id, kind, CAST(ROUND(total * 100) AS INTEGER),
+           CAST(ROUND(credit_amount * 100) AS INTEGER), CAST(ROUND(cash_amount * 100) AS INTEGER), method, reason, date
+    FROM refunds;
+DROP TABLE refunds;
+ALTER TABLE refunds_new RENAME TO refunds;
+
+-- refund_items
+CREATE TABLE refund_items_new (
+    id           TEXT PRIMARY KEY,
+    refund_id    TEXT NOT NULL REFERENCES refunds(id) ON DELETE CASCADE,
+    sale_item_id TEXT NOT NULL REFERENCES sale_items(id),
+    product_id   TEXT NOT NULL,
+    product_name TEXT NOT NULL,
+    quantity     REAL NOT NULL CHECK(quantity > 0),
+    amount       INTEGER NOT NULL
+);
+INSERT INTO refund_items_new (id, refund_id, sale_item_id, product_id, product_name, quantity, amount)
+    SELECT id, refund_id, sale_item_id, product_id, product_name, quantity, CAST(ROUND(amount * 100) AS INTEGER)
+    FROM refund_items;
+DROP TABLE refund_items;
+ALTER TABLE refund_items_new RENAME TO refund_items;
+
+-- Indexes dropped with the old tables
+CREATE INDEX IF NOT EXISTS idx_sales_client    ON sales(client_id);
+CREATE INDEX IF NOT EXISTS idx_sales_date      ON sales(date);
+CREATE INDEX IF NOT EXISTS idx_credits_client  ON credits(client_id);
+CREATE INDEX IF NOT EXISTS idx_credits_status  ON credits(status);
+CREATE INDEX IF NOT EXISTS idx_payments_credit ON payments(credit_id);
+CREATE INDEX IF NOT EXISTS idx_refunds_sale    ON refunds(sale_id);
+CREATE INDEX IF NOT EXISTS idx_refunds_date    ON refunds(date);
@@ -0,0 +1,5 @@
+ALTER TABLE payments DROP COLUMN user_name;
+ALTER TABLE payments DROP COLUMN user_id;
+ALTER TABLE sales DROP COLUMN user_name;
+ALTER TABLE sales DROP COLUMN user_id;
+DROP TABLE IF EXISTS users;
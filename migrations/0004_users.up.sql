@@ -0,0 +1,16 @@
+-- Staff accounts, and who recorded each sale and payment.
+
+CREATE TABLE IF NOT EXISTS users (
+    id            TEXT PRIMARY KEY,
+    username      TEXT NOT NULL UNIQUE,
+    name          TEXT NOT NULL,
+    role          TEXT NOT NULL CHECK(role IN ('owner','cashier','preparer')),
+    password_hash TEXT NOT NULL,
+    active        INTEGER NOT NULL DEFAULT 1,
+    created_at    DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE sales ADD COLUMN user_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE sales ADD COLUMN user_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE payments ADD COLUMN user_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE payments ADD COLUMN user_name TEXT NOT NULL DEFAULT '';
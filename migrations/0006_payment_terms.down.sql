@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_credits_due_date;
+
+ALTER TABLE clients DROP COLUMN payment_term_days;
@@ -0,0 +1,5 @@
+-- Per-client payment term overriding the shop default (NULL = default).
+
+ALTER TABLE clients ADD COLUMN payment_term_days INTEGER CHECK(payment_term_days >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_credits_due_date ON credits(due_date);
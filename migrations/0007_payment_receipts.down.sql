@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_payments_receipt;
+
+ALTER TABLE payments DROP COLUMN receipt_id;
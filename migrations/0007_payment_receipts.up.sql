@@ -0,0 +1,8 @@
+-- Payments recorded together (one payment on account spread over several
+-- credits) share a receipt ID.
+
+ALTER TABLE payments ADD COLUMN receipt_id TEXT NOT NULL DEFAULT '';
+
+UPDATE payments SET receipt_id = id WHERE receipt_id = '';
+
+CREATE INDEX IF NOT EXISTS idx_payments_receipt ON payments(receipt_id);
@@ -0,0 +1,3 @@
+ALTER TABLE sales DROP COLUMN credit_override_by;
+
+ALTER TABLE clients DROP COLUMN credit_limit;
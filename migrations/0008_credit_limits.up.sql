@@ -0,0 +1,6 @@
+-- Per-client credit limit overriding the shop default (NULL = default), and
+-- the owner who authorised a sale over the limit.
+
+ALTER TABLE clients ADD COLUMN credit_limit INTEGER CHECK(credit_limit >= 0);
+
+ALTER TABLE sales ADD COLUMN credit_override_by TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE orders DROP COLUMN cancelled_at;
+ALTER TABLE orders DROP COLUMN delivered_at;
+ALTER TABLE orders DROP COLUMN ready_at;
+ALTER TABLE orders DROP COLUMN confirmed_at;
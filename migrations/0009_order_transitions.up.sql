@@ -0,0 +1,6 @@
+-- When an order went through each step of its lifecycle.
+
+ALTER TABLE orders ADD COLUMN confirmed_at DATETIME;
+ALTER TABLE orders ADD COLUMN ready_at     DATETIME;
+ALTER TABLE orders ADD COLUMN delivered_at DATETIME;
+ALTER TABLE orders ADD COLUMN cancelled_at DATETIME;
@@ -0,0 +1,4 @@
+ALTER TABLE orders DROP COLUMN sale_id;
+
+ALTER TABLE sales DROP COLUMN payment_method;
+ALTER TABLE sales DROP COLUMN order_id;
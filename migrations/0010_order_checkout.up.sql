@@ -0,0 +1,6 @@
+-- Sales made when an order is picked up, and how sales were paid.
+
+ALTER TABLE sales ADD COLUMN order_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE sales ADD COLUMN payment_method TEXT NOT NULL DEFAULT 'cash' CHECK(payment_method IN ('cash','carte','virement'));
+
+ALTER TABLE orders ADD COLUMN sale_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_order_deposits_order;
+DROP TABLE IF EXISTS order_deposits;
+
+ALTER TABLE sales DROP COLUMN deposit_amount;
+ALTER TABLE orders DROP COLUMN deposit_outcome;
+ALTER TABLE order_items DROP COLUMN price_per_kg;
@@ -0,0 +1,27 @@
+-- Priced order lines and deposits (acomptes) taken against an order.
+
+-- Price per kg quoted when the order was taken. Existing lines get today's price.
+ALTER TABLE order_items ADD COLUMN price_per_kg INTEGER NOT NULL DEFAULT 0;
+UPDATE order_items SET price_per_kg = COALESCE(
+    (SELECT price_per_kg FROM products WHERE products.id = order_items.product_id), 0);
+
+-- What became of the deposit once the order was delivered or cancelled.
+ALTER TABLE orders ADD COLUMN deposit_outcome TEXT NOT NULL DEFAULT ''
+    CHECK(deposit_outcome IN ('', 'deduit', 'rembourse', 'conserve'));
+
+-- Part of a sale settled by the order's deposit.
+ALTER TABLE sales ADD COLUMN deposit_amount INTEGER NOT NULL DEFAULT 0;
+
+-- Deposits received (acompte) and paid back (remboursement), in centimes.
+CREATE TABLE IF NOT EXISTS order_deposits (
+    id         TEXT PRIMARY KEY,
+    order_id   TEXT    NOT NULL REFERENCES orders(id),
+    kind       TEXT    NOT NULL CHECK(kind IN ('acompte', 'remboursement')),
+    amount     INTEGER NOT NULL CHECK(amount > 0),
+    method     TEXT    NOT NULL DEFAULT 'cash' CHECK(method IN ('cash', 'carte', 'virement')),
+    user_id    TEXT    NOT NULL DEFAULT '',
+    user_name  TEXT    NOT NULL DEFAULT '',
+    date       DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_deposits_order ON order_deposits(order_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_orders_occurrence;
+ALTER TABLE orders DROP COLUMN recurring_order_id;
+
+DROP TABLE IF EXISTS recurring_order_skips;
+DROP TABLE IF EXISTS recurring_order_items;
+DROP TABLE IF EXISTS recurring_orders;
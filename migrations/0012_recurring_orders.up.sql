@@ -0,0 +1,33 @@
+-- Recurring order templates, materialized into orders ahead of pickup.
+
+CREATE TABLE IF NOT EXISTS recurring_orders (
+    id          TEXT PRIMARY KEY,
+    client_id   TEXT NOT NULL REFERENCES clients(id),
+    client_name TEXT NOT NULL,
+    weekdays    TEXT NOT NULL,          -- comma-separated, 0 = Sunday
+    start_date  DATETIME NOT NULL,
+    end_date    DATETIME,
+    notes       TEXT NOT NULL DEFAULT '',
+    active      INTEGER NOT NULL DEFAULT 1,
+    created_at  DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE TABLE IF NOT EXISTS recurring_order_items (
+    id                 TEXT PRIMARY KEY,
+    recurring_order_id TEXT NOT NULL REFERENCES recurring_orders(id) ON DELETE CASCADE,
+    product_id         TEXT NOT NULL,
+    product_name       TEXT NOT NULL,
+    quantity           REAL NOT NULL CHECK(quantity > 0)
+);
+
+-- Occurrences the scheduler must not create.
+CREATE TABLE IF NOT EXISTS recurring_order_skips (
+    recurring_order_id TEXT NOT NULL REFERENCES recurring_orders(id) ON DELETE CASCADE,
+    date               TEXT NOT NULL,   -- YYYY-MM-DD
+    PRIMARY KEY (recurring_order_id, date)
+);
+
+-- Orders created from a template, one per occurrence.
+ALTER TABLE orders ADD COLUMN recurring_order_id TEXT NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_occurrence
+    ON orders(recurring_order_id, date(pickup_date)) WHERE recurring_order_id <> '';
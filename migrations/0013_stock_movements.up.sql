@@ -0,0 +1,16 @@
+-- Stock ledger in kg. The stock of a product is the sum of its movements:
+-- positive quantities come in, negative ones go out.
+
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id         TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    kind       TEXT NOT NULL CHECK(kind IN ('reception', 'vente', 'remboursement', 'perte', 'ajustement', 'transfert')),
+    quantity   REAL NOT NULL CHECK(quantity <> 0),
+    reference  TEXT NOT NULL DEFAULT '',  -- sale or refund the movement comes from
+    note       TEXT NOT NULL DEFAULT '',
+    user_id    TEXT NOT NULL DEFAULT '',
+    user_name  TEXT NOT NULL DEFAULT '',
+    date       DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product ON stock_movements(product_id, date);
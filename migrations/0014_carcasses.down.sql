@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_carcass_cuts_product;
+DROP INDEX IF EXISTS idx_carcass_cuts_carcass;
+DROP TABLE IF EXISTS carcass_cuts;
+DROP INDEX IF EXISTS idx_carcasses_received;
+DROP TABLE IF EXISTS carcasses;
@@ -0,0 +1,32 @@
+-- Carcass breakdown (découpe): a carcass bought whole is cut into catalog
+-- products. Each cut carries its share of the carcass cost.
+
+CREATE TABLE IF NOT EXISTS carcasses (
+    id             TEXT PRIMARY KEY,
+    category       TEXT NOT NULL,
+    description    TEXT NOT NULL DEFAULT '',
+    supplier       TEXT NOT NULL DEFAULT '',
+    weight         REAL NOT NULL CHECK(weight > 0),
+    cost           INTEGER NOT NULL CHECK(cost >= 0),
+    status         TEXT NOT NULL DEFAULT 'recue' CHECK(status IN ('recue', 'decoupee')),
+    waste_kg       REAL NOT NULL DEFAULT 0 CHECK(waste_kg >= 0),
+    user_id        TEXT NOT NULL DEFAULT '',
+    user_name      TEXT NOT NULL DEFAULT '',
+    received_at    DATETIME NOT NULL,
+    broken_down_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_carcasses_received ON carcasses(received_at);
+
+CREATE TABLE IF NOT EXISTS carcass_cuts (
+    id           TEXT PRIMARY KEY,
+    carcass_id   TEXT NOT NULL REFERENCES carcasses(id) ON DELETE CASCADE,
+    product_id   TEXT NOT NULL,
+    product_name TEXT NOT NULL,
+    quantity     REAL NOT NULL CHECK(quantity > 0),
+    price_per_kg INTEGER NOT NULL DEFAULT 0,
+    cost         INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_carcass_cuts_carcass ON carcass_cuts(carcass_id);
+CREATE INDEX IF NOT EXISTS idx_carcass_cuts_product ON carcass_cuts(product_id);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_supplier_payments_receipt;
+DROP INDEX IF EXISTS idx_supplier_payments_invoice;
+DROP TABLE IF EXISTS supplier_payments;
+DROP INDEX IF EXISTS idx_reception_items_reception;
+DROP TABLE IF EXISTS reception_items;
+DROP INDEX IF EXISTS idx_receptions_invoice;
+DROP INDEX IF EXISTS idx_receptions_supplier;
+DROP TABLE IF EXISTS receptions;
+DROP INDEX IF EXISTS idx_supplier_invoices_status;
+DROP TABLE IF EXISTS supplier_invoices;
+DROP TABLE IF EXISTS suppliers;
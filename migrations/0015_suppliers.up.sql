@@ -0,0 +1,72 @@
+-- Suppliers and what the shop owes them: receptions of goods, supplier
+-- invoices and the payments settling them. Amounts in centimes.
+
+CREATE TABLE IF NOT EXISTS suppliers (
+    id                TEXT PRIMARY KEY,
+    name              TEXT    NOT NULL,
+    phone             TEXT    NOT NULL DEFAULT '',
+    email             TEXT    NOT NULL DEFAULT '',
+    address           TEXT    NOT NULL DEFAULT '',
+    payment_term_days INTEGER,
+    balance           INTEGER NOT NULL DEFAULT 0,
+    created_at        DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS supplier_invoices (
+    id               TEXT PRIMARY KEY,
+    supplier_id      TEXT    NOT NULL REFERENCES suppliers(id),
+    supplier_name    TEXT    NOT NULL,
+    number           TEXT    NOT NULL,
+    amount           INTEGER NOT NULL CHECK(amount > 0),
+    remaining_amount INTEGER NOT NULL,
+    status           TEXT    NOT NULL DEFAULT 'en_cours' CHECK(status IN ('en_cours','en_retard','paye')),
+    date             DATETIME NOT NULL,
+    due_date         DATETIME,
+    created_at       DATETIME NOT NULL,
+    UNIQUE (supplier_id, number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_supplier_invoices_status ON supplier_invoices(status);
+
+CREATE TABLE IF NOT EXISTS receptions (
+    id            TEXT PRIMARY KEY,
+    supplier_id   TEXT    NOT NULL REFERENCES suppliers(id),
+    supplier_name TEXT    NOT NULL,
+    invoice_id    TEXT    REFERENCES supplier_invoices(id),
+    total         INTEGER NOT NULL,
+    note          TEXT    NOT NULL DEFAULT '',
+    user_id       TEXT    NOT NULL DEFAULT '',
+    user_name     TEXT    NOT NULL DEFAULT '',
+    date          DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_receptions_supplier ON receptions(supplier_id, date);
+CREATE INDEX IF NOT EXISTS idx_receptions_invoice ON receptions(invoice_id);
+
+-- A line is either a catalog product or a carcass.
+CREATE TABLE IF NOT EXISTS reception_items (
+    id           TEXT PRIMARY KEY,
+    reception_id TEXT    NOT NULL REFERENCES receptions(id) ON DELETE CASCADE,
+    product_id   TEXT    NOT NULL DEFAULT '',
+    carcass_id   TEXT    NOT NULL DEFAULT '',
+    name         TEXT    NOT NULL,
+    quantity     REAL    NOT NULL CHECK(quantity > 0),
+    price_per_kg INTEGER NOT NULL CHECK(price_per_kg > 0),
+    subtotal     INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_reception_items_reception ON reception_items(reception_id);
+
+CREATE TABLE IF NOT EXISTS supplier_payments (
+    id         TEXT PRIMARY KEY,
+    invoice_id TEXT    NOT NULL REFERENCES supplier_invoices(id) ON DELETE CASCADE,
+    receipt_id TEXT    NOT NULL,
+    amount     INTEGER NOT NULL CHECK(amount > 0),
+    date       DATETIME NOT NULL,
+    method     TEXT    NOT NULL DEFAULT 'cash' CHECK(method IN ('cash','carte','virement')),
+    user_id    TEXT    NOT NULL DEFAULT '',
+    user_name  TEXT    NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_supplier_payments_invoice ON supplier_payments(invoice_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_payments_receipt ON supplier_payments(receipt_id);
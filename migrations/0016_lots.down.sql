@@ -0,0 +1,9 @@
+ALTER TABLE carcass_cuts DROP COLUMN lot_id;
+ALTER TABLE reception_items DROP COLUMN lot_id;
+DROP INDEX IF EXISTS idx_sale_item_lots_lot;
+DROP TABLE IF EXISTS sale_item_lots;
+DROP INDEX IF EXISTS idx_lots_number;
+DROP INDEX IF EXISTS idx_lots_parent;
+DROP INDEX IF EXISTS idx_lots_carcass;
+DROP INDEX IF EXISTS idx_lots_product;
+DROP TABLE IF EXISTS lots;
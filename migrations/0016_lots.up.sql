@@ -0,0 +1,38 @@
+-- Lot traceability: every reception line and carcass cut gets a lot, and
+-- sale lines record the lots they were taken from.
+
+CREATE TABLE IF NOT EXISTS lots (
+    id              TEXT PRIMARY KEY,
+    number          TEXT NOT NULL DEFAULT '',  -- supplier's lot number
+    product_id      TEXT NOT NULL DEFAULT '',  -- empty for a carcass lot
+    carcass_id      TEXT NOT NULL DEFAULT '',
+    parent_id       TEXT NOT NULL DEFAULT '',  -- carcass lot a cut lot comes from
+    reception_id    TEXT NOT NULL DEFAULT '',
+    supplier_id     TEXT NOT NULL DEFAULT '',
+    supplier_name   TEXT NOT NULL DEFAULT '',
+    origin          TEXT NOT NULL DEFAULT '',
+    slaughter_date  TEXT NOT NULL DEFAULT '',  -- YYYY-MM-DD
+    approval_number TEXT NOT NULL DEFAULT '',  -- slaughterhouse approval (agrément)
+    animal_id       TEXT NOT NULL DEFAULT '',  -- ear tag
+    quantity        REAL NOT NULL CHECK(quantity >= 0),
+    remaining       REAL NOT NULL CHECK(remaining >= 0),
+    received_at     DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_lots_product ON lots(product_id, received_at);
+CREATE INDEX IF NOT EXISTS idx_lots_carcass ON lots(carcass_id);
+CREATE INDEX IF NOT EXISTS idx_lots_parent ON lots(parent_id);
+CREATE INDEX IF NOT EXISTS idx_lots_number ON lots(number);
+
+CREATE TABLE IF NOT EXISTS sale_item_lots (
+    sale_item_id TEXT NOT NULL REFERENCES sale_items(id) ON DELETE CASCADE,
+    lot_id       TEXT NOT NULL REFERENCES lots(id),
+    quantity     REAL NOT NULL CHECK(quantity > 0),
+    PRIMARY KEY (sale_item_id, lot_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sale_item_lots_lot ON sale_item_lots(lot_id);
+
+-- Lot given to each reception line and carcass cut.
+ALTER TABLE reception_items ADD COLUMN lot_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE carcass_cuts ADD COLUMN lot_id TEXT NOT NULL DEFAULT '';
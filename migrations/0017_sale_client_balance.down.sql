@@ -0,0 +1 @@
+ALTER TABLE sales DROP COLUMN client_balance;
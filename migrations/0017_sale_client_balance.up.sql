@@ -0,0 +1,4 @@
+-- Client balance right after each sale, printed on its receipt. Sales
+-- recorded before are left NULL.
+
+ALTER TABLE sales ADD COLUMN client_balance INTEGER;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_carcasses_supplier;
+ALTER TABLE carcasses DROP COLUMN supplier_id;
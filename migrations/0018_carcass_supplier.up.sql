@@ -0,0 +1,10 @@
+-- Link carcasses to the supplier they were received from; the supplier
+-- column keeps the name for carcasses registered by hand.
+ALTER TABLE carcasses ADD COLUMN supplier_id TEXT NOT NULL DEFAULT '';
+
+UPDATE carcasses SET supplier_id = COALESCE((
+    SELECT r.supplier_id FROM reception_items ri JOIN receptions r ON r.id = ri.reception_id
+    WHERE ri.carcass_id = carcasses.id
+), '');
+
+CREATE INDEX IF NOT EXISTS idx_carcasses_supplier ON carcasses(supplier_id) WHERE supplier_id <> '';
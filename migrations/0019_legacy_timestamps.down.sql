@@ -0,0 +1,3 @@
+-- The original text of the rewritten timestamps is not kept; every version
+-- reads the SQLite format, so there is nothing to revert.
+SELECT 1;
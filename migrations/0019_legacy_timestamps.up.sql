@@ -0,0 +1,22 @@
+-- Rows written before the driver stored times in the SQLite format hold Go's
+-- time.Time.String() output ("2026-02-16 14:04:14.764241 +0100 WAT m=+19.8"),
+-- which date() and datetime() cannot read. Rewrite them as UTC
+-- "YYYY-MM-DD HH:MM:SS", the format of CURRENT_TIMESTAMP. Only the tables of
+-- the initial schema can hold such rows.
+
+UPDATE clients SET created_at = datetime(substr(created_at, 1, 19) || substr(created_at, 20 + instr(substr(created_at, 20), ' '), 3) || ':' || substr(created_at, 23 + instr(substr(created_at, 20), ' '), 2))
+    WHERE created_at GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9] [0-9][0-9]:[0-9][0-9]:[0-9][0-9]* [+-][0-9][0-9][0-9][0-9] *';
+UPDATE sales SET date = datetime(substr(date, 1, 19) || substr(date, 20 + instr(substr(date, 20), ' '), 3) || ':' || substr(date, 23 + instr(substr(date, 20), ' '), 2))
+    WHERE date GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9] [0-9][0-9]:[0-9][0-9]:[0-9][0-9]* [+-][0-9][0-9][0-9][0-9] *';
+UPDATE credits SET created_at = datetime(substr(created_at, 1, 19) || substr(created_at, 20 + instr(substr(created_at, 20), ' '), 3) || ':' || substr(created_at, 23 + instr(substr(created_at, 20), ' '), 2))
+    WHERE created_at GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9] [0-9][0-9]:[0-9][0-9]:[0-9][0-9]* [+-][0-9][0-9][0-9][0-9] *';
+UPDATE credits SET due_date = datetime(substr(due_date, 1, 19) || substr(due_date, 20 + instr(substr(due_date, 20), ' '), 3) || ':' || substr(due_date, 23 + instr(substr(due_date, 20), ' '), 2))
+    WHERE due_date GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9] [0-9][0-9]:[0-9][0-9]:[0-9][0-9]* [+-][0-9][0-9][0-9][0-9] *';
+UPDATE payments SET date = datetime(substr(date, 1, 19) || substr(date, 20 + instr(substr(date, 20), ' '), 3) || ':' || substr(date, 23 + instr(substr(date, 20), ' '), 2))
+    WHERE date GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9] [0-9][0-9]:[0-9][0-9]:[0-9][0-9]* [+-][0-9][0-9][0-9][0-9] *';
+UPDATE orders SET created_at = datetime(substr(created_at, 1, 19) || substr(created_at, 20 + instr(substr(created_at, 20), ' '), 3) || ':' || substr(created_at, 23 + instr(substr(created_at, 20), ' '), 2))
+    WHERE created_at GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9] [0-9][0-9]:[0-9][0-9]:[0-9][0-9]* [+-][0-9][0-9][0-9][0-9] *';
+
+-- Pickup dates are calendar days, stored as midnight UTC of the day
+UPDATE orders SET pickup_date = substr(pickup_date, 1, 10) || ' 00:00:00'
+    WHERE pickup_date GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9] [0-9][0-9]:[0-9][0-9]:[0-9][0-9]* [+-][0-9][0-9][0-9][0-9] *';
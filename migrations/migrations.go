@@ -0,0 +1,12 @@
+// Package migrations embeds the SQL migration scripts and seed data so the
+// binary does not depend on its working directory.
+//
+// Scripts are named NNNN_description.up.sql and NNNN_description.down.sql.
+package migrations
+
+import "embed"
+
+// FS holds every *.sql file of this directory.
+//
+//go:embed *.sql
+var FS embed.FS